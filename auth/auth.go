@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+var ErrUnauthenticated = errors.New("missing or invalid credentials")
+
+type principalKey struct{}
+
+// Authenticator resolves the calling principal from a static set of bearer
+// tokens. With no tokens configured every call is let through anonymously.
+type Authenticator struct {
+	tokens map[string]string
+}
+
+func NewAuthenticator(tokens map[string]string) *Authenticator {
+	return &Authenticator{tokens: tokens}
+}
+
+func (a *Authenticator) Enabled() bool {
+	return len(a.tokens) > 0
+}
+
+func (a *Authenticator) Authenticate(ctx context.Context) (string, error) {
+	if !a.Enabled() {
+		return "", nil
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", ErrUnauthenticated
+	}
+
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return "", ErrUnauthenticated
+	}
+
+	token, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok {
+		return "", ErrUnauthenticated
+	}
+
+	principal, ok := a.tokens[strings.TrimSpace(token)]
+	if !ok {
+		return "", ErrUnauthenticated
+	}
+
+	return principal, nil
+}
+
+func NewContext(ctx context.Context, principal string) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+func FromContext(ctx context.Context) string {
+	principal, _ := ctx.Value(principalKey{}).(string)
+	return principal
+}
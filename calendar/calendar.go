@@ -0,0 +1,100 @@
+// Package calendar serves each principal's tasks as an iCalendar feed that
+// calendar apps can subscribe to. Apps cannot send bearer tokens, so the feed
+// URL itself carries a secret token.
+package calendar
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"grpc-todo/ical"
+	"grpc-todo/logging"
+	"grpc-todo/repository"
+)
+
+// Signer issues and checks feed tokens. A token names its principal and
+// carries an HMAC of it, so nothing has to be stored; changing the secret
+// revokes every feed URL at once.
+type Signer struct {
+	key []byte
+}
+
+func NewSigner(secret string) *Signer {
+	return &Signer{key: []byte(secret)}
+}
+
+// Token returns the feed token of a principal.
+func (s *Signer) Token(principal string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(principal)) + "." + s.mac(principal)
+}
+
+// Principal returns the principal a token was issued to.
+func (s *Signer) Principal(token string) (string, bool) {
+	encoded, mac, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", false
+	}
+	principal, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", false
+	}
+	if !hmac.Equal([]byte(mac), []byte(s.mac(string(principal)))) {
+		return "", false
+	}
+	return string(principal), true
+}
+
+func (s *Signer) mac(principal string) string {
+	h := hmac.New(sha256.New, s.key)
+	h.Write([]byte("calendar-feed:" + principal))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+// FeedPath is the path of a principal's feed on the HTTP port.
+func (s *Signer) FeedPath(principal string) string {
+	return "/calendar/" + s.Token(principal) + ".ics"
+}
+
+// Handler serves GET /calendar/{token}.ics with the tasks owned by the
+// token's principal. Unknown tokens get 404 so that feeds cannot be probed.
+func Handler(repo repository.Repository, signer *Signer) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /calendar/{file}", func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutSuffix(r.PathValue("file"), ".ics")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		principal, ok := signer.Principal(token)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		tasks, err := repo.GetTasksByOwner(ctx, principal)
+		if err != nil {
+			logging.FromContext(ctx).Error("failed to build calendar feed", slog.String("error", err.Error()))
+			http.Error(w, "failed to load tasks", http.StatusInternalServerError)
+			return
+		}
+
+		var buf bytes.Buffer
+		if err := ical.Encode(&buf, tasks, time.Now()); err != nil {
+			http.Error(w, "failed to encode calendar", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Header().Set("Content-Disposition", `inline; filename="tasks.ics"`)
+		w.Header().Set("Cache-Control", "private, no-cache")
+		w.Write(buf.Bytes())
+	})
+	return mux
+}
@@ -0,0 +1,77 @@
+package calendar
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"grpc-todo/domain"
+	"grpc-todo/repository"
+)
+
+type fakeRepository struct {
+	repository.Repository
+	tasks []*domain.Task
+}
+
+func (f *fakeRepository) GetTasksByOwner(ctx context.Context, owner string) ([]*domain.Task, error) {
+	var tasks []*domain.Task
+	for _, t := range f.tasks {
+		if t.Owner == owner {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks, nil
+}
+
+func TestSigner(t *testing.T) {
+	signer := NewSigner("secret")
+
+	for _, principal := range []string{"alice", ""} {
+		got, ok := signer.Principal(signer.Token(principal))
+		if !ok || got != principal {
+			t.Errorf("Expected token to name %q, got %q (%v)", principal, got, ok)
+		}
+	}
+
+	forged := strings.Replace(signer.Token("alice"), "YWxpY2U", "Ym9i", 1)
+	if _, ok := signer.Principal(forged); ok {
+		t.Errorf("Expected a token with a swapped principal to be rejected")
+	}
+	if _, ok := NewSigner("other").Principal(signer.Token("alice")); ok {
+		t.Errorf("Expected a token signed with another secret to be rejected")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	signer := NewSigner("secret")
+	repo := &fakeRepository{tasks: []*domain.Task{
+		{Id: "5f1d7e4b9c2a3b0012345678", Title: "Alice's task", Status: "TODO", Owner: "alice"},
+		{Id: "5f1d7e4b9c2a3b0012345679", Title: "Bob's task", Status: "TODO", Owner: "bob"},
+	}}
+	handler := Handler(repo, signer)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, signer.FeedPath("alice"), nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/calendar") {
+		t.Errorf("Expected a calendar content type, got %q", got)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, "SUMMARY:Alice's task") || strings.Contains(body, "Bob") {
+		t.Errorf("Expected only Alice's tasks in the feed, got\n%s", body)
+	}
+
+	for _, path := range []string{"/calendar/nope.ics", "/calendar/" + signer.Token("alice")} {
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected 404 for %s, got %d", path, rec.Code)
+		}
+	}
+}
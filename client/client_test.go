@@ -0,0 +1,51 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"grpc-todo/proto"
+)
+
+func TestParseStatus(t *testing.T) {
+	tests := map[string]proto.Status{
+		"done":        proto.Status_DONE,
+		"in-progress": proto.Status_IN_PROGRESS,
+		"PAUSED":      proto.Status_PAUSED,
+	}
+	for input, want := range tests {
+		got, err := ParseStatus(input)
+		if err != nil || got != want {
+			t.Errorf("ParseStatus(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+
+	for _, input := range []string{"unknown", "finished"} {
+		if _, err := ParseStatus(input); err == nil {
+			t.Errorf("Expected error for %q", input)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := "server: todo.example.com:443\ntoken: secret\ntls:\n  enabled: true\n  server_name: todo.example.com\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	if cfg.Server != "todo.example.com:443" || cfg.Token != "secret" || !cfg.TLS.Enabled || cfg.TLS.ServerName != "todo.example.com" {
+		t.Errorf("Unexpected config: %+v", cfg)
+	}
+
+	cfg, err = LoadConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil || cfg.Server != DefaultServer {
+		t.Errorf("Expected defaults for a missing file, got %+v, %v", cfg, err)
+	}
+}
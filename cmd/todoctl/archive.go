@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"grpc-todo/proto"
+
+	"github.com/spf13/cobra"
+)
+
+func newArchiveCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "archive <id>...",
+		Short: "Move tasks to the archive",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := a.callContext(cmd)
+			defer cancel()
+
+			for _, id := range args {
+				if _, err := a.client.ArchiveTask(ctx, &proto.ArchiveTaskRequest{Id: id}); err != nil {
+					return fmt.Errorf("failed to archive %s: %w", id, err)
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "archived %s\n", id)
+			}
+			return nil
+		},
+	}
+}
+
+func newRestoreCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "restore <id>...",
+		Short: "Move archived tasks back",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := a.callContext(cmd)
+			defer cancel()
+
+			for _, id := range args {
+				if _, err := a.client.RestoreTask(ctx, &proto.RestoreTaskRequest{Id: id}); err != nil {
+					return fmt.Errorf("failed to restore %s: %w", id, err)
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "restored %s\n", id)
+			}
+			return nil
+		},
+	}
+}
+
+// listArchived reads every page of the archive.
+func listArchived(ctx context.Context, c proto.ToDoServiceClient) ([]*proto.Task, error) {
+	return allPages(func(token string) ([]*proto.Task, string, error) {
+		res, err := c.ListArchivedTasks(ctx, &proto.ListArchivedTasksRequest{PageSize: 1000, PageToken: token})
+		return res.GetTasks(), res.GetNextPageToken(), err
+	})
+}
+
+// allPages calls list with each next page token until the last page.
+func allPages[T any](list func(token string) ([]T, string, error)) ([]T, error) {
+	var tasks []T
+	token := ""
+	for {
+		page, next, err := list(token)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, page...)
+		if next == "" {
+			return tasks, nil
+		}
+		token = next
+	}
+}
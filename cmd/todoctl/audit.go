@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+
+	"grpc-todo/proto"
+
+	"github.com/spf13/cobra"
+)
+
+func newAuditCmd(a *app) *cobra.Command {
+	var taskID, actor, since, until string
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Show who changed which tasks",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			req := &proto.ListAuditEntriesRequest{TaskId: taskID, Actor: actor, PageSize: 1000}
+			var err error
+			if req.StartTime, err = parseDue(since); err != nil {
+				return fmt.Errorf("invalid --since %q, expected YYYY-MM-DD or RFC 3339", since)
+			}
+			if req.EndTime, err = parseDue(until); err != nil {
+				return fmt.Errorf("invalid --until %q, expected YYYY-MM-DD or RFC 3339", until)
+			}
+
+			ctx, cancel := a.callContext(cmd)
+			defer cancel()
+
+			entries, err := allPages(func(token string) ([]*proto.AuditEntry, string, error) {
+				req.PageToken = token
+				res, err := a.client.ListAuditEntries(ctx, req)
+				return res.GetEntries(), res.GetNextPageToken(), err
+			})
+			if err != nil {
+				return err
+			}
+			return a.printer.AuditEntries(entries)
+		},
+	}
+	cmd.Flags().StringVar(&taskID, "task", "", "only show changes to this task")
+	cmd.Flags().StringVar(&actor, "actor", "", "only show changes made by this principal")
+	cmd.Flags().StringVar(&since, "since", "", "only show changes at or after this time (YYYY-MM-DD or RFC 3339)")
+	cmd.Flags().StringVar(&until, "until", "", "only show changes at or before this time (YYYY-MM-DD or RFC 3339)")
+	return cmd
+}
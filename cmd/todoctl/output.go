@@ -0,0 +1,281 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"grpc-todo/proto"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	protobuf "google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+type printer interface {
+	Task(t *proto.Task) error
+	Tasks(tasks []*proto.Task) error
+	Event(e event) error
+	Webhooks(webhooks []*proto.Webhook) error
+	AuditEntries(entries []*proto.AuditEntry) error
+	History(h *proto.GetTaskHistoryResponse) error
+	SearchResults(results []*proto.SearchResult) error
+	Views(views []*proto.SavedView) error
+	Stats(s *proto.GetTaskStatsResponse) error
+}
+
+func newPrinter(format string, w io.Writer) (printer, error) {
+	switch format {
+	case "table":
+		return &tablePrinter{w: w}, nil
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return &structuredPrinter{encode: enc.Encode}, nil
+	case "yaml":
+		// A single encoder separates successive watch events with "---".
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		return &structuredPrinter{encode: enc.Encode}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q, expected table, json or yaml", format)
+	}
+}
+
+type tablePrinter struct {
+	w io.Writer
+}
+
+func (p *tablePrinter) Task(t *proto.Task) error {
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "ID:\t%s\n", t.Id)
+	fmt.Fprintf(tw, "Title:\t%s\n", t.Title)
+	fmt.Fprintf(tw, "Description:\t%s\n", t.Description)
+	fmt.Fprintf(tw, "Status:\t%s\n", t.Status)
+	fmt.Fprintf(tw, "Created:\t%s\n", formatTime(t.CreatedAt))
+	fmt.Fprintf(tw, "Due:\t%s\n", formatTime(t.DueAt))
+	return tw.Flush()
+}
+
+func (p *tablePrinter) Tasks(tasks []*proto.Task) error {
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSTATUS\tTITLE\tCREATED\tDUE")
+	for _, t := range tasks {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", t.Id, t.Status, t.Title, formatTime(t.CreatedAt), formatTime(t.DueAt))
+	}
+	return tw.Flush()
+}
+
+func (p *tablePrinter) Webhooks(webhooks []*proto.Webhook) error {
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tURL\tEVENTS\tCREATED")
+	for _, w := range webhooks {
+		events := "all"
+		if len(w.Events) > 0 {
+			names := make([]string, len(w.Events))
+			for i, e := range w.Events {
+				names[i] = webhookEventName(e)
+			}
+			events = strings.Join(names, ",")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", w.Id, w.Url, events, formatTime(w.CreatedAt))
+	}
+	return tw.Flush()
+}
+
+func (p *tablePrinter) AuditEntries(entries []*proto.AuditEntry) error {
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tTASK\tACTOR\tMETHOD\tCHANGES")
+	for _, e := range entries {
+		changes := make([]string, len(e.Changes))
+		for i, c := range e.Changes {
+			changes[i] = fmt.Sprintf("%s: %q -> %q", c.Field, c.Before, c.After)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", formatTime(e.Timestamp), e.TaskId, e.Actor, e.Method, strings.Join(changes, ", "))
+	}
+	return tw.Flush()
+}
+
+func (p *tablePrinter) History(h *proto.GetTaskHistoryResponse) error {
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tFROM\tTO\tBY")
+	for _, t := range h.Transitions {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", formatTime(t.ChangedAt), t.From, t.To, t.ChangedBy)
+	}
+	fmt.Fprintln(tw)
+	for _, d := range h.Durations {
+		fmt.Fprintf(tw, "%s:\t%s\n", d.Status, time.Duration(d.Seconds)*time.Second)
+	}
+	if h.CycleTimeSeconds > 0 {
+		fmt.Fprintf(tw, "Cycle time:\t%s\n", time.Duration(h.CycleTimeSeconds)*time.Second)
+	}
+	return tw.Flush()
+}
+
+func (p *tablePrinter) SearchResults(results []*proto.SearchResult) error {
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSTATUS\tSCORE\tTITLE\tMATCH")
+	for _, r := range results {
+		match := "-"
+		for _, s := range r.Snippets {
+			if s.Field == "description" {
+				match = highlight(s)
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%.2f\t%s\t%s\n", r.Task.Id, r.Task.Status, r.Score, r.Task.Title, match)
+	}
+	return tw.Flush()
+}
+
+func (p *tablePrinter) Views(views []*proto.SavedView) error {
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tOWNER\tSHARED\tFILTER\tORDER BY")
+	for _, v := range views {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%t\t%s\t%s\n", v.Id, v.Name, v.Owner, v.Shared, cmp.Or(v.Filter, "-"), cmp.Or(v.OrderBy, "-"))
+	}
+	return tw.Flush()
+}
+
+func (p *tablePrinter) Stats(s *proto.GetTaskStatsResponse) error {
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	for _, c := range s.StatusCounts {
+		fmt.Fprintf(tw, "%s:\t%d\n", c.Status, c.Count)
+	}
+	if s.MedianTimeToDoneSeconds > 0 {
+		fmt.Fprintf(tw, "Median time to done:\t%s\n", time.Duration(s.MedianTimeToDoneSeconds)*time.Second)
+	}
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "FROM\tCREATED\tCOMPLETED")
+	for _, b := range s.Buckets {
+		fmt.Fprintf(tw, "%s\t%d\t%d\n", formatTime(b.StartTime), b.Created, b.Completed)
+	}
+	if len(s.OldestOpen) > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "OLDEST OPEN\tSTATUS\tTITLE\tCREATED")
+		for _, t := range s.OldestOpen {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", t.Id, t.Status, t.Title, formatTime(t.CreatedAt))
+		}
+	}
+	return tw.Flush()
+}
+
+// highlight marks the matching words of a snippet with asterisks.
+func highlight(s *proto.Snippet) string {
+	runes := []rune(s.Text)
+	var b strings.Builder
+	last := 0
+	for _, h := range s.Highlights {
+		b.WriteString(string(runes[last:h.Start]))
+		b.WriteString("*" + string(runes[h.Start:h.End]) + "*")
+		last = int(h.End)
+	}
+	b.WriteString(string(runes[last:]))
+	return b.String()
+}
+
+func (p *tablePrinter) Event(e event) error {
+	_, err := fmt.Fprintf(p.w, "%s %s %s %s\n", e.Type, e.Task.Id, e.Task.Status, e.Task.Title)
+	return err
+}
+
+func formatTime(unix int64) string {
+	if unix == 0 {
+		return "-"
+	}
+	return time.Unix(unix, 0).Format(time.RFC3339)
+}
+
+// structuredPrinter renders tasks with the protobuf JSON mapping, so JSON
+// and YAML output use the same field names as the REST API.
+type structuredPrinter struct {
+	encode func(v any) error
+}
+
+func (p *structuredPrinter) Task(t *proto.Task) error {
+	v, err := toPlain(t)
+	if err != nil {
+		return err
+	}
+	return p.encode(v)
+}
+
+func (p *structuredPrinter) Tasks(tasks []*proto.Task) error {
+	v, err := toPlain(&proto.GetAllTasksResponse{Tasks: tasks})
+	if err != nil {
+		return err
+	}
+	return p.encode(v)
+}
+
+func (p *structuredPrinter) Webhooks(webhooks []*proto.Webhook) error {
+	v, err := toPlain(&proto.ListWebhooksResponse{Webhooks: webhooks})
+	if err != nil {
+		return err
+	}
+	return p.encode(v)
+}
+
+func (p *structuredPrinter) AuditEntries(entries []*proto.AuditEntry) error {
+	v, err := toPlain(&proto.ListAuditEntriesResponse{Entries: entries})
+	if err != nil {
+		return err
+	}
+	return p.encode(v)
+}
+
+func (p *structuredPrinter) History(h *proto.GetTaskHistoryResponse) error {
+	v, err := toPlain(h)
+	if err != nil {
+		return err
+	}
+	return p.encode(v)
+}
+
+func (p *structuredPrinter) SearchResults(results []*proto.SearchResult) error {
+	v, err := toPlain(&proto.SearchTasksResponse{Results: results})
+	if err != nil {
+		return err
+	}
+	return p.encode(v)
+}
+
+func (p *structuredPrinter) Views(views []*proto.SavedView) error {
+	v, err := toPlain(&proto.ListViewsResponse{Views: views})
+	if err != nil {
+		return err
+	}
+	return p.encode(v)
+}
+
+func (p *structuredPrinter) Stats(s *proto.GetTaskStatsResponse) error {
+	v, err := toPlain(s)
+	if err != nil {
+		return err
+	}
+	return p.encode(v)
+}
+
+func (p *structuredPrinter) Event(e event) error {
+	task, err := toPlain(e.Task)
+	if err != nil {
+		return err
+	}
+	return p.encode(map[string]any{"type": e.Type, "task": task})
+}
+
+func toPlain(m protobuf.Message) (any, error) {
+	data, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
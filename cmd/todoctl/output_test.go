@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"grpc-todo/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestJSONPrinter(t *testing.T) {
+	var buf bytes.Buffer
+	p, err := newPrinter("json", &buf)
+	if err != nil {
+		t.Fatalf("newPrinter failed: %v", err)
+	}
+
+	err = p.Tasks([]*proto.Task{{Id: "1", Title: "Task 1", Status: proto.Status_IN_PROGRESS, CreatedAt: 1700000000}})
+	if err != nil {
+		t.Fatalf("Tasks failed: %v", err)
+	}
+
+	var out struct {
+		Tasks []struct {
+			ID        string `json:"id"`
+			Status    string `json:"status"`
+			CreatedAt string `json:"createdAt"`
+		} `json:"tasks"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("Failed to decode output %q: %v", buf.String(), err)
+	}
+
+	if len(out.Tasks) != 1 || out.Tasks[0].Status != "IN_PROGRESS" || out.Tasks[0].CreatedAt != "1700000000" {
+		t.Errorf("Unexpected output: %s", buf.String())
+	}
+}
+
+func TestYAMLPrinterEvents(t *testing.T) {
+	var buf bytes.Buffer
+	p, err := newPrinter("yaml", &buf)
+	if err != nil {
+		t.Fatalf("newPrinter failed: %v", err)
+	}
+
+	for _, e := range []event{
+		{Type: eventCreated, Task: &proto.Task{Id: "1", Title: "Task 1"}},
+		{Type: eventDeleted, Task: &proto.Task{Id: "2", Title: "Task 2"}},
+	} {
+		if err := p.Event(e); err != nil {
+			t.Fatalf("Event failed: %v", err)
+		}
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "type: CREATED") || !strings.Contains(out, "---\n") || !strings.Contains(out, "type: DELETED") {
+		t.Errorf("Expected two YAML documents, got:\n%s", out)
+	}
+}
+
+func TestDiffTasks(t *testing.T) {
+	previous := map[string]*proto.Task{
+		"1": {Id: "1", Status: proto.Status_TODO},
+		"2": {Id: "2", Status: proto.Status_TODO},
+	}
+	current := map[string]*proto.Task{
+		"1": {Id: "1", Status: proto.Status_DONE},
+		"3": {Id: "3", Status: proto.Status_TODO},
+	}
+
+	var got []string
+	for _, e := range diffTasks(previous, current) {
+		got = append(got, e.Task.Id+" "+string(e.Type))
+	}
+
+	// Changes come in task ID order, deletions last.
+	want := []string{"1 UPDATED", "3 CREATED", "2 DELETED"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected events %v, got %v", want, got)
+	}
+}
+
+// pollClient answers GetAllTasks with one reply per call and, once they
+// run out, calls done.
+type pollClient struct {
+	proto.ToDoServiceClient
+	replies []error
+	tasks   [][]*proto.Task
+	done    func()
+}
+
+func (c *pollClient) GetAllTasks(ctx context.Context, in *proto.GetAllTasksRequest, opts ...grpc.CallOption) (*proto.GetAllTasksResponse, error) {
+	if len(c.replies) == 0 {
+		c.done()
+		return nil, ctx.Err()
+	}
+	err, tasks := c.replies[0], c.tasks[0]
+	c.replies, c.tasks = c.replies[1:], c.tasks[1:]
+	if err != nil {
+		return nil, err
+	}
+	return &proto.GetAllTasksResponse{Tasks: tasks}, nil
+}
+
+func TestWatchRetriesFailedPolls(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	p, err := newPrinter("json", &stdout)
+	if err != nil {
+		t.Fatalf("newPrinter failed: %v", err)
+	}
+	client := &pollClient{
+		replies: []error{nil, status.Error(codes.Unavailable, "connection refused"), nil, nil},
+		tasks: [][]*proto.Task{
+			{{Id: "1", Title: "Task 1"}},
+			nil,
+			{{Id: "1", Title: "Task 1"}, {Id: "2", Title: "Task 2"}},
+			{{Id: "2", Title: "Task 2"}},
+		},
+		done: cancel,
+	}
+	a := &app{opts: &options{timeout: time.Second}, client: client, printer: p}
+
+	if err := watch(ctx, a, time.Millisecond, &stderr); err != nil {
+		t.Fatalf("Expected watch to outlive a failed poll, got %v", err)
+	}
+	if !strings.Contains(stderr.String(), "connection refused") {
+		t.Errorf("Expected the failed poll on stderr, got %q", stderr.String())
+	}
+	out := stdout.String()
+	if !strings.Contains(out, `"CREATED"`) || !strings.Contains(out, `"DELETED"`) {
+		t.Errorf("Expected the changes around the failed poll, got:\n%s", out)
+	}
+
+	client.replies = []error{status.Error(codes.Unauthenticated, "bad token")}
+	client.tasks = [][]*proto.Task{nil}
+	client.done = func() {}
+	if err := watch(context.Background(), a, time.Millisecond, &stderr); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected watch to stop on Unauthenticated, got %v", err)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	s := &proto.Snippet{Text: "…the café deploy", Highlights: []*proto.TextRange{{Start: 5, End: 9}, {Start: 10, End: 16}}}
+	if got, want := highlight(s), "…the *café* *deploy*"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"grpc-todo/client"
+	"grpc-todo/proto"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+)
+
+type options struct {
+	configPath string
+	output     string
+	timeout    time.Duration
+
+	server string
+	token  string
+
+	tls           bool
+	tlsCA         string
+	tlsCert       string
+	tlsKey        string
+	tlsServerName string
+	tlsInsecure   bool
+}
+
+// app is the state shared by the subcommands once flags are parsed.
+type app struct {
+	opts    *options
+	conn    *grpc.ClientConn
+	client  proto.ToDoServiceClient
+	printer printer
+}
+
+func newRootCmd() *cobra.Command {
+	opts := &options{}
+	a := &app{opts: opts}
+
+	cmd := &cobra.Command{
+		Use:           "todoctl",
+		Short:         "Command-line client for the ToDoService",
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			if cmd.Name() == "help" || cmd.HasParent() && cmd.Parent().Name() == "completion" {
+				return nil
+			}
+			return a.connect(cmd)
+		},
+		PersistentPostRun: func(*cobra.Command, []string) {
+			if a.conn != nil {
+				a.conn.Close()
+			}
+		},
+	}
+
+	flags := cmd.PersistentFlags()
+	flags.StringVar(&opts.configPath, "config", client.DefaultConfigPath(), "path to the config file")
+	flags.StringVarP(&opts.output, "output", "o", "table", "output format: table, json or yaml")
+	flags.DurationVar(&opts.timeout, "timeout", 10*time.Second, "timeout for each call")
+	flags.StringVarP(&opts.server, "server", "s", "", "server address (default from config, then "+client.DefaultServer+")")
+	flags.StringVar(&opts.token, "token", "", "bearer token sent with every call")
+	flags.BoolVar(&opts.tls, "tls", false, "connect using TLS")
+	flags.StringVar(&opts.tlsCA, "tls-ca", "", "CA certificate used to verify the server")
+	flags.StringVar(&opts.tlsCert, "tls-cert", "", "client certificate for mutual TLS")
+	flags.StringVar(&opts.tlsKey, "tls-key", "", "client key for mutual TLS")
+	flags.StringVar(&opts.tlsServerName, "tls-server-name", "", "override the server name used to verify the certificate")
+	flags.BoolVar(&opts.tlsInsecure, "tls-insecure-skip-verify", false, "do not verify the server certificate")
+
+	_ = cmd.RegisterFlagCompletionFunc("output", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return []string{"table", "json", "yaml"}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	cmd.AddCommand(
+		newCreateCmd(a),
+		newListCmd(a),
+		newSearchCmd(a),
+		newViewCmd(a),
+		newGetCmd(a),
+		newSetStatusCmd(a),
+		newHistoryCmd(a),
+		newStatsCmd(a),
+		newDeleteCmd(a),
+		newArchiveCmd(a),
+		newRestoreCmd(a),
+		newTrashCmd(a),
+		newAuditCmd(a),
+		newWatchCmd(a),
+		newExportCmd(a),
+		newImportCmd(a),
+		newCalendarCmd(a),
+		newWebhookCmd(a),
+	)
+
+	return cmd
+}
+
+func (a *app) connect(cmd *cobra.Command) error {
+	printer, err := newPrinter(a.opts.output, cmd.OutOrStdout())
+	if err != nil {
+		return err
+	}
+	a.printer = printer
+
+	cfg, err := client.LoadConfig(a.opts.configPath)
+	if err != nil {
+		return err
+	}
+	a.applyFlags(cmd, cfg)
+
+	conn, err := client.Dial(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %v", cfg.Server, err)
+	}
+	a.conn = conn
+	a.client = proto.NewToDoServiceClient(conn)
+
+	return nil
+}
+
+// applyFlags lets flags given on the command line override the config file.
+func (a *app) applyFlags(cmd *cobra.Command, cfg *client.Config) {
+	changed := cmd.Flags().Changed
+	if changed("server") {
+		cfg.Server = a.opts.server
+	}
+	if changed("token") {
+		cfg.Token = a.opts.token
+	}
+	if changed("tls") {
+		cfg.TLS.Enabled = a.opts.tls
+	}
+	if changed("tls-ca") {
+		cfg.TLS.CAFile = a.opts.tlsCA
+	}
+	if changed("tls-cert") {
+		cfg.TLS.CertFile = a.opts.tlsCert
+	}
+	if changed("tls-key") {
+		cfg.TLS.KeyFile = a.opts.tlsKey
+	}
+	if changed("tls-server-name") {
+		cfg.TLS.ServerName = a.opts.tlsServerName
+	}
+	if changed("tls-insecure-skip-verify") {
+		cfg.TLS.InsecureSkipVerify = a.opts.tlsInsecure
+	}
+}
+
+func (a *app) callContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(cmd.Context(), a.opts.timeout)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"grpc-todo/proto"
+
+	"github.com/spf13/cobra"
+)
+
+func newStatsCmd(a *app) *cobra.Command {
+	var since, until string
+	var bucket time.Duration
+	var oldest int32
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show task counts, throughput and the oldest open tasks",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			req := &proto.GetTaskStatsRequest{BucketSeconds: int64(bucket / time.Second), OldestOpenLimit: oldest}
+			var err error
+			if req.StartTime, err = parseDue(since); err != nil {
+				return fmt.Errorf("invalid --since %q, expected YYYY-MM-DD or RFC 3339", since)
+			}
+			if req.EndTime, err = parseDue(until); err != nil {
+				return fmt.Errorf("invalid --until %q, expected YYYY-MM-DD or RFC 3339", until)
+			}
+
+			ctx, cancel := a.callContext(cmd)
+			defer cancel()
+
+			res, err := a.client.GetTaskStats(ctx, req)
+			if err != nil {
+				return err
+			}
+			return a.printer.Stats(res)
+		},
+	}
+	cmd.Flags().StringVar(&since, "since", "", "start of the window (YYYY-MM-DD or RFC 3339, default 30 days before --until)")
+	cmd.Flags().StringVar(&until, "until", "", "end of the window, excluded (YYYY-MM-DD or RFC 3339, default now)")
+	cmd.Flags().DurationVar(&bucket, "bucket", 24*time.Hour, "size of the buckets the window is cut into")
+	cmd.Flags().Int32Var(&oldest, "oldest", 10, "number of oldest open tasks to show")
+	return cmd
+}
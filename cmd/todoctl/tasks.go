@@ -0,0 +1,312 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"grpc-todo/client"
+	"grpc-todo/proto"
+
+	"github.com/spf13/cobra"
+)
+
+func newCreateCmd(a *app) *cobra.Command {
+	var description, due string
+
+	cmd := &cobra.Command{
+		Use:   "create <title>",
+		Short: "Create a task",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dueAt, err := parseDue(due)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := a.callContext(cmd)
+			defer cancel()
+
+			res, err := a.client.CreateTask(ctx, &proto.CreateTaskRequest{
+				Title:       args[0],
+				Description: description,
+				DueAt:       dueAt,
+			})
+			if err != nil {
+				return err
+			}
+			return a.printer.Task(res.Task)
+		},
+	}
+
+	cmd.Flags().StringVarP(&description, "description", "d", "", "task description")
+	cmd.Flags().StringVar(&due, "due", "", "due date as YYYY-MM-DD (local midnight) or RFC 3339")
+	return cmd
+}
+
+func newListCmd(a *app) *cobra.Command {
+	var (
+		statuses []string
+		search   string
+		expr     string
+		asOf     string
+		archived bool
+	)
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List tasks",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if archived && expr != "" {
+				return fmt.Errorf("--filter cannot be used with --archived")
+			}
+			if archived && asOf != "" {
+				return fmt.Errorf("--as-of cannot be used with --archived")
+			}
+			at, err := parseDue(asOf)
+			if err != nil {
+				return fmt.Errorf("invalid --as-of %q, expected YYYY-MM-DD or RFC 3339", asOf)
+			}
+			wanted := make(map[proto.Status]bool)
+			for _, s := range statuses {
+				status, err := client.ParseStatus(s)
+				if err != nil {
+					return err
+				}
+				wanted[status] = true
+			}
+
+			ctx, cancel := a.callContext(cmd)
+			defer cancel()
+
+			var all []*proto.Task
+			if archived {
+				all, err = listArchived(ctx, a.client)
+			} else {
+				var res *proto.GetAllTasksResponse
+				res, err = a.client.GetAllTasks(ctx, &proto.GetAllTasksRequest{Filter: expr, AsOf: at})
+				all = res.GetTasks()
+			}
+			if err != nil {
+				return err
+			}
+
+			var tasks []*proto.Task
+			for _, t := range all {
+				if len(wanted) > 0 && !wanted[t.Status] {
+					continue
+				}
+				if search != "" && !containsFold(t.Title, search) && !containsFold(t.Description, search) {
+					continue
+				}
+				tasks = append(tasks, t)
+			}
+
+			return a.printer.Tasks(tasks)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&statuses, "status", nil, "only show tasks with these statuses")
+	cmd.Flags().StringVar(&search, "search", "", "only show tasks whose title or description contains this text")
+	cmd.Flags().StringVar(&expr, "filter", "", `server-side filter, e.g. 'status:IN_PROGRESS AND created>2026-01-01 AND title~"deploy"'`)
+	cmd.Flags().StringVar(&asOf, "as-of", "", "list the tasks as they were at this time (YYYY-MM-DD or RFC 3339); needs the event-sourced task store")
+	cmd.Flags().BoolVar(&archived, "archived", false, "list archived tasks instead")
+	_ = cmd.RegisterFlagCompletionFunc("status", completeStatus)
+	return cmd
+}
+
+func newSearchCmd(a *app) *cobra.Command {
+	var (
+		statuses []string
+		tags     []string
+		limit    int32
+	)
+
+	cmd := &cobra.Command{
+		Use:   "search <query>",
+		Short: "Search task titles and descriptions, most relevant first",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req := &proto.SearchTasksRequest{Query: args[0], Tags: tags, PageSize: limit}
+			for _, s := range statuses {
+				status, err := client.ParseStatus(s)
+				if err != nil {
+					return err
+				}
+				req.Statuses = append(req.Statuses, status)
+			}
+
+			ctx, cancel := a.callContext(cmd)
+			defer cancel()
+
+			res, err := a.client.SearchTasks(ctx, req)
+			if err != nil {
+				return err
+			}
+			return a.printer.SearchResults(res.Results)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&statuses, "status", nil, "only show tasks with these statuses")
+	cmd.Flags().StringSliceVar(&tags, "tag", nil, "only show tasks with all of these +project or @context tags")
+	cmd.Flags().Int32Var(&limit, "limit", 0, "maximum number of results (default 20)")
+	_ = cmd.RegisterFlagCompletionFunc("status", completeStatus)
+	return cmd
+}
+
+func newGetCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <id>",
+		Short: "Show a single task",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := a.callContext(cmd)
+			defer cancel()
+
+			res, err := a.client.GetTask(ctx, &proto.GetTaskRequest{Id: args[0]})
+			if err != nil {
+				return err
+			}
+			return a.printer.Task(res.Task)
+		},
+	}
+}
+
+func newHistoryCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "history <id>",
+		Short: "Show a task's status changes and the time spent in each status",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := a.callContext(cmd)
+			defer cancel()
+
+			res, err := a.client.GetTaskHistory(ctx, &proto.GetTaskHistoryRequest{Id: args[0]})
+			if err != nil {
+				return err
+			}
+			return a.printer.History(res)
+		},
+	}
+}
+
+func newSetStatusCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set-status <id> <status>",
+		Short: "Move a task to another status",
+		Long:  "Move a task to another status: " + strings.Join(client.StatusNames(), ", ") + ".",
+		Args:  cobra.ExactArgs(2),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 1 {
+				return completeStatus(cmd, args, toComplete)
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			status, err := client.ParseStatus(args[1])
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := a.callContext(cmd)
+			defer cancel()
+
+			if _, err := a.client.UpdateTaskStatus(ctx, &proto.UpdateTaskStatusRequest{Id: args[0], Status: status}); err != nil {
+				return err
+			}
+
+			res, err := a.client.GetTask(ctx, &proto.GetTaskRequest{Id: args[0]})
+			if err != nil {
+				return err
+			}
+			return a.printer.Task(res.Task)
+		},
+	}
+}
+
+func newDeleteCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:     "delete <id>...",
+		Aliases: []string{"rm"},
+		Short:   "Move tasks to the trash",
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := a.callContext(cmd)
+			defer cancel()
+
+			for _, id := range args {
+				if _, err := a.client.DeleteTask(ctx, &proto.DeleteTaskRequest{Id: id}); err != nil {
+					return fmt.Errorf("failed to delete %s: %w", id, err)
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "deleted %s\n", id)
+			}
+			return nil
+		},
+	}
+}
+
+func newWatchCmd(a *app) *cobra.Command {
+	var interval time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Print task changes as they happen",
+		Long: "Print task changes as they happen. The server has no change feed, " +
+			"so tasks are polled and compared at every interval. A failed poll is " +
+			"reported on stderr and retried at the next one, unless the server " +
+			"refused the credentials.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return watch(cmd.Context(), a, interval, cmd.ErrOrStderr())
+		},
+	}
+
+	cmd.Flags().DurationVar(&interval, "interval", 2*time.Second, "how often to poll the server")
+	return cmd
+}
+
+func newCalendarCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "calendar",
+		Short: "Print the URL of your iCalendar feed",
+		Long: "Print the URL of your iCalendar feed. Subscribe to it from a calendar app; " +
+			"anyone with the URL can read your tasks.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx, cancel := a.callContext(cmd)
+			defer cancel()
+
+			res, err := a.client.GetCalendarFeed(ctx, &proto.GetCalendarFeedRequest{})
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), res.Url)
+			return nil
+		},
+	}
+}
+
+// parseDue accepts a date, taken as local midnight, or an RFC 3339 time.
+func parseDue(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, s, time.Local); err == nil {
+		return t.Unix(), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid due date %q, expected YYYY-MM-DD or RFC 3339", s)
+	}
+	return t.Unix(), nil
+}
+
+func completeStatus(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return client.StatusNames(), cobra.ShellCompDirectiveNoFileComp
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"grpc-todo/domain"
+	"grpc-todo/export"
+	"grpc-todo/proto"
+
+	"github.com/spf13/cobra"
+)
+
+func newExportCmd(a *app) *cobra.Command {
+	var (
+		format      string
+		file        string
+		preserveIDs bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write all tasks to a CSV, JSON Lines, Markdown or todo.txt file",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			f, err := documentFormat(format, file)
+			if err != nil {
+				return err
+			}
+
+			stream, err := a.client.ExportTasks(cmd.Context(), &proto.ExportTasksRequest{PreserveIds: preserveIDs})
+			if err != nil {
+				return err
+			}
+
+			var tasks []*domain.Task
+			for {
+				res, err := stream.Recv()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					return err
+				}
+				tasks = append(tasks, &domain.Task{
+					Id:          res.Task.Id,
+					Title:       res.Task.Title,
+					Description: res.Task.Description,
+					Status:      res.Task.Status.String(),
+					CreatedAt:   res.Task.CreatedAt,
+				})
+			}
+
+			if file == "" || file == "-" {
+				return export.Write(cmd.OutOrStdout(), f, tasks)
+			}
+
+			out, err := os.Create(file)
+			if err != nil {
+				return err
+			}
+			if err := export.Write(out, f, tasks); err != nil {
+				out.Close()
+				return err
+			}
+			return out.Close()
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "", "csv, jsonl, markdown or todotxt (default from the file extension, then csv)")
+	cmd.Flags().StringVarP(&file, "file", "f", "", "file to write (default stdout)")
+	cmd.Flags().BoolVar(&preserveIDs, "preserve-ids", false, "keep task IDs and creation times")
+	_ = cmd.RegisterFlagCompletionFunc("format", completeFormat)
+	return cmd
+}
+
+func newImportCmd(a *app) *cobra.Command {
+	var (
+		format      string
+		dryRun      bool
+		preserveIDs bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Create tasks from a CSV, JSON Lines, Markdown or todo.txt file",
+		Long: "Create tasks from a CSV, JSON Lines, Markdown or todo.txt file (\"-\" reads stdin). " +
+			"Invalid rows are reported by line and skipped; --dry-run only reports. " +
+			"iCalendar (.ics) files update the tasks they were exported from or imported as before.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format == "ics" || format == "" && strings.EqualFold(filepath.Ext(args[0]), ".ics") {
+				return importCalendar(cmd, a, args[0])
+			}
+
+			f, err := documentFormat(format, args[0])
+			if err != nil {
+				return err
+			}
+
+			in := cmd.InOrStdin()
+			if args[0] != "-" {
+				file, err := os.Open(args[0])
+				if err != nil {
+					return err
+				}
+				defer file.Close()
+				in = file
+			}
+
+			rep, err := export.Read(in, f)
+			if err != nil {
+				return err
+			}
+
+			stderr := cmd.ErrOrStderr()
+			for _, p := range rep.Problems {
+				fmt.Fprintln(stderr, p.Error())
+			}
+
+			if dryRun {
+				tasks := make([]*proto.Task, len(rep.Tasks))
+				for i, t := range rep.Tasks {
+					tasks[i] = toProtoTask(t)
+				}
+				if err := a.printer.Tasks(tasks); err != nil {
+					return err
+				}
+				fmt.Fprintf(stderr, "dry run: %s\n", rep.Summary())
+				return nil
+			}
+
+			stream, err := a.client.ImportTasks(cmd.Context())
+			if err != nil {
+				return err
+			}
+			for _, t := range rep.Tasks {
+				err := stream.Send(&proto.ImportTasksRequest{Task: toProtoTask(t), PreserveIds: preserveIDs})
+				if err != nil {
+					break
+				}
+			}
+			res, err := stream.CloseAndRecv()
+			if err != nil {
+				return err
+			}
+
+			for _, e := range res.Errors {
+				fmt.Fprintf(stderr, "line %d: %s\n", rep.Lines[e.Index], e.Status.GetMessage())
+			}
+			fmt.Fprintf(stderr, "imported %d of %d tasks, %d problems\n", res.Imported, res.Received, len(rep.Problems)+int(res.Failed))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "", "csv, jsonl, markdown, todotxt or ics (default from the file extension)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "validate the file and show what would be imported")
+	cmd.Flags().BoolVar(&preserveIDs, "preserve-ids", false, "keep task IDs and creation times from the file")
+	_ = cmd.RegisterFlagCompletionFunc("format", completeFormat)
+	return cmd
+}
+
+func toProtoTask(t *domain.Task) *proto.Task {
+	status, _ := export.ParseStatus(t.Status)
+	return &proto.Task{
+		Id:          t.Id,
+		Title:       t.Title,
+		Description: t.Description,
+		Status:      status,
+		CreatedAt:   t.CreatedAt,
+	}
+}
+
+// importCalendar sends an .ics file to ImportCalendar, which updates the
+// tasks it has seen before instead of creating them again.
+func importCalendar(cmd *cobra.Command, a *app, path string) error {
+	var content []byte
+	var err error
+	if path == "-" {
+		content, err = io.ReadAll(cmd.InOrStdin())
+	} else {
+		content, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := a.callContext(cmd)
+	defer cancel()
+
+	res, err := a.client.ImportCalendar(ctx, &proto.ImportCalendarRequest{Content: content})
+	if err != nil {
+		return err
+	}
+
+	stderr := cmd.ErrOrStderr()
+	for _, p := range res.Problems {
+		fmt.Fprintln(stderr, export.Problem{Line: int(p.Line), Field: p.Field, Message: p.Message}.Error())
+	}
+	fmt.Fprintf(stderr, "created %d, updated %d tasks, %d problems\n", res.Created, res.Updated, len(res.Problems))
+	return nil
+}
+
+// documentFormat resolves --format, falling back to the file's extension and
+// then to CSV.
+func documentFormat(format, file string) (export.Format, error) {
+	if format != "" {
+		return export.ParseFormat(format)
+	}
+	if f, err := export.FormatFromPath(file); err == nil {
+		return f, nil
+	}
+	return export.CSV, nil
+}
+
+func completeFormat(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	var names []string
+	for _, f := range export.Formats() {
+		names = append(names, string(f))
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+
+	"grpc-todo/proto"
+
+	"github.com/spf13/cobra"
+)
+
+func newTrashCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trash",
+		Short: "List, undelete or purge deleted tasks",
+	}
+	cmd.AddCommand(newTrashListCmd(a), newTrashUndeleteCmd(a), newTrashPurgeCmd(a))
+	return cmd
+}
+
+func newTrashListCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List deleted tasks",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx, cancel := a.callContext(cmd)
+			defer cancel()
+
+			tasks, err := allPages(func(token string) ([]*proto.Task, string, error) {
+				res, err := a.client.ListTrash(ctx, &proto.ListTrashRequest{PageSize: 1000, PageToken: token})
+				return res.GetTasks(), res.GetNextPageToken(), err
+			})
+			if err != nil {
+				return err
+			}
+			return a.printer.Tasks(tasks)
+		},
+	}
+}
+
+func newTrashUndeleteCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "undelete <id>...",
+		Short: "Take tasks out of the trash",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := a.callContext(cmd)
+			defer cancel()
+
+			for _, id := range args {
+				if _, err := a.client.UndeleteTask(ctx, &proto.UndeleteTaskRequest{Id: id}); err != nil {
+					return fmt.Errorf("failed to undelete %s: %w", id, err)
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "undeleted %s\n", id)
+			}
+			return nil
+		},
+	}
+}
+
+func newTrashPurgeCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "purge <id>...",
+		Short: "Delete tasks in the trash for good",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := a.callContext(cmd)
+			defer cancel()
+
+			for _, id := range args {
+				if _, err := a.client.PurgeTask(ctx, &proto.PurgeTaskRequest{Id: id}); err != nil {
+					return fmt.Errorf("failed to purge %s: %w", id, err)
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "purged %s\n", id)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+
+	"grpc-todo/proto"
+
+	"github.com/spf13/cobra"
+)
+
+func newViewCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "view",
+		Aliases: []string{"views"},
+		Short:   "Manage saved views, named filters for listing tasks",
+	}
+	cmd.AddCommand(newViewCreateCmd(a), newViewListCmd(a), newViewUpdateCmd(a), newViewDeleteCmd(a), newViewTasksCmd(a))
+	return cmd
+}
+
+func addViewFlags(cmd *cobra.Command, filter, orderBy *string, shared *bool) {
+	cmd.Flags().StringVar(filter, "filter", "", `task filter, e.g. 'status:IN_PROGRESS AND title~"deploy"'`)
+	cmd.Flags().StringVar(orderBy, "order-by", "", `sort order, e.g. "due, created desc"`)
+	cmd.Flags().BoolVar(shared, "shared", false, "let everyone list and use the view")
+}
+
+func newViewCreateCmd(a *app) *cobra.Command {
+	var filter, orderBy string
+	var shared bool
+
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Save a view",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := a.callContext(cmd)
+			defer cancel()
+
+			res, err := a.client.CreateView(ctx, &proto.CreateViewRequest{
+				Name:    args[0],
+				Filter:  filter,
+				OrderBy: orderBy,
+				Shared:  shared,
+			})
+			if err != nil {
+				return err
+			}
+			return a.printer.Views([]*proto.SavedView{res.View})
+		},
+	}
+
+	addViewFlags(cmd, &filter, &orderBy, &shared)
+	return cmd
+}
+
+func newViewListCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List your views and the views others share",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx, cancel := a.callContext(cmd)
+			defer cancel()
+
+			res, err := a.client.ListViews(ctx, &proto.ListViewsRequest{})
+			if err != nil {
+				return err
+			}
+			return a.printer.Views(res.Views)
+		},
+	}
+}
+
+func newViewUpdateCmd(a *app) *cobra.Command {
+	var name, filter, orderBy string
+	var shared bool
+
+	cmd := &cobra.Command{
+		Use:   "update <id>",
+		Short: "Change a view; flags that are not given keep their value",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := a.callContext(cmd)
+			defer cancel()
+
+			current, err := a.client.GetView(ctx, &proto.GetViewRequest{Id: args[0]})
+			if err != nil {
+				return err
+			}
+			req := &proto.UpdateViewRequest{
+				Id:      args[0],
+				Name:    current.View.Name,
+				Filter:  current.View.Filter,
+				OrderBy: current.View.OrderBy,
+				Shared:  current.View.Shared,
+			}
+			flags := cmd.Flags()
+			if flags.Changed("name") {
+				req.Name = name
+			}
+			if flags.Changed("filter") {
+				req.Filter = filter
+			}
+			if flags.Changed("order-by") {
+				req.OrderBy = orderBy
+			}
+			if flags.Changed("shared") {
+				req.Shared = shared
+			}
+
+			res, err := a.client.UpdateView(ctx, req)
+			if err != nil {
+				return err
+			}
+			return a.printer.Views([]*proto.SavedView{res.View})
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "new name")
+	addViewFlags(cmd, &filter, &orderBy, &shared)
+	return cmd
+}
+
+func newViewDeleteCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:     "delete <id>...",
+		Aliases: []string{"rm"},
+		Short:   "Delete views",
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := a.callContext(cmd)
+			defer cancel()
+
+			for _, id := range args {
+				if _, err := a.client.DeleteView(ctx, &proto.DeleteViewRequest{Id: id}); err != nil {
+					return fmt.Errorf("failed to delete %s: %w", id, err)
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "deleted %s\n", id)
+			}
+			return nil
+		},
+	}
+}
+
+func newViewTasksCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "tasks <id>",
+		Short: "List the tasks a view selects, in its order",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := a.callContext(cmd)
+			defer cancel()
+
+			res, err := a.client.ListTasksByView(ctx, &proto.ListTasksByViewRequest{Id: args[0]})
+			if err != nil {
+				return err
+			}
+			return a.printer.Tasks(res.Tasks)
+		},
+	}
+}
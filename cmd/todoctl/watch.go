@@ -0,0 +1,110 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"grpc-todo/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+type eventType string
+
+const (
+	eventCreated eventType = "CREATED"
+	eventUpdated eventType = "UPDATED"
+	eventDeleted eventType = "DELETED"
+)
+
+type event struct {
+	Type eventType
+	Task *proto.Task
+}
+
+// watch polls the tasks until ctx is done. A failed poll is written to
+// stderr and the next one compares against the last tasks it got, so
+// changes made in between are still printed; only errors that another try
+// cannot fix end it.
+func watch(ctx context.Context, a *app, interval time.Duration, stderr io.Writer) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var previous map[string]*proto.Task
+	for {
+		callCtx, cancel := context.WithTimeout(ctx, a.opts.timeout)
+		res, err := a.client.GetAllTasks(callCtx, &proto.GetAllTasksRequest{})
+		cancel()
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err != nil {
+			switch status.Code(err) {
+			case codes.Unauthenticated, codes.PermissionDenied:
+				return err
+			}
+			fmt.Fprintf(stderr, "watch: %v; retrying in %s\n", err, interval)
+		} else if previous, err = printChanges(a.printer, previous, res.Tasks); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// printChanges prints tasks in full on the first poll, when previous is
+// nil, and the changes since previous after that. It returns the tasks by
+// ID for the next poll.
+func printChanges(p printer, previous map[string]*proto.Task, tasks []*proto.Task) (map[string]*proto.Task, error) {
+	current := make(map[string]*proto.Task, len(tasks))
+	for _, t := range tasks {
+		current[t.Id] = t
+	}
+
+	if previous == nil {
+		return current, p.Tasks(tasks)
+	}
+	for _, e := range diffTasks(previous, current) {
+		if err := p.Event(e); err != nil {
+			return current, err
+		}
+	}
+	return current, nil
+}
+
+// diffTasks returns the changes between two polls: creations and updates in
+// task ID order, then deletions in task ID order, so that the same change
+// always prints the same way.
+func diffTasks(previous, current map[string]*proto.Task) []event {
+	var changed, deleted []event
+	for id, t := range current {
+		old, ok := previous[id]
+		switch {
+		case !ok:
+			changed = append(changed, event{Type: eventCreated, Task: t})
+		case !protobuf.Equal(old, t):
+			changed = append(changed, event{Type: eventUpdated, Task: t})
+		}
+	}
+	for id, t := range previous {
+		if _, ok := current[id]; !ok {
+			deleted = append(deleted, event{Type: eventDeleted, Task: t})
+		}
+	}
+
+	byID := func(a, b event) int { return cmp.Compare(a.Task.Id, b.Task.Id) }
+	slices.SortFunc(changed, byID)
+	slices.SortFunc(deleted, byID)
+	return append(changed, deleted...)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"grpc-todo/proto"
+
+	"github.com/spf13/cobra"
+)
+
+var webhookEvents = map[string]proto.WebhookEvent{
+	"created":        proto.WebhookEvent_WEBHOOK_EVENT_TASK_CREATED,
+	"status-updated": proto.WebhookEvent_WEBHOOK_EVENT_TASK_STATUS_UPDATED,
+	"deleted":        proto.WebhookEvent_WEBHOOK_EVENT_TASK_DELETED,
+}
+
+func webhookEventName(e proto.WebhookEvent) string {
+	for name, v := range webhookEvents {
+		if v == e {
+			return name
+		}
+	}
+	return e.String()
+}
+
+func newWebhookCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "webhook",
+		Aliases: []string{"webhooks"},
+		Short:   "Manage webhooks that are called when your tasks change",
+	}
+	cmd.AddCommand(newWebhookCreateCmd(a), newWebhookListCmd(a), newWebhookDeleteCmd(a))
+	return cmd
+}
+
+func newWebhookCreateCmd(a *app) *cobra.Command {
+	var events []string
+	var secret string
+
+	cmd := &cobra.Command{
+		Use:   "create <url>",
+		Short: "Subscribe a URL to task events",
+		Long: "Subscribe a URL to task events. Every delivery carries an X-Webhook-Signature " +
+			"header made with the secret, which is printed once; keep it to verify deliveries.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req := &proto.CreateWebhookRequest{Url: args[0], Secret: secret}
+			for _, name := range events {
+				e, ok := webhookEvents[strings.ToLower(name)]
+				if !ok {
+					return fmt.Errorf("unknown event %q, expected created, status-updated or deleted", name)
+				}
+				req.Events = append(req.Events, e)
+			}
+
+			ctx, cancel := a.callContext(cmd)
+			defer cancel()
+
+			res, err := a.client.CreateWebhook(ctx, req)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "secret: %s\n", res.Secret)
+			return a.printer.Webhooks([]*proto.Webhook{res.Webhook})
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&events, "event", "e", nil, "events to send: created, status-updated, deleted (default all)")
+	cmd.Flags().StringVar(&secret, "secret", "", "signing secret (default generated)")
+	_ = cmd.RegisterFlagCompletionFunc("event", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return []string{"created", "status-updated", "deleted"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	return cmd
+}
+
+func newWebhookListCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List your webhooks",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx, cancel := a.callContext(cmd)
+			defer cancel()
+
+			res, err := a.client.ListWebhooks(ctx, &proto.ListWebhooksRequest{})
+			if err != nil {
+				return err
+			}
+			return a.printer.Webhooks(res.Webhooks)
+		},
+	}
+}
+
+func newWebhookDeleteCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:     "delete <id>...",
+		Aliases: []string{"rm"},
+		Short:   "Delete webhooks",
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := a.callContext(cmd)
+			defer cancel()
+
+			for _, id := range args {
+				if _, err := a.client.DeleteWebhook(ctx, &proto.DeleteWebhookRequest{Id: id}); err != nil {
+					return fmt.Errorf("failed to delete %s: %w", id, err)
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "deleted %s\n", id)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"grpc-todo/client"
+	"grpc-todo/proto"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func main() {
+	var (
+		configPath = flag.String("config", client.DefaultConfigPath(), "path to the config file shared with todoctl")
+		server     = flag.String("server", "", "server address (default from config, then "+client.DefaultServer+")")
+		token      = flag.String("token", "", "bearer token sent with every call")
+		useTLS     = flag.Bool("tls", false, "connect using TLS")
+		tlsCA      = flag.String("tls-ca", "", "CA certificate used to verify the server")
+		interval   = flag.Duration("interval", 5*time.Second, "how often to refresh the board")
+		timeout    = flag.Duration("timeout", 10*time.Second, "timeout for each call")
+	)
+	flag.Parse()
+
+	cfg, err := client.LoadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "server":
+			cfg.Server = *server
+		case "token":
+			cfg.Token = *token
+		case "tls":
+			cfg.TLS.Enabled = *useTLS
+		case "tls-ca":
+			cfg.TLS.CAFile = *tlsCA
+		}
+	})
+
+	conn, err := client.Dial(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to %s: %v\n", cfg.Server, err)
+		os.Exit(1)
+	}
+	defer conn.Close()
+
+	m := newModel(proto.NewToDoServiceClient(conn), *interval, *timeout)
+	if _, err := tea.NewProgram(m, tea.WithAltScreen()).Run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,322 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"grpc-todo/proto"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// columns are the board columns, in the order tasks move through them.
+var columns = []proto.Status{
+	proto.Status_TODO,
+	proto.Status_IN_PROGRESS,
+	proto.Status_PAUSED,
+	proto.Status_DONE,
+}
+
+type mode int
+
+const (
+	modeBoard mode = iota
+	modeCreate
+	modeEdit
+)
+
+type tasksLoadedMsg struct {
+	tasks []*proto.Task
+}
+
+type taskSavedMsg struct {
+	info string
+}
+
+type errMsg struct {
+	err error
+}
+
+type tickMsg struct{}
+
+type model struct {
+	client   proto.ToDoServiceClient
+	interval time.Duration
+	timeout  time.Duration
+
+	board    [][]*proto.Task
+	column   int
+	selected []int
+	// follow is the ID of a task to keep selected after the next reload.
+	follow string
+
+	mode        mode
+	editingID   string
+	inputs      []textinput.Model
+	inputFocus  int
+	createAtCol int
+
+	info   string
+	err    error
+	width  int
+	height int
+}
+
+func newModel(c proto.ToDoServiceClient, interval, timeout time.Duration) model {
+	title := textinput.New()
+	title.Placeholder = "Title"
+	title.CharLimit = 200
+
+	description := textinput.New()
+	description.Placeholder = "Description"
+	description.CharLimit = 4000
+
+	return model{
+		client:   c,
+		interval: interval,
+		timeout:  timeout,
+		board:    make([][]*proto.Task, len(columns)),
+		selected: make([]int, len(columns)),
+		inputs:   []textinput.Model{title, description},
+	}
+}
+
+func (m model) Init() tea.Cmd {
+	return tea.Batch(m.load(), m.tick())
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
+
+	case tasksLoadedMsg:
+		m.setTasks(msg.tasks)
+		m.err = nil
+		return m, nil
+
+	case taskSavedMsg:
+		m.info = msg.info
+		return m, m.load()
+
+	case errMsg:
+		m.err = msg.err
+		return m, nil
+
+	case tickMsg:
+		if m.mode != modeBoard {
+			return m, m.tick()
+		}
+		return m, tea.Batch(m.load(), m.tick())
+
+	case tea.KeyMsg:
+		if m.mode != modeBoard {
+			return m.updateForm(msg)
+		}
+		return m.updateBoard(msg)
+	}
+
+	return m, nil
+}
+
+func (m model) updateBoard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "left", "h":
+		m.column = (m.column + len(columns) - 1) % len(columns)
+	case "right", "l":
+		m.column = (m.column + 1) % len(columns)
+	case "up", "k":
+		if m.selected[m.column] > 0 {
+			m.selected[m.column]--
+		}
+	case "down", "j":
+		if m.selected[m.column] < len(m.board[m.column])-1 {
+			m.selected[m.column]++
+		}
+	case "shift+left", "H", "<":
+		return m.move(m.column - 1)
+	case "shift+right", "L", ">":
+		return m.move(m.column + 1)
+	case "1", "2", "3", "4":
+		return m.move(int(msg.String()[0] - '1'))
+	case "r":
+		m.info = ""
+		return m, m.load()
+	case "n":
+		return m.openForm(modeCreate, nil)
+	case "e", "enter":
+		if task := m.current(); task != nil {
+			return m.openForm(modeEdit, task)
+		}
+	}
+	return m, nil
+}
+
+func (m model) updateForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.mode = modeBoard
+		return m, nil
+	case "tab", "shift+tab", "up", "down":
+		m.inputs[m.inputFocus].Blur()
+		m.inputFocus = (m.inputFocus + 1) % len(m.inputs)
+		return m, m.inputs[m.inputFocus].Focus()
+	case "enter":
+		title, description := m.inputs[0].Value(), m.inputs[1].Value()
+		if title == "" {
+			m.info = "title must not be empty"
+			return m, nil
+		}
+
+		cmd := m.save(title, description)
+		m.mode = modeBoard
+		return m, cmd
+	}
+
+	var cmd tea.Cmd
+	m.inputs[m.inputFocus], cmd = m.inputs[m.inputFocus].Update(msg)
+	return m, cmd
+}
+
+func (m model) openForm(md mode, task *proto.Task) (tea.Model, tea.Cmd) {
+	m.mode = md
+	m.editingID = ""
+	m.createAtCol = m.column
+	m.inputs[0].SetValue("")
+	m.inputs[1].SetValue("")
+	if task != nil {
+		m.editingID = task.Id
+		m.inputs[0].SetValue(task.Title)
+		m.inputs[1].SetValue(task.Description)
+	}
+
+	m.inputs[1].Blur()
+	m.inputFocus = 0
+	return m, m.inputs[0].Focus()
+}
+
+func (m *model) setTasks(tasks []*proto.Task) {
+	board := make([][]*proto.Task, len(columns))
+	for _, t := range tasks {
+		col := columnOf(t.Status)
+		board[col] = append(board[col], t)
+	}
+	for _, col := range board {
+		sort.SliceStable(col, func(i, j int) bool { return col[i].CreatedAt < col[j].CreatedAt })
+	}
+
+	m.board = board
+	for i := range m.selected {
+		if m.selected[i] >= len(board[i]) {
+			m.selected[i] = max(len(board[i])-1, 0)
+		}
+	}
+
+	if m.follow == "" {
+		return
+	}
+	for i, col := range board {
+		for j, t := range col {
+			if t.Id == m.follow {
+				m.column, m.selected[i] = i, j
+				m.follow = ""
+				return
+			}
+		}
+	}
+}
+
+func (m model) current() *proto.Task {
+	col := m.board[m.column]
+	if len(col) == 0 {
+		return nil
+	}
+	return col[m.selected[m.column]]
+}
+
+func (m model) move(target int) (tea.Model, tea.Cmd) {
+	task := m.current()
+	if task == nil || target < 0 || target >= len(columns) || target == m.column {
+		return m, nil
+	}
+
+	status := columns[target]
+	id := task.Id
+	m.follow = id
+	return m, m.call(func(ctx context.Context) (string, error) {
+		_, err := m.client.UpdateTaskStatus(ctx, &proto.UpdateTaskStatusRequest{Id: id, Status: status})
+		return "moved to " + status.String(), err
+	})
+}
+
+func (m model) save(title, description string) tea.Cmd {
+	if m.mode == modeEdit {
+		id := m.editingID
+		return m.call(func(ctx context.Context) (string, error) {
+			_, err := m.client.UpdateTask(ctx, &proto.UpdateTaskRequest{Id: id, Title: title, Description: description})
+			return "task updated", err
+		})
+	}
+
+	status := columns[m.createAtCol]
+	return m.call(func(ctx context.Context) (string, error) {
+		res, err := m.client.CreateTask(ctx, &proto.CreateTaskRequest{Title: title, Description: description})
+		if err != nil {
+			return "", err
+		}
+		if status != proto.Status_TODO {
+			_, err = m.client.UpdateTaskStatus(ctx, &proto.UpdateTaskStatusRequest{Id: res.Task.Id, Status: status})
+		}
+		return "task created", err
+	})
+}
+
+func (m model) call(fn func(ctx context.Context) (string, error)) tea.Cmd {
+	timeout := m.timeout
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		info, err := fn(ctx)
+		if err != nil {
+			return errMsg{err: err}
+		}
+		return taskSavedMsg{info: info}
+	}
+}
+
+func (m model) load() tea.Cmd {
+	c, timeout := m.client, m.timeout
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		res, err := c.GetAllTasks(ctx, &proto.GetAllTasksRequest{})
+		if err != nil {
+			return errMsg{err: err}
+		}
+		return tasksLoadedMsg{tasks: res.Tasks}
+	}
+}
+
+// tick schedules the next refresh. The service has no change feed yet, so
+// the board polls GetAllTasks.
+func (m model) tick() tea.Cmd {
+	return tea.Tick(m.interval, func(time.Time) tea.Msg { return tickMsg{} })
+}
+
+func columnOf(status proto.Status) int {
+	for i, s := range columns {
+		if s == status {
+			return i
+		}
+	}
+	return 0
+}
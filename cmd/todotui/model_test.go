@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"grpc-todo/proto"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"google.golang.org/grpc"
+)
+
+type fakeClient struct {
+	proto.ToDoServiceClient
+	tasks   []*proto.Task
+	updates []*proto.UpdateTaskStatusRequest
+	created []*proto.CreateTaskRequest
+}
+
+func (f *fakeClient) GetAllTasks(context.Context, *proto.GetAllTasksRequest, ...grpc.CallOption) (*proto.GetAllTasksResponse, error) {
+	return &proto.GetAllTasksResponse{Tasks: f.tasks}, nil
+}
+
+func (f *fakeClient) CreateTask(_ context.Context, req *proto.CreateTaskRequest, _ ...grpc.CallOption) (*proto.CreateTaskResponse, error) {
+	f.created = append(f.created, req)
+	return &proto.CreateTaskResponse{Task: &proto.Task{Id: "new", Title: req.Title, Status: proto.Status_TODO}}, nil
+}
+
+func (f *fakeClient) UpdateTaskStatus(_ context.Context, req *proto.UpdateTaskStatusRequest, _ ...grpc.CallOption) (*proto.UpdateTaskStatusResponse, error) {
+	f.updates = append(f.updates, req)
+	return &proto.UpdateTaskStatusResponse{}, nil
+}
+
+func key(s string) tea.KeyMsg {
+	switch s {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "right":
+		return tea.KeyMsg{Type: tea.KeyRight}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func press(t *testing.T, m model, keys ...string) (model, tea.Cmd) {
+	t.Helper()
+	var cmd tea.Cmd
+	for _, k := range keys {
+		var next tea.Model
+		next, cmd = m.Update(key(k))
+		m = next.(model)
+	}
+	return m, cmd
+}
+
+func loaded(c *fakeClient) model {
+	m := newModel(c, time.Minute, time.Second)
+	next, _ := m.Update(m.load()())
+	return next.(model)
+}
+
+func TestBoardGroupsTasksByStatus(t *testing.T) {
+	m := loaded(&fakeClient{tasks: []*proto.Task{
+		{Id: "1", Title: "A", Status: proto.Status_TODO},
+		{Id: "2", Title: "B", Status: proto.Status_PAUSED},
+		{Id: "3", Title: "C", Status: proto.Status_DONE},
+		{Id: "4", Title: "D", Status: proto.Status_DONE},
+	}})
+
+	want := []int{1, 0, 1, 2}
+	for i, n := range want {
+		if len(m.board[i]) != n {
+			t.Errorf("Expected %d tasks in %s, got %d", n, columns[i], len(m.board[i]))
+		}
+	}
+}
+
+func TestMoveTaskToNextColumn(t *testing.T) {
+	c := &fakeClient{tasks: []*proto.Task{{Id: "1", Title: "A", Status: proto.Status_TODO}}}
+	m := loaded(c)
+
+	m, cmd := press(t, m, "L")
+	if cmd == nil {
+		t.Fatalf("Expected a command to update the task")
+	}
+	cmd()
+
+	if len(c.updates) != 1 || c.updates[0].Id != "1" || c.updates[0].Status != proto.Status_IN_PROGRESS {
+		t.Fatalf("Expected task 1 to move to IN_PROGRESS, got %v", c.updates)
+	}
+
+	c.tasks[0].Status = proto.Status_IN_PROGRESS
+	next, _ := m.Update(m.load()())
+	if got := next.(model).column; got != 1 {
+		t.Errorf("Expected selection to follow the task to column 1, got %d", got)
+	}
+}
+
+func TestCreateTaskInFocusedColumn(t *testing.T) {
+	c := &fakeClient{}
+	m := loaded(c)
+
+	m, _ = press(t, m, "right", "right", "n", "W", "r", "i", "t", "e")
+	if m.mode != modeCreate {
+		t.Fatalf("Expected create form to be open")
+	}
+
+	_, cmd := press(t, m, "enter")
+	if cmd == nil {
+		t.Fatalf("Expected a command to create the task")
+	}
+	cmd()
+
+	if len(c.created) != 1 || c.created[0].Title != "Write" {
+		t.Fatalf("Expected task Write to be created, got %v", c.created)
+	}
+	if len(c.updates) != 1 || c.updates[0].Status != proto.Status_PAUSED {
+		t.Errorf("Expected new task to be moved to PAUSED, got %v", c.updates)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	columnStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("240")).
+			Padding(0, 1)
+	focusedColumnStyle = columnStyle.BorderForeground(lipgloss.Color("63"))
+	headerStyle        = lipgloss.NewStyle().Bold(true)
+	selectedStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("212")).Bold(true)
+	mutedStyle         = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	errorStyle         = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+)
+
+const helpText = "←/→ column • ↑/↓ task • H/L or 1-4 move • n new • e edit • r refresh • q quit"
+
+func (m model) View() string {
+	var b strings.Builder
+
+	b.WriteString(m.viewBoard())
+	b.WriteString("\n")
+
+	if m.mode != modeBoard {
+		b.WriteString(m.viewForm())
+		b.WriteString("\n")
+	}
+
+	switch {
+	case m.err != nil:
+		b.WriteString(errorStyle.Render("error: " + status.Convert(m.err).Message()))
+	case m.info != "":
+		b.WriteString(mutedStyle.Render(m.info))
+	}
+	b.WriteString("\n")
+	b.WriteString(mutedStyle.Render(helpText))
+
+	return b.String()
+}
+
+func (m model) viewBoard() string {
+	width := 30
+	if m.width > 0 {
+		width = max(m.width/len(columns)-4, 16)
+	}
+
+	rendered := make([]string, len(columns))
+	for i, s := range columns {
+		var lines []string
+		lines = append(lines, headerStyle.Render(fmt.Sprintf("%s (%d)", s, len(m.board[i]))), "")
+
+		for j, t := range m.board[i] {
+			line := truncate(t.Title, width-2)
+			if i == m.column && j == m.selected[i] {
+				line = selectedStyle.Render("> " + line)
+			} else {
+				line = "  " + line
+			}
+			lines = append(lines, line)
+		}
+
+		style := columnStyle
+		if i == m.column {
+			style = focusedColumnStyle
+		}
+		rendered[i] = style.Width(width).Render(strings.Join(lines, "\n"))
+	}
+
+	return lipgloss.JoinHorizontal(lipgloss.Top, rendered...)
+}
+
+func (m model) viewForm() string {
+	heading := "New task in " + columns[m.createAtCol].String()
+	if m.mode == modeEdit {
+		heading = "Edit task"
+	}
+
+	return strings.Join([]string{
+		headerStyle.Render(heading),
+		m.inputs[0].View(),
+		m.inputs[1].View(),
+		mutedStyle.Render("tab switch field • enter save • esc cancel"),
+	}, "\n")
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if n <= 1 || len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
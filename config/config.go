@@ -0,0 +1,178 @@
+package config
+
+import (
+	"fmt"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"grpc-todo/ratelimit"
+	"grpc-todo/retention"
+)
+
+type Config struct {
+	MongoURI   string
+	GRPCPort   string
+	HTTPPort   string
+	LogFormat  string
+	LogLevel   string
+	AuthTokens map[string]string
+
+	CORSAllowedOrigins []string
+
+	RateLimit         ratelimit.Limit
+	MethodRateLimits  map[string]ratelimit.Limit
+	MaxTasksPerTenant int64
+
+	// CalendarSecret signs calendar feed URLs; feeds are off when it is empty.
+	CalendarSecret  string
+	CalendarBaseURL string
+
+	// WebhookMaxAttempts is how many failed deliveries dead-letter a
+	// webhook event.
+	WebhookMaxAttempts int
+	// WebhookAllowedNetworks are the loopback, private or link-local
+	// networks webhooks may still be posted to.
+	WebhookAllowedNetworks []netip.Prefix
+
+	// OutboxFile is a file the outbox relay also appends task events to,
+	// besides queuing their webhook deliveries; none is written when it is
+	// empty.
+	OutboxFile string
+
+	RetentionPolicies []retention.Policy
+	RetentionSchedule string
+	RetentionDryRun   bool
+
+	// AuditTTL is how long audit entries are kept.
+	AuditTTL time.Duration
+
+	// TaskStore is "mongo" for the tasks collection or "events" for an
+	// event-sourced store, kept in EventLogDir when it is set and in Mongo
+	// otherwise.
+	TaskStore          string
+	EventLogDir        string
+	EventSnapshotEvery int
+}
+
+func Load() (*Config, error) {
+	cfg := &Config{
+		MongoURI:  getEnv("MONGO_URI", "mongodb://localhost:27017"),
+		GRPCPort:  getEnv("GRPC_PORT", "50051"),
+		HTTPPort:  getEnv("HTTP_PORT", "8080"),
+		LogFormat: getEnv("LOG_FORMAT", "text"),
+		LogLevel:  getEnv("LOG_LEVEL", "info"),
+	}
+	cfg.CalendarSecret = os.Getenv("CALENDAR_SECRET")
+	cfg.OutboxFile = os.Getenv("OUTBOX_FILE")
+	cfg.RetentionSchedule = getEnv("RETENTION_SCHEDULE", "@every 1h")
+	cfg.CalendarBaseURL = getEnv("CALENDAR_BASE_URL", "http://localhost:"+cfg.HTTPPort)
+
+	tokens, err := parseTokens(os.Getenv("AUTH_TOKENS"))
+	if err != nil {
+		return nil, err
+	}
+	cfg.AuthTokens = tokens
+
+	for _, origin := range strings.Split(os.Getenv("CORS_ALLOWED_ORIGINS"), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			cfg.CORSAllowedOrigins = append(cfg.CORSAllowedOrigins, origin)
+		}
+	}
+
+	if raw := os.Getenv("RATE_LIMIT"); raw != "" {
+		cfg.RateLimit, err = ratelimit.ParseLimit(raw)
+		if err != nil {
+			return nil, fmt.Errorf("RATE_LIMIT: %v", err)
+		}
+	}
+
+	cfg.MethodRateLimits, err = ratelimit.ParseMethodLimits(os.Getenv("RATE_LIMIT_METHODS"))
+	if err != nil {
+		return nil, fmt.Errorf("RATE_LIMIT_METHODS: %v", err)
+	}
+
+	if raw := os.Getenv("MAX_TASKS_PER_TENANT"); raw != "" {
+		cfg.MaxTasksPerTenant, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil || cfg.MaxTasksPerTenant < 0 {
+			return nil, fmt.Errorf("invalid MAX_TASKS_PER_TENANT %q", raw)
+		}
+	}
+
+	cfg.RetentionPolicies, err = retention.ParsePolicies(getEnv("RETENTION_POLICIES", "DONE:archive:30d,TRASH:purge:30d"))
+	if err != nil {
+		return nil, fmt.Errorf("RETENTION_POLICIES: %v", err)
+	}
+	if raw := os.Getenv("RETENTION_DRY_RUN"); raw != "" {
+		cfg.RetentionDryRun, err = strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RETENTION_DRY_RUN %q", raw)
+		}
+	}
+
+	raw := getEnv("AUDIT_TTL", "365d")
+	cfg.AuditTTL, err = retention.ParseAge(raw)
+	if err != nil || cfg.AuditTTL <= 0 {
+		return nil, fmt.Errorf("invalid AUDIT_TTL %q", raw)
+	}
+
+	cfg.TaskStore = getEnv("TASK_STORE", "mongo")
+	if cfg.TaskStore != "mongo" && cfg.TaskStore != "events" {
+		return nil, fmt.Errorf("invalid TASK_STORE %q, expected mongo or events", cfg.TaskStore)
+	}
+	cfg.EventLogDir = os.Getenv("EVENT_LOG_DIR")
+	cfg.EventSnapshotEvery = 1000
+	if raw := os.Getenv("EVENT_SNAPSHOT_EVERY"); raw != "" {
+		cfg.EventSnapshotEvery, err = strconv.Atoi(raw)
+		if err != nil || cfg.EventSnapshotEvery < 1 {
+			return nil, fmt.Errorf("invalid EVENT_SNAPSHOT_EVERY %q", raw)
+		}
+	}
+
+	cfg.WebhookMaxAttempts = 8
+	if raw := os.Getenv("WEBHOOK_MAX_ATTEMPTS"); raw != "" {
+		cfg.WebhookMaxAttempts, err = strconv.Atoi(raw)
+		if err != nil || cfg.WebhookMaxAttempts < 1 {
+			return nil, fmt.Errorf("invalid WEBHOOK_MAX_ATTEMPTS %q", raw)
+		}
+	}
+	for _, raw := range strings.Split(os.Getenv("WEBHOOK_ALLOWED_NETWORKS"), ",") {
+		if raw = strings.TrimSpace(raw); raw == "" {
+			continue
+		}
+		network, err := netip.ParsePrefix(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid WEBHOOK_ALLOWED_NETWORKS entry %q, expected a CIDR such as 10.0.0.0/8", raw)
+		}
+		cfg.WebhookAllowedNetworks = append(cfg.WebhookAllowedNetworks, network)
+	}
+
+	return cfg, nil
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// parseTokens reads a comma-separated list of token:principal pairs.
+func parseTokens(raw string) (map[string]string, error) {
+	tokens := make(map[string]string)
+	if raw == "" {
+		return tokens, nil
+	}
+
+	for _, pair := range strings.Split(raw, ",") {
+		token, principal, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || token == "" || principal == "" {
+			return nil, fmt.Errorf("invalid AUTH_TOKENS entry %q, expected token:principal", pair)
+		}
+		tokens[token] = principal
+	}
+
+	return tokens, nil
+}
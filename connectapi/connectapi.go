@@ -0,0 +1,299 @@
+package connectapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+
+	"grpc-todo/proto"
+	"grpc-todo/proto/protoconnect"
+
+	"connectrpc.com/connect"
+	connectcors "connectrpc.com/cors"
+	"github.com/rs/cors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// forwardedHeaders are copied from browser requests into gRPC metadata.
+var forwardedHeaders = []string{"authorization", "x-request-id"}
+
+// returnedHeaders are copied from gRPC response metadata back to the browser.
+var returnedHeaders = []string{"x-request-id", "retry-after"}
+
+// handler serves ToDoService over the Connect, gRPC-Web and gRPC protocols.
+// Calls are relayed to the native gRPC server so that they go through the
+// same interceptor chain as every other transport.
+type handler struct {
+	client proto.ToDoServiceClient
+}
+
+// New returns the route prefix and HTTP handler for ToDoService.
+func New(client proto.ToDoServiceClient, opts ...connect.HandlerOption) (string, http.Handler) {
+	return protoconnect.NewToDoServiceHandler(&handler{client: client}, opts...)
+}
+
+func (h *handler) CreateTask(ctx context.Context, req *connect.Request[proto.CreateTaskRequest]) (*connect.Response[proto.CreateTaskResponse], error) {
+	return unary(ctx, req, h.client.CreateTask)
+}
+
+func (h *handler) GetAllTasks(ctx context.Context, req *connect.Request[proto.GetAllTasksRequest]) (*connect.Response[proto.GetAllTasksResponse], error) {
+	return unary(ctx, req, h.client.GetAllTasks)
+}
+
+func (h *handler) GetTask(ctx context.Context, req *connect.Request[proto.GetTaskRequest]) (*connect.Response[proto.GetTaskResponse], error) {
+	return unary(ctx, req, h.client.GetTask)
+}
+
+func (h *handler) UpdateTask(ctx context.Context, req *connect.Request[proto.UpdateTaskRequest]) (*connect.Response[proto.UpdateTaskResponse], error) {
+	return unary(ctx, req, h.client.UpdateTask)
+}
+
+func (h *handler) UpdateTaskStatus(ctx context.Context, req *connect.Request[proto.UpdateTaskStatusRequest]) (*connect.Response[proto.UpdateTaskStatusResponse], error) {
+	return unary(ctx, req, h.client.UpdateTaskStatus)
+}
+
+func (h *handler) DeleteTask(ctx context.Context, req *connect.Request[proto.DeleteTaskRequest]) (*connect.Response[proto.DeleteTaskResponse], error) {
+	return unary(ctx, req, h.client.DeleteTask)
+}
+
+func (h *handler) BatchCreateTasks(ctx context.Context, req *connect.Request[proto.BatchCreateTasksRequest]) (*connect.Response[proto.BatchCreateTasksResponse], error) {
+	return unary(ctx, req, h.client.BatchCreateTasks)
+}
+
+func (h *handler) BatchUpdateTaskStatus(ctx context.Context, req *connect.Request[proto.BatchUpdateTaskStatusRequest]) (*connect.Response[proto.BatchUpdateTaskStatusResponse], error) {
+	return unary(ctx, req, h.client.BatchUpdateTaskStatus)
+}
+
+func (h *handler) BatchDeleteTasks(ctx context.Context, req *connect.Request[proto.BatchDeleteTasksRequest]) (*connect.Response[proto.BatchDeleteTasksResponse], error) {
+	return unary(ctx, req, h.client.BatchDeleteTasks)
+}
+
+func (h *handler) ExportDocument(ctx context.Context, req *connect.Request[proto.ExportDocumentRequest]) (*connect.Response[proto.ExportDocumentResponse], error) {
+	return unary(ctx, req, h.client.ExportDocument)
+}
+
+func (h *handler) ImportDocument(ctx context.Context, req *connect.Request[proto.ImportDocumentRequest]) (*connect.Response[proto.ImportDocumentResponse], error) {
+	return unary(ctx, req, h.client.ImportDocument)
+}
+
+func (h *handler) GetCalendarFeed(ctx context.Context, req *connect.Request[proto.GetCalendarFeedRequest]) (*connect.Response[proto.GetCalendarFeedResponse], error) {
+	return unary(ctx, req, h.client.GetCalendarFeed)
+}
+
+func (h *handler) ImportCalendar(ctx context.Context, req *connect.Request[proto.ImportCalendarRequest]) (*connect.Response[proto.ImportCalendarResponse], error) {
+	return unary(ctx, req, h.client.ImportCalendar)
+}
+
+func (h *handler) CreateWebhook(ctx context.Context, req *connect.Request[proto.CreateWebhookRequest]) (*connect.Response[proto.CreateWebhookResponse], error) {
+	return unary(ctx, req, h.client.CreateWebhook)
+}
+
+func (h *handler) ListWebhooks(ctx context.Context, req *connect.Request[proto.ListWebhooksRequest]) (*connect.Response[proto.ListWebhooksResponse], error) {
+	return unary(ctx, req, h.client.ListWebhooks)
+}
+
+func (h *handler) DeleteWebhook(ctx context.Context, req *connect.Request[proto.DeleteWebhookRequest]) (*connect.Response[proto.DeleteWebhookResponse], error) {
+	return unary(ctx, req, h.client.DeleteWebhook)
+}
+
+func (h *handler) ArchiveTask(ctx context.Context, req *connect.Request[proto.ArchiveTaskRequest]) (*connect.Response[proto.ArchiveTaskResponse], error) {
+	return unary(ctx, req, h.client.ArchiveTask)
+}
+
+func (h *handler) ListArchivedTasks(ctx context.Context, req *connect.Request[proto.ListArchivedTasksRequest]) (*connect.Response[proto.ListArchivedTasksResponse], error) {
+	return unary(ctx, req, h.client.ListArchivedTasks)
+}
+
+func (h *handler) RestoreTask(ctx context.Context, req *connect.Request[proto.RestoreTaskRequest]) (*connect.Response[proto.RestoreTaskResponse], error) {
+	return unary(ctx, req, h.client.RestoreTask)
+}
+
+func (h *handler) ListTrash(ctx context.Context, req *connect.Request[proto.ListTrashRequest]) (*connect.Response[proto.ListTrashResponse], error) {
+	return unary(ctx, req, h.client.ListTrash)
+}
+
+func (h *handler) UndeleteTask(ctx context.Context, req *connect.Request[proto.UndeleteTaskRequest]) (*connect.Response[proto.UndeleteTaskResponse], error) {
+	return unary(ctx, req, h.client.UndeleteTask)
+}
+
+func (h *handler) PurgeTask(ctx context.Context, req *connect.Request[proto.PurgeTaskRequest]) (*connect.Response[proto.PurgeTaskResponse], error) {
+	return unary(ctx, req, h.client.PurgeTask)
+}
+
+func (h *handler) ListAuditEntries(ctx context.Context, req *connect.Request[proto.ListAuditEntriesRequest]) (*connect.Response[proto.ListAuditEntriesResponse], error) {
+	return unary(ctx, req, h.client.ListAuditEntries)
+}
+
+func (h *handler) GetTaskHistory(ctx context.Context, req *connect.Request[proto.GetTaskHistoryRequest]) (*connect.Response[proto.GetTaskHistoryResponse], error) {
+	return unary(ctx, req, h.client.GetTaskHistory)
+}
+
+func (h *handler) SearchTasks(ctx context.Context, req *connect.Request[proto.SearchTasksRequest]) (*connect.Response[proto.SearchTasksResponse], error) {
+	return unary(ctx, req, h.client.SearchTasks)
+}
+
+func (h *handler) CreateView(ctx context.Context, req *connect.Request[proto.CreateViewRequest]) (*connect.Response[proto.CreateViewResponse], error) {
+	return unary(ctx, req, h.client.CreateView)
+}
+
+func (h *handler) GetView(ctx context.Context, req *connect.Request[proto.GetViewRequest]) (*connect.Response[proto.GetViewResponse], error) {
+	return unary(ctx, req, h.client.GetView)
+}
+
+func (h *handler) ListViews(ctx context.Context, req *connect.Request[proto.ListViewsRequest]) (*connect.Response[proto.ListViewsResponse], error) {
+	return unary(ctx, req, h.client.ListViews)
+}
+
+func (h *handler) UpdateView(ctx context.Context, req *connect.Request[proto.UpdateViewRequest]) (*connect.Response[proto.UpdateViewResponse], error) {
+	return unary(ctx, req, h.client.UpdateView)
+}
+
+func (h *handler) DeleteView(ctx context.Context, req *connect.Request[proto.DeleteViewRequest]) (*connect.Response[proto.DeleteViewResponse], error) {
+	return unary(ctx, req, h.client.DeleteView)
+}
+
+func (h *handler) ListTasksByView(ctx context.Context, req *connect.Request[proto.ListTasksByViewRequest]) (*connect.Response[proto.ListTasksByViewResponse], error) {
+	return unary(ctx, req, h.client.ListTasksByView)
+}
+
+func (h *handler) GetTaskStats(ctx context.Context, req *connect.Request[proto.GetTaskStatsRequest]) (*connect.Response[proto.GetTaskStatsResponse], error) {
+	return unary(ctx, req, h.client.GetTaskStats)
+}
+
+// ImportTasks relays a client stream. gRPC-Web clients cannot call it since
+// the protocol has no client streaming.
+func (h *handler) ImportTasks(ctx context.Context, stream *connect.ClientStream[proto.ImportTasksRequest]) (*connect.Response[proto.ImportTasksResponse], error) {
+	ctx, cancel := context.WithCancel(outgoingContext(ctx, stream.RequestHeader(), stream.Peer()))
+	defer cancel()
+
+	var header metadata.MD
+	upstream, err := h.client.ImportTasks(ctx, grpc.Header(&header))
+	if err != nil {
+		return nil, toConnectError(err, header)
+	}
+
+	for stream.Receive() {
+		// A failed Send means the server has already finished the call;
+		// CloseAndRecv returns its status.
+		if err := upstream.Send(stream.Msg()); err != nil {
+			break
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return nil, err
+	}
+
+	res, err := upstream.CloseAndRecv()
+	if err != nil {
+		return nil, toConnectError(err, header)
+	}
+
+	resp := connect.NewResponse(res)
+	copyMetadata(resp.Header(), header)
+	return resp, nil
+}
+
+func (h *handler) ExportTasks(ctx context.Context, req *connect.Request[proto.ExportTasksRequest], stream *connect.ServerStream[proto.ExportTasksResponse]) error {
+	ctx = outgoingContext(ctx, req.Header(), req.Peer())
+
+	downstream, err := h.client.ExportTasks(ctx, req.Msg)
+	if err != nil {
+		return toConnectError(err, nil)
+	}
+	header, err := downstream.Header()
+	if err == nil {
+		copyMetadata(stream.ResponseHeader(), header)
+	}
+
+	for {
+		res, err := downstream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return toConnectError(err, header)
+		}
+		if err := stream.Send(res); err != nil {
+			return err
+		}
+	}
+}
+
+type unaryCall[Req, Res any] func(context.Context, *Req, ...grpc.CallOption) (*Res, error)
+
+func unary[Req, Res any](ctx context.Context, req *connect.Request[Req], call unaryCall[Req, Res]) (*connect.Response[Res], error) {
+	ctx = outgoingContext(ctx, req.Header(), req.Peer())
+
+	var header metadata.MD
+	res, err := call(ctx, req.Msg, grpc.Header(&header))
+	if err != nil {
+		return nil, toConnectError(err, header)
+	}
+
+	resp := connect.NewResponse(res)
+	copyMetadata(resp.Header(), header)
+	return resp, nil
+}
+
+func outgoingContext(ctx context.Context, header http.Header, peer connect.Peer) context.Context {
+	md := metadata.MD{}
+	for _, key := range forwardedHeaders {
+		if value := header.Get(key); value != "" {
+			md.Set(key, value)
+		}
+	}
+
+	if host, _, err := net.SplitHostPort(peer.Addr); err == nil {
+		md.Set("x-forwarded-for", host)
+	}
+
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+func copyMetadata(header http.Header, md metadata.MD) {
+	for _, key := range returnedHeaders {
+		for _, value := range md.Get(key) {
+			header.Add(key, value)
+		}
+	}
+}
+
+// toConnectError keeps the gRPC status code and error details so that
+// browser clients see the same errors as native gRPC clients.
+func toConnectError(err error, header metadata.MD) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return connect.NewError(connect.CodeUnknown, err)
+	}
+
+	cerr := connect.NewError(connect.Code(st.Code()), errors.New(st.Message()))
+	for _, detail := range st.Proto().GetDetails() {
+		if d, err := connect.NewErrorDetail(detail); err == nil {
+			cerr.AddDetail(d)
+		}
+	}
+	copyMetadata(cerr.Meta(), header)
+
+	return cerr
+}
+
+// WithCORS lets browsers on the given origins call both the Connect and the
+// REST endpoints. With no origins configured next is returned unchanged.
+func WithCORS(origins []string, next http.Handler) http.Handler {
+	if len(origins) == 0 {
+		return next
+	}
+
+	return cors.New(cors.Options{
+		AllowedOrigins:   origins,
+		AllowedMethods:   append(connectcors.AllowedMethods(), http.MethodPatch, http.MethodDelete),
+		AllowedHeaders:   append(connectcors.AllowedHeaders(), "Authorization", "X-Request-Id"),
+		ExposedHeaders:   append(connectcors.ExposedHeaders(), "X-Request-Id", "Retry-After"),
+		AllowCredentials: true,
+		MaxAge:           7200,
+	}).Handler(next)
+}
@@ -0,0 +1,179 @@
+package connectapi
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"grpc-todo/proto"
+	"grpc-todo/proto/protoconnect"
+
+	"connectrpc.com/connect"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type stubServer struct {
+	proto.UnimplementedToDoServiceServer
+	md metadata.MD
+}
+
+func (s *stubServer) CreateTask(ctx context.Context, req *proto.CreateTaskRequest) (*proto.CreateTaskResponse, error) {
+	s.md, _ = metadata.FromIncomingContext(ctx)
+	_ = grpc.SetHeader(ctx, metadata.Pairs("x-request-id", "req-1"))
+	return &proto.CreateTaskResponse{Task: &proto.Task{Id: "1", Title: req.Title}}, nil
+}
+
+func (s *stubServer) DeleteTask(ctx context.Context, req *proto.DeleteTaskRequest) (*proto.DeleteTaskResponse, error) {
+	return nil, status.Errorf(codes.NotFound, "task with ID %s not found", req.Id)
+}
+
+func (s *stubServer) ImportTasks(stream grpc.ClientStreamingServer[proto.ImportTasksRequest, proto.ImportTasksResponse]) error {
+	res := &proto.ImportTasksResponse{}
+	for {
+		_, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(res)
+		}
+		if err != nil {
+			return err
+		}
+		res.Received++
+		res.Imported++
+	}
+}
+
+func (s *stubServer) ExportTasks(req *proto.ExportTasksRequest, stream grpc.ServerStreamingServer[proto.ExportTasksResponse]) error {
+	for _, title := range []string{"Task 1", "Task 2"} {
+		if err := stream.Send(&proto.ExportTasksResponse{Task: &proto.Task{Title: title}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setupServer(t *testing.T) (*httptest.Server, *stubServer) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	stub := &stubServer{}
+	grpcServer := grpc.NewServer()
+	proto.RegisterToDoServiceServer(grpcServer, stub)
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	mux := http.NewServeMux()
+	mux.Handle(New(proto.NewToDoServiceClient(conn)))
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv, stub
+}
+
+func TestConnect_CreateTask(t *testing.T) {
+	srv, stub := setupServer(t)
+	client := protoconnect.NewToDoServiceClient(srv.Client(), srv.URL)
+
+	req := connect.NewRequest(&proto.CreateTaskRequest{Title: "Task 1"})
+	req.Header().Set("Authorization", "Bearer secret")
+
+	resp, err := client.CreateTask(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+
+	if resp.Msg.Task.Title != "Task 1" {
+		t.Errorf("Expected title Task 1, got %s", resp.Msg.Task.Title)
+	}
+	if got := resp.Header().Get("X-Request-Id"); got != "req-1" {
+		t.Errorf("Expected X-Request-Id header, got %q", got)
+	}
+	if got := stub.md.Get("authorization"); len(got) == 0 || got[0] != "Bearer secret" {
+		t.Errorf("Expected authorization to be forwarded, got %v", got)
+	}
+	if got := stub.md.Get("x-forwarded-for"); len(got) == 0 || got[0] != "127.0.0.1" {
+		t.Errorf("Expected x-forwarded-for with the browser address, got %v", got)
+	}
+}
+
+func TestGRPCWeb_ErrorCode(t *testing.T) {
+	srv, _ := setupServer(t)
+	client := protoconnect.NewToDoServiceClient(srv.Client(), srv.URL, connect.WithGRPCWeb())
+
+	_, err := client.DeleteTask(context.Background(), connect.NewRequest(&proto.DeleteTaskRequest{Id: "abc"}))
+	if connect.CodeOf(err) != connect.CodeNotFound {
+		t.Errorf("Expected NotFound, got %v", err)
+	}
+}
+
+func TestConnect_ImportTasks(t *testing.T) {
+	srv, _ := setupServer(t)
+	client := protoconnect.NewToDoServiceClient(srv.Client(), srv.URL)
+
+	stream := client.ImportTasks(context.Background())
+	for _, title := range []string{"Task 1", "Task 2", "Task 3"} {
+		if err := stream.Send(&proto.ImportTasksRequest{Task: &proto.Task{Title: title}}); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+	}
+
+	resp, err := stream.CloseAndReceive()
+	if err != nil {
+		t.Fatalf("ImportTasks failed: %v", err)
+	}
+	if resp.Msg.Imported != 3 {
+		t.Errorf("Expected 3 imported tasks, got %d", resp.Msg.Imported)
+	}
+}
+
+func TestConnect_ExportTasks(t *testing.T) {
+	srv, _ := setupServer(t)
+	client := protoconnect.NewToDoServiceClient(srv.Client(), srv.URL)
+
+	stream, err := client.ExportTasks(context.Background(), connect.NewRequest(&proto.ExportTasksRequest{}))
+	if err != nil {
+		t.Fatalf("ExportTasks failed: %v", err)
+	}
+	defer stream.Close()
+
+	var titles []string
+	for stream.Receive() {
+		titles = append(titles, stream.Msg().Task.Title)
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("Receive failed: %v", err)
+	}
+	if len(titles) != 2 {
+		t.Errorf("Expected 2 exported tasks, got %v", titles)
+	}
+}
+
+func TestWithCORS(t *testing.T) {
+	handler := WithCORS([]string{"https://app.example.com"}, http.NotFoundHandler())
+
+	req := httptest.NewRequest(http.MethodOptions, "/todo.ToDoService/GetAllTasks", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	req.Header.Set("Access-Control-Request-Headers", "connect-protocol-version,content-type")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Errorf("Expected allowed origin in preflight response, got %q", got)
+	}
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// AuditEntry records one change to a task: who made it, through which
+// method, and the fields it changed.
+type AuditEntry struct {
+	Id        string
+	TaskId    string
+	Actor     string
+	Method    string
+	RequestId string
+	Changes   []FieldChange
+	Timestamp time.Time
+}
+
+// FieldChange is a task field before and after a change, formatted as text.
+// Before is empty for created tasks and After for deleted ones.
+type FieldChange struct {
+	Field  string
+	Before string
+	After  string
+}
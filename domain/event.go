@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// Task event names, used by webhooks and the outbox.
+const (
+	EventTaskCreated       = "task.created"
+	EventTaskUpdated       = "task.updated"
+	EventTaskStatusUpdated = "task.status_updated"
+	EventTaskDeleted       = "task.deleted"
+	EventTaskArchived      = "task.archived"
+	EventTaskRestored      = "task.restored"
+	EventTaskUndeleted     = "task.undeleted"
+	EventTaskPurged        = "task.purged"
+)
+
+// OutboxMessage is a task event stored in the same transaction as the change
+// it describes, waiting to be published. Id doubles as the idempotency key
+// consumers use to drop redelivered messages.
+type OutboxMessage struct {
+	Id     string
+	Event  string
+	TaskId string
+	Owner  string
+	// Payload is the task as JSON, as it was after the change or, for
+	// deletions, before it.
+	Payload   []byte
+	CreatedAt time.Time
+}
@@ -0,0 +1,54 @@
+package domain
+
+// StatusTransition records that a task's status changed from From to To.
+type StatusTransition struct {
+	TaskId    string
+	From      string
+	To        string
+	ChangedBy string
+	ChangedAt int64
+}
+
+// TimeInStatus adds up how many seconds the task spent in each status until
+// now, given its transitions oldest first. Before the first transition the
+// task counts as having been in that transition's From status since it was
+// created, or in its current status if there are none.
+func TimeInStatus(task *Task, transitions []*StatusTransition, now int64) map[string]int64 {
+	durations := make(map[string]int64)
+	status, since := task.Status, task.CreatedAt
+	if len(transitions) > 0 {
+		status = transitions[0].From
+	}
+	for _, t := range transitions {
+		durations[status] += max(t.ChangedAt-since, 0)
+		status, since = t.To, t.ChangedAt
+	}
+	durations[status] += max(now-since, 0)
+	return durations
+}
+
+// CycleTime is the number of seconds from the task first entering
+// IN_PROGRESS, or its creation if it was created in progress, to it last
+// entering DONE. It is zero unless the task is DONE and has been in
+// progress.
+func CycleTime(task *Task, transitions []*StatusTransition) int64 {
+	if task.Status != "DONE" || len(transitions) == 0 {
+		return 0
+	}
+	var started, done int64
+	if transitions[0].From == "IN_PROGRESS" {
+		started = task.CreatedAt
+	}
+	for _, t := range transitions {
+		if t.To == "IN_PROGRESS" && started == 0 {
+			started = t.ChangedAt
+		}
+		if t.To == "DONE" {
+			done = t.ChangedAt
+		}
+	}
+	if started == 0 || done < started {
+		return 0
+	}
+	return done - started
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// StatsQuery selects the window [Start, End), in Unix seconds, that task
+// statistics count created and completed tasks in, cut into buckets of
+// Bucket seconds from Start, and how many of the oldest open tasks to list.
+type StatsQuery struct {
+	Start      int64
+	End        int64
+	Bucket     int64
+	OldestOpen int
+}
+
+// StatsBucket counts the tasks created and completed from Start until the
+// next bucket.
+type StatsBucket struct {
+	Start     int64
+	Created   int64
+	Completed int64
+}
+
+// TaskStats summarizes the live tasks. StatusCounts counts all of them and
+// OldestOpen lists those not DONE, oldest first. The buckets cover the
+// window, and MedianTimeToDone, in seconds from creation to completion, the
+// tasks completed in it.
+type TaskStats struct {
+	StatusCounts     map[string]int64
+	Buckets          []StatsBucket
+	MedianTimeToDone int64
+	OldestOpen       []*Task
+}
+
+// EmptyBuckets returns the buckets of the window with nothing counted.
+func (q StatsQuery) EmptyBuckets() []StatsBucket {
+	if q.End <= q.Start {
+		return nil
+	}
+	n := (q.End-q.Start-1)/q.Bucket + 1
+	buckets := make([]StatsBucket, n)
+	for i := range buckets {
+		buckets[i].Start = q.Start + int64(i)*q.Bucket
+	}
+	return buckets
+}
+
+// BucketOf returns the index of the bucket that contains t, if the window
+// does.
+func (q StatsQuery) BucketOf(t int64) (int, bool) {
+	if t < q.Start || t >= q.End {
+		return 0, false
+	}
+	return int((t - q.Start) / q.Bucket), true
+}
+
+// Median returns the median of sorted values, the mean of the middle two
+// for an even number of them, or zero if there are none.
+func Median(sorted []int64) int64 {
+	n := len(sorted)
+	switch {
+	case n == 0:
+		return 0
+	case n%2 == 1:
+		return sorted[n/2]
+	default:
+		return (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+}
+
+// ComputeStats computes the statistics q selects over tasks, which must be
+// the live tasks, for stores that cannot compute them themselves.
+func ComputeStats(tasks []*Task, q StatsQuery) *TaskStats {
+	stats := &TaskStats{StatusCounts: make(map[string]int64), Buckets: q.EmptyBuckets()}
+	var durations []int64
+	var open []*Task
+	for _, t := range tasks {
+		stats.StatusCounts[t.Status]++
+		if i, ok := q.BucketOf(t.CreatedAt); ok {
+			stats.Buckets[i].Created++
+		}
+		if t.Status != "DONE" {
+			open = append(open, t)
+		} else if i, ok := q.BucketOf(t.CompletedAt); ok {
+			stats.Buckets[i].Completed++
+			durations = append(durations, t.CompletedAt-t.CreatedAt)
+		}
+	}
+
+	slices.Sort(durations)
+	stats.MedianTimeToDone = Median(durations)
+
+	slices.SortFunc(open, func(a, b *Task) int {
+		return cmp.Or(cmp.Compare(a.CreatedAt, b.CreatedAt), strings.Compare(a.Id, b.Id))
+	})
+	stats.OldestOpen = open[:min(q.OldestOpen, len(open))]
+	return stats
+}
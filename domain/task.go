@@ -6,4 +6,19 @@ type Task struct {
 	Description string
 	Status      string
 	CreatedAt   int64
+	Owner       string
+	// DueAt is zero when the task has no due date.
+	DueAt int64
+	// ICalUID is the UID of the calendar entry the task was imported from.
+	ICalUID string
+	// CompletedAt is when the task was last marked DONE, zero while it is
+	// not DONE.
+	CompletedAt int64
+	// ArchivedAt is when the task was moved to the archive, zero for live
+	// tasks.
+	ArchivedAt int64
+	// DeletedAt is when the task was moved to the trash, and DeletedBy the
+	// principal that did it. Both are zero for tasks not in the trash.
+	DeletedAt int64
+	DeletedBy string
 }
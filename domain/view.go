@@ -0,0 +1,18 @@
+package domain
+
+// SavedView is a named task filter and sort order. Filter and OrderBy are
+// written in version FilterVersion of the filter format, so that a view
+// saved before the format changed still reads the same.
+type SavedView struct {
+	Id            string
+	Owner         string
+	Name          string
+	Filter        string
+	OrderBy       string
+	FilterVersion int
+	// Shared views can be read and used by everyone, but changed only by
+	// their owner.
+	Shared    bool
+	CreatedAt int64
+	UpdatedAt int64
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+type Webhook struct {
+	Id     string
+	Owner  string
+	URL    string
+	Secret string
+	// Events limits the webhook to these events; empty means all of them.
+	Events    []string
+	CreatedAt int64
+}
+
+// WebhookDelivery is one event waiting in the outbox to be posted to one
+// webhook.
+type WebhookDelivery struct {
+	Id         string
+	WebhookId  string
+	Event      string
+	Data       []byte
+	OccurredAt int64
+	// Attempts counts the failed attempts so far.
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	// Dead is set once the delivery has failed too often to be retried.
+	Dead bool
+}
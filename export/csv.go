@@ -0,0 +1,84 @@
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"grpc-todo/domain"
+)
+
+var csvHeader = []string{"id", "title", "description", "status", "created_at", "due_at"}
+
+func writeCSV(w io.Writer, tasks []*domain.Task) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, t := range tasks {
+		err := cw.Write([]string{t.Id, t.Title, t.Description, t.Status, formatTime(t.CreatedAt), formatTime(t.DueAt)})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// readCSV matches columns by their header, so spreadsheets may reorder them
+// or add columns of their own. Only the title column is required.
+func readCSV(r io.Reader, rep *Report) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read CSV header: %v", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return fmt.Errorf("CSV header has no title column")
+	}
+
+	for {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			var perr *csv.ParseError
+			if errors.As(err, &perr) {
+				rep.problem(perr.StartLine, "", "%v", perr.Err)
+				continue
+			}
+			return err
+		}
+		line, _ := cr.FieldPos(0)
+
+		get := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(row) {
+				return row[i]
+			}
+			return ""
+		}
+		rep.add(record{
+			line:        line,
+			id:          strings.TrimSpace(get("id")),
+			title:       get("title"),
+			description: get("description"),
+			status:      get("status"),
+			createdAt:   get("created_at"),
+			dueAt:       get("due_at"),
+		})
+	}
+}
@@ -0,0 +1,300 @@
+// Package export converts tasks to and from the files people edit by hand:
+// CSV spreadsheets, JSON Lines, Markdown checklists and todo.txt. Parsing
+// never stops at a bad row; it returns a Report listing the tasks that would
+// be imported and every problem found, so callers can offer a dry run.
+package export
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"grpc-todo/domain"
+	"grpc-todo/proto"
+	"grpc-todo/validation"
+)
+
+type Format string
+
+const (
+	CSV      Format = "csv"
+	JSONL    Format = "jsonl"
+	Markdown Format = "markdown"
+	TodoTxt  Format = "todotxt"
+)
+
+// Formats lists the supported formats.
+func Formats() []Format {
+	return []Format{CSV, JSONL, Markdown, TodoTxt}
+}
+
+// ParseFormat accepts a format name or a common file extension.
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), ".")) {
+	case "csv":
+		return CSV, nil
+	case "jsonl", "ndjson":
+		return JSONL, nil
+	case "markdown", "md":
+		return Markdown, nil
+	case "todotxt", "todo.txt", "txt":
+		return TodoTxt, nil
+	}
+	return "", fmt.Errorf("unknown format %q, expected one of csv, jsonl, markdown, todotxt", name)
+}
+
+// FormatFromPath picks the format from a file name's extension.
+func FormatFromPath(path string) (Format, error) {
+	return ParseFormat(filepath.Ext(path))
+}
+
+// ContentType is the media type of files in the format.
+func (f Format) ContentType() string {
+	switch f {
+	case CSV:
+		return "text/csv; charset=utf-8"
+	case JSONL:
+		return "application/jsonl"
+	case Markdown:
+		return "text/markdown; charset=utf-8"
+	case TodoTxt:
+		return "text/plain; charset=utf-8"
+	}
+	return "application/octet-stream"
+}
+
+// Write serializes tasks in the given format.
+func Write(w io.Writer, format Format, tasks []*domain.Task) error {
+	switch format {
+	case CSV:
+		return writeCSV(w, tasks)
+	case JSONL:
+		return writeJSONL(w, tasks)
+	case Markdown:
+		return writeMarkdown(w, tasks)
+	case TodoTxt:
+		return writeTodoTxt(w, tasks)
+	}
+	return fmt.Errorf("unknown format %q", format)
+}
+
+// Read parses tasks in the given format. The error is only set when the input
+// cannot be read at all; invalid tasks are listed in the report instead.
+func Read(r io.Reader, format Format) (*Report, error) {
+	rep := &Report{Format: format, ids: make(map[string]int)}
+
+	var err error
+	switch format {
+	case CSV:
+		err = readCSV(r, rep)
+	case JSONL:
+		err = readJSONL(r, rep)
+	case Markdown:
+		err = readMarkdown(r, rep)
+	case TodoTxt:
+		err = readTodoTxt(r, rep)
+	default:
+		err = fmt.Errorf("unknown format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rep, nil
+}
+
+// Problem is a task that failed validation, located by its line in the input.
+type Problem struct {
+	Line    int
+	Field   string
+	Message string
+}
+
+func (p Problem) Error() string {
+	if p.Field == "" {
+		return fmt.Sprintf("line %d: %s", p.Line, p.Message)
+	}
+	return fmt.Sprintf("line %d: %s: %s", p.Line, p.Field, p.Message)
+}
+
+// Report is the outcome of parsing a file: the tasks that passed validation,
+// the lines they came from, and the problems that kept other tasks out.
+type Report struct {
+	Format   Format
+	Tasks    []*domain.Task
+	Lines    []int
+	Problems []Problem
+
+	ids map[string]int
+}
+
+// Valid reports whether every task in the input passed validation.
+func (r *Report) Valid() bool {
+	return len(r.Problems) == 0
+}
+
+// Summary is a one-line description of the report, e.g.
+// "3 tasks (DONE 1, TODO 2), 1 problem".
+func (r *Report) Summary() string {
+	counts := make(map[string]int)
+	for _, t := range r.Tasks {
+		counts[t.Status]++
+	}
+	statuses := make([]string, 0, len(counts))
+	for s := range counts {
+		statuses = append(statuses, s)
+	}
+	sort.Strings(statuses)
+
+	parts := make([]string, 0, len(statuses))
+	for _, s := range statuses {
+		parts = append(parts, fmt.Sprintf("%s %d", s, counts[s]))
+	}
+
+	summary := plural(len(r.Tasks), "task")
+	if len(parts) > 0 {
+		summary += " (" + strings.Join(parts, ", ") + ")"
+	}
+	return summary + ", " + plural(len(r.Problems), "problem")
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// record is one task as found in the input, before validation.
+type record struct {
+	line        int
+	id          string
+	title       string
+	description string
+	status      string
+	createdAt   string
+	completedAt string
+	dueAt       string
+}
+
+func (r *Report) problem(line int, field, format string, args ...any) {
+	r.Problems = append(r.Problems, Problem{Line: line, Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// add validates rec with the same rules the server applies to new tasks and
+// records either the task or its problems.
+func (r *Report) add(rec record) {
+	before := len(r.Problems)
+
+	err := validation.Validate(&proto.CreateTaskRequest{Title: rec.title, Description: rec.description})
+	if verr, ok := err.(*validation.Error); ok {
+		for _, v := range verr.Violations {
+			r.problem(rec.line, v.Field, "%s", v.Description)
+		}
+	}
+
+	status, err := ParseStatus(rec.status)
+	if err != nil {
+		r.problem(rec.line, "status", "%v", err)
+	}
+
+	if rec.id != "" {
+		if err := validation.Validate(&proto.GetTaskRequest{Id: rec.id}); err != nil {
+			r.problem(rec.line, "id", "must be a 24-character hex task ID")
+		} else if first, ok := r.ids[rec.id]; ok {
+			r.problem(rec.line, "id", "duplicate of line %d", first)
+		} else {
+			r.ids[rec.id] = rec.line
+		}
+	}
+
+	createdAt, err := parseTime(rec.createdAt)
+	if err != nil {
+		r.problem(rec.line, "created_at", "%v", err)
+	}
+
+	completedAt, err := parseTime(rec.completedAt)
+	if err != nil {
+		r.problem(rec.line, "completed_at", "%v", err)
+	}
+
+	dueAt, err := parseTime(rec.dueAt)
+	if err != nil {
+		r.problem(rec.line, "due_at", "%v", err)
+	}
+
+	if len(r.Problems) > before {
+		return
+	}
+
+	r.Tasks = append(r.Tasks, &domain.Task{
+		Id:          rec.id,
+		Title:       rec.title,
+		Description: rec.description,
+		Status:      status.String(),
+		CreatedAt:   createdAt,
+		CompletedAt: completedAt,
+		DueAt:       dueAt,
+	})
+	r.Lines = append(r.Lines, rec.line)
+}
+
+// statusAliases are the words people use in spreadsheets for our statuses.
+var statusAliases = map[string]proto.Status{
+	"OPEN":      proto.Status_TODO,
+	"NEW":       proto.Status_TODO,
+	"TO_DO":     proto.Status_TODO,
+	"DOING":     proto.Status_IN_PROGRESS,
+	"STARTED":   proto.Status_IN_PROGRESS,
+	"ON_HOLD":   proto.Status_PAUSED,
+	"BLOCKED":   proto.Status_PAUSED,
+	"COMPLETE":  proto.Status_DONE,
+	"COMPLETED": proto.Status_DONE,
+	"FINISHED":  proto.Status_DONE,
+}
+
+// ParseStatus maps a status as people write it, e.g. "done", "In progress"
+// or "IN_PROGRESS", onto proto.Status. An empty status means TODO.
+func ParseStatus(s string) (proto.Status, error) {
+	name := strings.ToUpper(strings.TrimSpace(s))
+	name = strings.NewReplacer("-", "_", " ", "_").Replace(name)
+	if name == "" {
+		return proto.Status_TODO, nil
+	}
+	if status, ok := statusAliases[name]; ok {
+		return status, nil
+	}
+	if value, ok := proto.Status_value[name]; ok && proto.Status(value) != proto.Status_UNKNOWN {
+		return proto.Status(value), nil
+	}
+	return proto.Status_UNKNOWN, fmt.Errorf("unknown status %q", s)
+}
+
+func formatTime(unix int64) string {
+	if unix == 0 {
+		return ""
+	}
+	return time.Unix(unix, 0).UTC().Format(time.RFC3339)
+}
+
+// parseTime accepts RFC 3339 timestamps, plain dates and Unix seconds. An
+// empty string yields zero, which means "not set".
+func parseTime(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.Unix(), nil
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t.Unix(), nil
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil && n >= 0 {
+		return n, nil
+	}
+	return 0, fmt.Errorf("invalid time %q, expected RFC 3339, YYYY-MM-DD or Unix seconds", s)
+}
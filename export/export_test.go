@@ -0,0 +1,175 @@
+package export
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"grpc-todo/domain"
+	"grpc-todo/proto"
+)
+
+func sampleTasks() []*domain.Task {
+	return []*domain.Task{
+		{Id: "5f1d7e4b9c2a3b0012345678", Title: "Write docs", Description: "README\nand examples", Status: "IN_PROGRESS", CreatedAt: 1714557600, DueAt: 1714730400},
+		{Id: "5f1d7e4b9c2a3b0012345679", Title: "Ship, \"v2\"", Status: "DONE", CreatedAt: 1714561200},
+		{Title: "Plan sprint", Status: "TODO"},
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, format := range Formats() {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, format, sampleTasks()); err != nil {
+				t.Fatalf("Write failed: %v", err)
+			}
+
+			rep, err := Read(&buf, format)
+			if err != nil {
+				t.Fatalf("Read failed: %v", err)
+			}
+			if !rep.Valid() {
+				t.Fatalf("Expected no problems, got %v", rep.Problems)
+			}
+
+			got := make(map[string]domain.Task)
+			for _, task := range rep.Tasks {
+				got[task.Title] = *task
+			}
+			for _, want := range sampleTasks() {
+				if !reflect.DeepEqual(got[want.Title], *want) {
+					t.Errorf("Expected %+v, got %+v", *want, got[want.Title])
+				}
+			}
+		})
+	}
+}
+
+func TestReadCSVReport(t *testing.T) {
+	input := "Title,Status,Owner\n" +
+		"Buy milk,done,alice\n" +
+		",todo,bob\n" +
+		"Call Bob,someday,carol\n"
+
+	rep, err := Read(strings.NewReader(input), CSV)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+
+	if len(rep.Tasks) != 1 || rep.Tasks[0].Status != "DONE" || rep.Lines[0] != 2 {
+		t.Errorf("Expected one DONE task from line 2, got %v", rep.Tasks)
+	}
+	if len(rep.Problems) != 2 {
+		t.Fatalf("Expected 2 problems, got %v", rep.Problems)
+	}
+	if p := rep.Problems[0]; p.Line != 3 || p.Field != "title" {
+		t.Errorf("Expected a title problem on line 3, got %v", p)
+	}
+	if p := rep.Problems[1]; p.Line != 4 || p.Field != "status" {
+		t.Errorf("Expected a status problem on line 4, got %v", p)
+	}
+	if got := rep.Summary(); got != "1 task (DONE 1), 2 problems" {
+		t.Errorf("Unexpected summary %q", got)
+	}
+
+	if _, err := Read(strings.NewReader("name\nx\n"), CSV); err == nil {
+		t.Errorf("Expected an error for a CSV without a title column")
+	}
+}
+
+func TestReadJSONLProblems(t *testing.T) {
+	input := `{"title": "One", "id": "5f1d7e4b9c2a3b0012345678"}` + "\n" +
+		`not json` + "\n" +
+		`{"title": "Two", "id": "5f1d7e4b9c2a3b0012345678", "created_at": "yesterday"}` + "\n"
+
+	rep, err := Read(strings.NewReader(input), JSONL)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+
+	if len(rep.Tasks) != 1 {
+		t.Errorf("Expected 1 task, got %d", len(rep.Tasks))
+	}
+	var fields []string
+	for _, p := range rep.Problems {
+		fields = append(fields, p.Field)
+	}
+	if strings.Join(fields, ",") != ",id,created_at" {
+		t.Errorf("Expected problems on the JSON, id and created_at, got %v", rep.Problems)
+	}
+}
+
+func TestReadMarkdownChecklist(t *testing.T) {
+	input := "# Sprint\n\n" +
+		"- [x] Release\n" +
+		"- [ ] Retro\n" +
+		"  notes from the team\n\n" +
+		"## Paused\n\n" +
+		"* [ ] Migrate\n"
+
+	rep, err := Read(strings.NewReader(input), Markdown)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+
+	want := []string{"Release DONE", "Retro TODO", "Migrate PAUSED"}
+	var got []string
+	for _, task := range rep.Tasks {
+		got = append(got, task.Title+" "+task.Status)
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if rep.Tasks[1].Description != "notes from the team" {
+		t.Errorf("Expected indented description, got %q", rep.Tasks[1].Description)
+	}
+}
+
+func TestTodoTxtRoundTrip(t *testing.T) {
+	tasks := []*domain.Task{
+		{Title: "Two  spaces", Status: "DONE", CreatedAt: 1714521600, CompletedAt: 1714640400},
+		{Title: "Midnight", Status: "DONE", CreatedAt: 1714521600, CompletedAt: 1714608000},
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, TodoTxt, tasks); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	rep, err := Read(&buf, TodoTxt)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if !rep.Valid() || len(rep.Tasks) != len(tasks) {
+		t.Fatalf("Expected %d tasks and no problems, got %v", len(tasks), rep.Problems)
+	}
+	for i, want := range tasks {
+		if !reflect.DeepEqual(rep.Tasks[i], want) {
+			t.Errorf("Expected %+v, got %+v", want, rep.Tasks[i])
+		}
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	cases := map[string]proto.Status{
+		"":            proto.Status_TODO,
+		"done":        proto.Status_DONE,
+		"In progress": proto.Status_IN_PROGRESS,
+		"in-progress": proto.Status_IN_PROGRESS,
+		"On hold":     proto.Status_PAUSED,
+		"PAUSED":      proto.Status_PAUSED,
+	}
+	for in, want := range cases {
+		got, err := ParseStatus(in)
+		if err != nil || got != want {
+			t.Errorf("ParseStatus(%q): expected %v, got %v (%v)", in, want, got, err)
+		}
+	}
+
+	for _, in := range []string{"UNKNOWN", "someday"} {
+		if _, err := ParseStatus(in); err == nil {
+			t.Errorf("ParseStatus(%q): expected an error", in)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"grpc-todo/domain"
+)
+
+type jsonTask struct {
+	ID          string `json:"id,omitempty"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Status      string `json:"status,omitempty"`
+	CreatedAt   string `json:"created_at,omitempty"`
+	DueAt       string `json:"due_at,omitempty"`
+}
+
+func writeJSONL(w io.Writer, tasks []*domain.Task) error {
+	enc := json.NewEncoder(w)
+	for _, t := range tasks {
+		err := enc.Encode(jsonTask{
+			ID:          t.Id,
+			Title:       t.Title,
+			Description: t.Description,
+			Status:      t.Status,
+			CreatedAt:   formatTime(t.CreatedAt),
+			DueAt:       formatTime(t.DueAt),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readJSONL(r io.Reader, rep *Report) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var jt jsonTask
+		if err := json.Unmarshal([]byte(text), &jt); err != nil {
+			rep.problem(line, "", "invalid JSON: %v", err)
+			continue
+		}
+		rep.add(record{
+			line:        line,
+			id:          jt.ID,
+			title:       jt.Title,
+			description: jt.Description,
+			status:      jt.Status,
+			createdAt:   jt.CreatedAt,
+			dueAt:       jt.DueAt,
+		})
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,163 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"grpc-todo/domain"
+	"grpc-todo/proto"
+)
+
+// Markdown checklists group tasks under one heading per status:
+//
+//	## IN_PROGRESS
+//
+//	- [ ] Write docs <!-- id:5f1d7e4b9c2a3b0012345678 created:2024-05-01T10:00:00Z -->
+//	  README and examples
+//
+// The checkbox is ticked for DONE tasks, descriptions are indented under
+// their task, and the HTML comment, which renderers hide, keeps the ID,
+// creation time and due date so that a checklist can be imported back.
+var (
+	checklistItem = regexp.MustCompile(`^[-*+] \[([ xX])\] (.*)$`)
+	itemMeta      = regexp.MustCompile(`\s*<!--\s*(.*?)\s*-->\s*$`)
+	statusHeading = regexp.MustCompile(`^#{1,6}\s+(.*?)\s*#*$`)
+)
+
+var markdownOrder = []proto.Status{
+	proto.Status_TODO,
+	proto.Status_IN_PROGRESS,
+	proto.Status_PAUSED,
+	proto.Status_DONE,
+}
+
+func writeMarkdown(w io.Writer, tasks []*domain.Task) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "# Tasks")
+
+	for _, status := range markdownOrder {
+		var group []*domain.Task
+		for _, t := range tasks {
+			if t.Status == status.String() || status == proto.Status_TODO && !knownStatus(t.Status) {
+				group = append(group, t)
+			}
+		}
+		if len(group) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(bw, "\n## %s\n\n", status)
+		for _, t := range group {
+			box := " "
+			if status == proto.Status_DONE {
+				box = "x"
+			}
+			fmt.Fprintf(bw, "- [%s] %s", box, oneLine(t.Title))
+			if meta := itemComment(t); meta != "" {
+				fmt.Fprintf(bw, " <!-- %s -->", meta)
+			}
+			fmt.Fprintln(bw)
+			if t.Description != "" {
+				for _, line := range strings.Split(t.Description, "\n") {
+					fmt.Fprintf(bw, "  %s\n", line)
+				}
+			}
+		}
+	}
+
+	return bw.Flush()
+}
+
+func knownStatus(s string) bool {
+	_, err := ParseStatus(s)
+	return s != "" && err == nil
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func itemComment(t *domain.Task) string {
+	var parts []string
+	if t.Id != "" {
+		parts = append(parts, "id:"+t.Id)
+	}
+	if t.CreatedAt != 0 {
+		parts = append(parts, "created:"+formatTime(t.CreatedAt))
+	}
+	if t.DueAt != 0 {
+		parts = append(parts, "due:"+formatTime(t.DueAt))
+	}
+	return strings.Join(parts, " ")
+}
+
+func readMarkdown(r io.Reader, rep *Report) error {
+	scanner := bufio.NewScanner(r)
+
+	heading := proto.Status_TODO
+	var current *record
+	flush := func() {
+		if current != nil {
+			current.description = strings.TrimRight(current.description, "\n")
+			rep.add(*current)
+			current = nil
+		}
+	}
+
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+
+		if m := checklistItem.FindStringSubmatch(text); m != nil {
+			flush()
+			current = &record{line: line, status: heading.String()}
+			if m[1] != " " {
+				current.status = proto.Status_DONE.String()
+			} else if heading == proto.Status_DONE {
+				current.status = proto.Status_TODO.String()
+			}
+
+			title := m[2]
+			if meta := itemMeta.FindStringSubmatch(title); meta != nil {
+				title = title[:len(title)-len(meta[0])]
+				for _, field := range strings.Fields(meta[1]) {
+					key, value, _ := strings.Cut(field, ":")
+					switch key {
+					case "id":
+						current.id = value
+					case "created":
+						current.createdAt = value
+					case "due":
+						current.dueAt = value
+					}
+				}
+			}
+			current.title = strings.TrimSpace(title)
+			continue
+		}
+
+		if current != nil && strings.HasPrefix(text, "  ") {
+			current.description += strings.TrimPrefix(text, "  ") + "\n"
+			continue
+		}
+
+		if m := statusHeading.FindStringSubmatch(text); m != nil {
+			flush()
+			if status, err := ParseStatus(m[1]); err == nil {
+				heading = status
+			} else {
+				heading = proto.Status_TODO
+			}
+			continue
+		}
+
+		if strings.TrimSpace(text) != "" {
+			flush()
+		}
+	}
+	flush()
+
+	return scanner.Err()
+}
@@ -0,0 +1,55 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"grpc-todo/domain"
+	"grpc-todo/todotxt"
+)
+
+func writeTodoTxt(w io.Writer, tasks []*domain.Task) error {
+	bw := bufio.NewWriter(w)
+	for _, t := range tasks {
+		fmt.Fprintln(bw, todotxt.FromTask(t))
+	}
+	return bw.Flush()
+}
+
+func readTodoTxt(r io.Reader, rep *Report) error {
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		it, err := todotxt.Parse(scanner.Text())
+		if err != nil {
+			rep.problem(line, "", "%v", err)
+			continue
+		}
+
+		t := it.Task()
+		rec := record{
+			line:        line,
+			id:          t.Id,
+			title:       t.Title,
+			description: t.Description,
+			status:      t.Status,
+		}
+		if t.CreatedAt != 0 {
+			rec.createdAt = strconv.FormatInt(t.CreatedAt, 10)
+		}
+		if t.CompletedAt != 0 {
+			rec.completedAt = strconv.FormatInt(t.CompletedAt, 10)
+		}
+		if t.DueAt != 0 {
+			rec.dueAt = strconv.FormatInt(t.DueAt, 10)
+		}
+		rep.add(rec)
+	}
+	return scanner.Err()
+}
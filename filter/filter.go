@@ -0,0 +1,160 @@
+// Package filter parses AIP-160 style filter expressions over task fields,
+// such as `status:IN_PROGRESS AND created>2026-01-01 AND title~"deploy"`,
+// into an AST that does not depend on where tasks are stored, and
+// translates the AST into Mongo and SQL queries or evaluates it in memory.
+package filter
+
+import (
+	"fmt"
+	"strings"
+
+	"grpc-todo/domain"
+)
+
+// Op is a comparison operator.
+type Op string
+
+const (
+	Eq Op = "="
+	Ne Op = "!="
+	Lt Op = "<"
+	Le Op = "<="
+	Gt Op = ">"
+	Ge Op = ">="
+	// Contains matches text fields that contain the value, ignoring case.
+	Contains Op = "~"
+)
+
+// Expr is a node of a parsed filter: And, Or, Not or Compare.
+type Expr interface {
+	expr()
+}
+
+// And matches tasks that every expression matches.
+type And []Expr
+
+// Or matches tasks that any expression matches.
+type Or []Expr
+
+// Not matches the tasks X does not.
+type Not struct {
+	X Expr
+}
+
+// Compare compares a field with a value: a string for text and enum fields,
+// and Unix seconds as an int64 for time fields. A time field that is not set
+// satisfies only Ne.
+type Compare struct {
+	Field string
+	Op    Op
+	Value any
+}
+
+func (And) expr()     {}
+func (Or) expr()      {}
+func (Not) expr()     {}
+func (Compare) expr() {}
+
+// Kind is the type of a field, which decides the operators it takes and how
+// its values are read.
+type Kind int
+
+const (
+	// Text fields take =, !=, : and ~.
+	Text Kind = iota
+	// Enum fields take =, != and : with one of the field's values, ignoring
+	// case.
+	Enum
+	// Time fields take every operator but ~ with a date (YYYY-MM-DD, UTC
+	// midnight), an RFC 3339 time or Unix seconds.
+	Time
+)
+
+type Field struct {
+	Kind Kind
+	// Values are the values of an Enum field.
+	Values []string
+}
+
+// Schema is the fields a filter may use, by name.
+type Schema map[string]Field
+
+// Tasks is the schema of task filters.
+var Tasks = Schema{
+	"title":       {Kind: Text},
+	"description": {Kind: Text},
+	"owner":       {Kind: Text},
+	"status":      {Kind: Enum, Values: []string{"TODO", "IN_PROGRESS", "PAUSED", "DONE"}},
+	"created":     {Kind: Time},
+	"due":         {Kind: Time},
+	"completed":   {Kind: Time},
+}
+
+// Error is a filter that could not be parsed. Column is the position of
+// Token, the offending token, counted in characters from 1.
+type Error struct {
+	Column int
+	Token  string
+	Msg    string
+}
+
+func (e *Error) Error() string {
+	if e.Token == "" {
+		return fmt.Sprintf("column %d: %s", e.Column, e.Msg)
+	}
+	return fmt.Sprintf("column %d: %s at %q", e.Column, e.Msg, e.Token)
+}
+
+// String returns the expression in the syntax Parse reads.
+func String(e Expr) string {
+	switch e := e.(type) {
+	case And:
+		return join(e, " AND ")
+	case Or:
+		return join(e, " OR ")
+	case Not:
+		return "NOT " + String(e.X)
+	case Compare:
+		if s, ok := e.Value.(string); ok {
+			return fmt.Sprintf("%s%s%s", e.Field, e.Op, quote(s))
+		}
+		return fmt.Sprintf("%s%s%v", e.Field, e.Op, e.Value)
+	}
+	return ""
+}
+
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func join(exprs []Expr, sep string) string {
+	parts := make([]string, len(exprs))
+	for i, e := range exprs {
+		parts[i] = String(e)
+	}
+	return "(" + strings.Join(parts, sep) + ")"
+}
+
+// TaskValue returns the value of a field of the Tasks schema for t, in the
+// form Match expects.
+func TaskValue(t *domain.Task) func(field string) any {
+	return func(field string) any {
+		switch field {
+		case "title":
+			return t.Title
+		case "description":
+			return t.Description
+		case "owner":
+			return t.Owner
+		case "status":
+			return t.Status
+		case "created":
+			return t.CreatedAt
+		case "due":
+			return t.DueAt
+		case "completed":
+			return t.CompletedAt
+		}
+		return nil
+	}
+}
@@ -0,0 +1,217 @@
+package filter
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"grpc-todo/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var jan1 = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		filter string
+		want   Expr
+	}{
+		{"", nil},
+		{"status:in-progress", Compare{"status", Eq, "IN_PROGRESS"}},
+		{
+			`status:IN_PROGRESS AND created>2026-01-01 AND title~"deploy"`,
+			And{Compare{"status", Eq, "IN_PROGRESS"}, Compare{"created", Gt, jan1}, Compare{"title", Contains, "deploy"}},
+		},
+		{
+			// OR binds tighter than AND and than juxtaposition.
+			"status=TODO OR status=DONE due<=2026-01-01T00:00:00Z",
+			And{Or{Compare{"status", Eq, "TODO"}, Compare{"status", Eq, "DONE"}}, Compare{"due", Le, jan1}},
+		},
+		{
+			`-owner="bob \"b\"" NOT (due!=0 AND completed>=1)`,
+			And{Not{Compare{"owner", Eq, `bob "b"`}}, Not{And{Compare{"due", Ne, int64(0)}, Compare{"completed", Ge, int64(1)}}}},
+		},
+	} {
+		got, err := Parse(tc.filter, Tasks)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", tc.filter, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Parse(%q): expected %#v, got %#v", tc.filter, tc.want, got)
+		}
+		if got == nil {
+			continue
+		}
+		// String writes what Parse reads.
+		again, err := Parse(String(got), Tasks)
+		if err != nil || !reflect.DeepEqual(again, got) {
+			t.Errorf("Expected %q to parse back to %#v, got %#v (%v)", String(got), got, again, err)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, tc := range []struct {
+		filter string
+		column int
+		token  string
+	}{
+		{"colour=red", 1, "colour"},
+		{"status=DONE AND title<x", 22, "<"},
+		{"status=FINISHED", 8, "FINISHED"},
+		{"created>yesterday", 9, "yesterday"},
+		{"title=", 7, ""},
+		{"status=DONE AND", 16, ""},
+		{"(status=DONE", 13, ""},
+		{"status=DONE)", 12, ")"},
+		{`title~"deploy`, 7, `"deploy`},
+		{"title", 6, ""},
+		{"título=x", 1, "título"},
+	} {
+		_, err := Parse(tc.filter, Tasks)
+		var ferr *Error
+		if !errors.As(err, &ferr) {
+			t.Errorf("Parse(%q): expected an *Error, got %v", tc.filter, err)
+			continue
+		}
+		if ferr.Column != tc.column || ferr.Token != tc.token {
+			t.Errorf("Parse(%q): expected column %d at %q, got %v", tc.filter, tc.column, tc.token, ferr)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	e, err := Parse(`status:TODO AND (created>2026-01-01 OR NOT title~"50%")`, Tasks)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	names := map[string]string{"status": "status", "created": "created_at", "title": "title"}
+
+	wantBSON := bson.M{"$and": bson.A{
+		bson.M{"status": "TODO"},
+		bson.M{"$or": bson.A{
+			bson.M{"created_at": bson.M{"$gt": jan1}},
+			bson.M{"$nor": bson.A{bson.M{"title": primitive.Regex{Pattern: "50%", Options: "i"}}}},
+		}},
+	}}
+	if got := Mongo(e, names); !reflect.DeepEqual(got, wantBSON) {
+		t.Errorf("Expected %v, got %v", wantBSON, got)
+	}
+
+	where, args := SQL(e, names)
+	wantWhere := `((status IS NOT NULL AND status = ?) AND ((created_at IS NOT NULL AND created_at > ?) OR ` +
+		`NOT (title IS NOT NULL AND LOWER(title) LIKE ? ESCAPE '\')))`
+	if where != wantWhere {
+		t.Errorf("Expected %s, got %s", wantWhere, where)
+	}
+	if want := []any{"TODO", jan1, `%50\%%`}; !slices.Equal(args, want) {
+		t.Errorf("Expected args %v, got %v", want, args)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tasks := []*domain.Task{
+		{Id: "1", Title: "Deploy website", Status: "IN_PROGRESS", CreatedAt: jan1 + 3600},
+		{Id: "2", Title: "Deploy API", Status: "IN_PROGRESS", CreatedAt: jan1 - 3600, DueAt: jan1},
+		{Id: "3", Title: "Write report", Status: "DONE", CreatedAt: jan1 + 3600},
+	}
+	for _, tc := range []struct {
+		filter string
+		want   []string
+	}{
+		{`status:IN_PROGRESS AND created>2026-01-01 AND title~"DEPLOY"`, []string{"1"}},
+		{"due<2026-02-01", []string{"2"}},
+		// Tasks with no due date only satisfy !=.
+		{"NOT due<2026-02-01", []string{"1", "3"}},
+		{"due!=2026-01-01", []string{"1", "3"}},
+		{"status=DONE OR title=\"Deploy API\"", []string{"2", "3"}},
+		{"", []string{"1", "2", "3"}},
+	} {
+		e, err := Parse(tc.filter, Tasks)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", tc.filter, err)
+		}
+		var got []string
+		for _, task := range tasks {
+			if Match(e, TaskValue(task)) {
+				got = append(got, task.Id)
+			}
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("%q: expected %v, got %v", tc.filter, tc.want, got)
+		}
+	}
+}
+
+func TestParseOrderBy(t *testing.T) {
+	keys, err := ParseOrderBy(" due, created DESC,title asc ", Tasks)
+	if err != nil {
+		t.Fatalf("ParseOrderBy failed: %v", err)
+	}
+	want := []OrderKey{{"due", false}, {"created", true}, {"title", false}}
+	if !slices.Equal(keys, want) {
+		t.Fatalf("Expected %v, got %v", want, keys)
+	}
+	if got := OrderByString(keys); got != "due, created desc, title" {
+		t.Errorf("Expected the canonical order, got %q", got)
+	}
+	if got := MongoSort(keys, map[string]string{"due": "due_at", "created": "created_at", "title": "title"}); !reflect.DeepEqual(got,
+		bson.D{{Key: "due_at", Value: 1}, {Key: "created_at", Value: -1}, {Key: "title", Value: 1}}) {
+		t.Errorf("Unexpected sort %v", got)
+	}
+
+	for _, tc := range []struct {
+		order  string
+		column int
+		token  string
+	}{
+		{"due,colour", 5, "colour"},
+		{"due sideways", 5, "sideways"},
+		{"due,,title", 5, ","},
+		{"due, due desc", 6, "due"},
+		{"due desc title", 10, "title"},
+	} {
+		_, err := ParseOrderBy(tc.order, Tasks)
+		var ferr *Error
+		if !errors.As(err, &ferr) || ferr.Column != tc.column || ferr.Token != tc.token {
+			t.Errorf("ParseOrderBy(%q): expected an error at column %d %q, got %v", tc.order, tc.column, tc.token, err)
+		}
+	}
+
+	tasks := []*domain.Task{
+		{Id: "1", Title: "b", DueAt: jan1},
+		{Id: "2", Title: "a"},
+		{Id: "3", Title: "c", DueAt: jan1},
+	}
+	keys, _ = ParseOrderBy("due desc, title", Tasks)
+	slices.SortFunc(tasks, func(a, b *domain.Task) int { return CompareOrder(keys, TaskValue(a), TaskValue(b)) })
+	if got := []string{tasks[0].Id, tasks[1].Id, tasks[2].Id}; !slices.Equal(got, []string{"1", "3", "2"}) {
+		t.Errorf("Expected tasks with no due date last, got %v", got)
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	// Pretend version 2 renamed created to created_at.
+	renamed[2] = map[string]string{"created": "created_at"}
+	defer delete(renamed, 2)
+	if got := upgradeField("created", 1, 2); got != "created_at" {
+		t.Errorf("Expected created to become created_at, got %q", got)
+	}
+	if got := upgradeField("created", 2, 2); got != "created" {
+		t.Errorf("Expected no rename within a version, got %q", got)
+	}
+
+	for _, version := range []int{0, Version + 1} {
+		if _, err := ParseVersion("status=DONE", version, Tasks); err == nil {
+			t.Errorf("Expected version %d to be rejected", version)
+		}
+		if _, err := ParseOrderByVersion("due", version, Tasks); err == nil {
+			t.Errorf("Expected version %d to be rejected", version)
+		}
+	}
+}
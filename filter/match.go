@@ -0,0 +1,65 @@
+package filter
+
+import (
+	"cmp"
+	"strings"
+)
+
+// Match reports whether e matches the values value returns for each field:
+// a string for text and enum fields and Unix seconds as an int64, zero when
+// not set, for time fields. It agrees with the Mongo and SQL translations. A
+// nil e matches everything.
+func Match(e Expr, value func(field string) any) bool {
+	switch e := e.(type) {
+	case And:
+		for _, x := range e {
+			if !Match(x, value) {
+				return false
+			}
+		}
+		return true
+	case Or:
+		for _, x := range e {
+			if Match(x, value) {
+				return true
+			}
+		}
+		return false
+	case Not:
+		return !Match(e.X, value)
+	case Compare:
+		switch v := value(e.Field).(type) {
+		case string:
+			if e.Op == Contains {
+				return strings.Contains(strings.ToLower(v), strings.ToLower(e.Value.(string)))
+			}
+			return compare(cmp.Compare(v, e.Value.(string)), e.Op)
+		case int64:
+			if v == 0 {
+				return e.Op == Ne
+			}
+			return compare(cmp.Compare(v, e.Value.(int64)), e.Op)
+		}
+	}
+	return true
+}
+
+// compare reports whether c, the result of comparing a field with a value,
+// satisfies op.
+func compare(c int, op Op) bool {
+	switch op {
+	case Eq:
+		return c == 0
+	case Ne:
+		return c != 0
+	case Lt:
+		return c < 0
+	case Le:
+		return c <= 0
+	case Gt:
+		return c > 0
+	case Ge:
+		return c >= 0
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package filter
+
+import (
+	"regexp"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var mongoOps = map[Op]string{Ne: "$ne", Lt: "$lt", Le: "$lte", Gt: "$gt", Ge: "$gte"}
+
+// Mongo translates e into a query filter, naming each field by its key in
+// keys. Time fields that are not set must be missing from the documents, as
+// a missing field only satisfies $ne. A nil e matches every document.
+func Mongo(e Expr, keys map[string]string) bson.M {
+	switch e := e.(type) {
+	case And:
+		return bson.M{"$and": mongoAll(e, keys)}
+	case Or:
+		return bson.M{"$or": mongoAll(e, keys)}
+	case Not:
+		return bson.M{"$nor": bson.A{Mongo(e.X, keys)}}
+	case Compare:
+		key := keys[e.Field]
+		switch e.Op {
+		case Eq:
+			return bson.M{key: e.Value}
+		case Contains:
+			return bson.M{key: primitive.Regex{Pattern: regexp.QuoteMeta(e.Value.(string)), Options: "i"}}
+		default:
+			return bson.M{key: bson.M{mongoOps[e.Op]: e.Value}}
+		}
+	}
+	return bson.M{}
+}
+
+func mongoAll(exprs []Expr, keys map[string]string) bson.A {
+	res := make(bson.A, len(exprs))
+	for i, e := range exprs {
+		res[i] = Mongo(e, keys)
+	}
+	return res
+}
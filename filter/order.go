@@ -0,0 +1,150 @@
+package filter
+
+import (
+	"cmp"
+	"strings"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// OrderKey is a field to sort by.
+type OrderKey struct {
+	Field string
+	Desc  bool
+}
+
+// ParseOrderBy reads an AIP-132 style order, a comma-separated list of
+// fields of schema each optionally followed by "asc" or "desc", such as
+// "due, created desc". Errors are *Error.
+func ParseOrderBy(s string, schema Schema) ([]OrderKey, error) {
+	return ParseOrderByVersion(s, Version, schema)
+}
+
+// ParseOrderByVersion parses an order written in an earlier version of the
+// format, as ParseVersion does a filter.
+func ParseOrderByVersion(s string, version int, schema Schema) ([]OrderKey, error) {
+	if err := checkVersion(version); err != nil {
+		return nil, err
+	}
+
+	var keys []OrderKey
+	seen := make(map[string]bool)
+	runes := []rune(s)
+	start := 0
+	for i := 0; i <= len(runes); i++ {
+		if i < len(runes) && runes[i] != ',' {
+			continue
+		}
+		words := fields(runes[start:i], start)
+		switch {
+		case len(words) == 0 && strings.TrimSpace(s) == "":
+			return nil, nil
+		case len(words) == 0:
+			return nil, &Error{Column: i + 1, Token: string(runes[start:min(i+1, len(runes))]), Msg: "expected a field"}
+		case len(words) > 2:
+			return nil, errorAt(words[2], "expected \",\"")
+		}
+
+		key := OrderKey{Field: upgradeField(words[0].text, version, Version)}
+		if _, ok := schema[key.Field]; !ok {
+			return nil, errorAt(words[0], "unknown field, expected one of %s", strings.Join(fieldNames(schema), ", "))
+		}
+		if seen[key.Field] {
+			return nil, errorAt(words[0], "field already ordered by")
+		}
+		seen[key.Field] = true
+		if len(words) == 2 {
+			switch strings.ToLower(words[1].text) {
+			case "asc":
+			case "desc":
+				key.Desc = true
+			default:
+				return nil, errorAt(words[1], "expected asc or desc")
+			}
+		}
+		keys = append(keys, key)
+		start = i + 1
+	}
+	return keys, nil
+}
+
+// fields splits runes, which start at offset in the order, into words.
+func fields(runes []rune, offset int) []token {
+	var words []token
+	start := -1
+	for i := 0; i <= len(runes); i++ {
+		if i < len(runes) && !unicode.IsSpace(runes[i]) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			words = append(words, token{kind: tokWord, text: string(runes[start:i]), column: offset + start + 1})
+			start = -1
+		}
+	}
+	return words
+}
+
+// OrderByString returns keys in the form ParseOrderBy reads.
+func OrderByString(keys []OrderKey) string {
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k.Field
+		if k.Desc {
+			parts[i] += " desc"
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// MongoSort translates keys into a sort document, naming each field by its
+// key in keys. Missing fields sort first, as zero values do in CompareOrder.
+func MongoSort(order []OrderKey, keys map[string]string) bson.D {
+	sort := make(bson.D, len(order))
+	for i, k := range order {
+		sort[i] = bson.E{Key: keys[k.Field], Value: 1}
+		if k.Desc {
+			sort[i].Value = -1
+		}
+	}
+	return sort
+}
+
+// SQLOrderBy translates keys into the list of an ORDER BY clause, naming
+// each field by its column in columns. NULLs sort first, as zero values do
+// in CompareOrder.
+func SQLOrderBy(order []OrderKey, columns map[string]string) string {
+	parts := make([]string, len(order))
+	for i, k := range order {
+		if k.Desc {
+			parts[i] = columns[k.Field] + " DESC NULLS LAST"
+		} else {
+			parts[i] = columns[k.Field] + " ASC NULLS FIRST"
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// CompareOrder compares two tasks, given by functions that return their
+// field values as Match expects, in the order of keys.
+func CompareOrder(order []OrderKey, a, b func(field string) any) int {
+	for _, k := range order {
+		var c int
+		switch va := a(k.Field).(type) {
+		case string:
+			c = cmp.Compare(va, b(k.Field).(string))
+		case int64:
+			c = cmp.Compare(va, b(k.Field).(int64))
+		}
+		if k.Desc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
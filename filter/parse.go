@@ -0,0 +1,419 @@
+package filter
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// maxDepth bounds the nesting of parentheses and NOTs.
+const maxDepth = 32
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+	tokMinus
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	// column is where the token starts, in characters from 1.
+	column int
+}
+
+func (t token) keyword(k string) bool {
+	return t.kind == tokWord && t.text == k
+}
+
+// lexer splits a filter into tokens. A comparison's value is read up to the
+// next space or parenthesis, so that times such as 2026-01-01T09:00:00Z
+// need no quotes.
+type lexer struct {
+	src []rune
+	pos int
+}
+
+func isOpRune(r rune) bool {
+	return strings.ContainsRune("=!<>:~", r)
+}
+
+func (l *lexer) skipSpace() {
+	for l.pos < len(l.src) && unicode.IsSpace(l.src[l.pos]) {
+		l.pos++
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipSpace()
+	start := l.pos
+	tok := token{column: start + 1}
+	if l.pos == len(l.src) {
+		return tok, nil
+	}
+
+	switch r := l.src[l.pos]; {
+	case r == '(':
+		l.pos++
+		tok.kind, tok.text = tokLParen, "("
+	case r == ')':
+		l.pos++
+		tok.kind, tok.text = tokRParen, ")"
+	case r == '-':
+		l.pos++
+		tok.kind, tok.text = tokMinus, "-"
+	case r == '"':
+		return l.quoted()
+	case isOpRune(r):
+		for l.pos < len(l.src) && isOpRune(l.src[l.pos]) {
+			l.pos++
+		}
+		tok.kind, tok.text = tokOp, string(l.src[start:l.pos])
+	default:
+		for l.pos < len(l.src) && !unicode.IsSpace(l.src[l.pos]) && !isOpRune(l.src[l.pos]) &&
+			!strings.ContainsRune(`()"`, l.src[l.pos]) {
+			l.pos++
+		}
+		tok.kind, tok.text = tokWord, string(l.src[start:l.pos])
+	}
+	return tok, nil
+}
+
+// value reads the value of a comparison.
+func (l *lexer) value() (token, error) {
+	l.skipSpace()
+	if l.pos < len(l.src) && l.src[l.pos] == '"' {
+		return l.quoted()
+	}
+	start := l.pos
+	for l.pos < len(l.src) && !unicode.IsSpace(l.src[l.pos]) && !strings.ContainsRune("()", l.src[l.pos]) {
+		l.pos++
+	}
+	if l.pos == start {
+		return token{column: start + 1}, nil
+	}
+	return token{kind: tokWord, text: string(l.src[start:l.pos]), column: start + 1}, nil
+}
+
+// quoted reads a double-quoted string, in which \" and \\ stand for " and
+// \.
+func (l *lexer) quoted() (token, error) {
+	start := l.pos
+	var b strings.Builder
+	for l.pos++; l.pos < len(l.src); l.pos++ {
+		switch r := l.src[l.pos]; r {
+		case '"':
+			l.pos++
+			return token{kind: tokString, text: b.String(), column: start + 1}, nil
+		case '\\':
+			if l.pos+1 < len(l.src) {
+				l.pos++
+				r = l.src[l.pos]
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return token{}, &Error{Column: start + 1, Token: string(l.src[start:]), Msg: "unterminated string"}
+}
+
+type parser struct {
+	lex     lexer
+	schema  Schema
+	version int
+	tok     token
+	depth   int
+}
+
+// Parse reads a filter over the fields of schema:
+//
+//	expression  = sequence {"AND" sequence}
+//	sequence    = factor {factor}
+//	factor      = term {"OR" term}
+//	term        = ["NOT" | "-"] simple
+//	simple      = "(" expression ")" | field comparator value
+//	comparator  = "=" | "!=" | "<" | "<=" | ">" | ">=" | ":" | "~"
+//
+// As in AIP-160, OR binds tighter than AND, terms next to each other must
+// all match, and ":" compares like "=". Values may be quoted with double
+// quotes. An empty filter parses to nil, which matches every task. Errors
+// are *Error.
+func Parse(s string, schema Schema) (Expr, error) {
+	return ParseVersion(s, Version, schema)
+}
+
+// ParseVersion parses a filter written in an earlier version of the format,
+// reading fields by the names they had then. The expression it returns uses
+// the current names.
+func ParseVersion(s string, version int, schema Schema) (Expr, error) {
+	if err := checkVersion(version); err != nil {
+		return nil, err
+	}
+	p := &parser{lex: lexer{src: []rune(s)}, schema: schema, version: version}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokEOF {
+		return nil, nil
+	}
+	e, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("unexpected %s", p.tok.describe())
+	}
+	return e, nil
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) errorf(msg string, args ...any) error {
+	return errorAt(p.tok, msg, args...)
+}
+
+func errorAt(tok token, msg string, args ...any) error {
+	return &Error{Column: tok.column, Token: tok.text, Msg: fmt.Sprintf(msg, args...)}
+}
+
+func (t token) describe() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of filter"
+	case tokString:
+		return "string"
+	default:
+		return "token"
+	}
+}
+
+func (p *parser) expression() (Expr, error) {
+	var and And
+	for {
+		e, err := p.sequence()
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, e)
+		if !p.tok.keyword("AND") {
+			break
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	return flatten(and), nil
+}
+
+// sequence reads terms next to each other, up to AND, ")" or the end.
+func (p *parser) sequence() (Expr, error) {
+	var and And
+	for {
+		e, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, e)
+		if p.tok.kind == tokEOF || p.tok.kind == tokRParen || p.tok.keyword("AND") {
+			break
+		}
+	}
+	return flatten(and), nil
+}
+
+func (p *parser) factor() (Expr, error) {
+	var or Or
+	for {
+		e, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		or = append(or, e)
+		if !p.tok.keyword("OR") {
+			break
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if len(or) == 1 {
+		return or[0], nil
+	}
+	return or, nil
+}
+
+func (p *parser) term() (Expr, error) {
+	if !p.tok.keyword("NOT") && p.tok.kind != tokMinus {
+		return p.simple()
+	}
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	e, err := p.simple()
+	if err != nil {
+		return nil, err
+	}
+	return Not{X: e}, nil
+}
+
+func (p *parser) enter() error {
+	if p.depth == maxDepth {
+		return p.errorf("filter nests too deeply")
+	}
+	p.depth++
+	return nil
+}
+
+func (p *parser) leave() {
+	p.depth--
+}
+
+func (p *parser) simple() (Expr, error) {
+	switch {
+	case p.tok.kind == tokLParen:
+		if err := p.enter(); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		e, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokRParen {
+			return nil, p.errorf("expected \")\"")
+		}
+		return e, p.advance()
+	case p.tok.kind == tokWord && !p.tok.keyword("AND") && !p.tok.keyword("OR") && !p.tok.keyword("NOT"):
+		return p.restriction()
+	case p.tok.kind == tokEOF:
+		return nil, p.errorf("unexpected end of filter, expected a comparison")
+	default:
+		return nil, p.errorf("expected a comparison such as status=DONE")
+	}
+}
+
+func (p *parser) restriction() (Expr, error) {
+	name := p.tok
+	name.text = upgradeField(name.text, p.version, Version)
+	field, ok := p.schema[name.text]
+	if !ok {
+		return nil, errorAt(p.tok, "unknown field, expected one of %s", strings.Join(fieldNames(p.schema), ", "))
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	opTok := p.tok
+	if opTok.kind != tokOp {
+		return nil, p.errorf("expected a comparator after %s", name.text)
+	}
+	op := Op(opTok.text)
+	switch op {
+	case ":":
+		op = Eq
+	case Eq, Ne, Lt, Le, Gt, Ge, Contains:
+	default:
+		return nil, errorAt(opTok, "unknown comparator")
+	}
+	if !field.takes(op) {
+		return nil, errorAt(opTok, "%s cannot be compared with %s", name.text, opTok.text)
+	}
+
+	valTok, err := p.lex.value()
+	if err != nil {
+		return nil, err
+	}
+	if valTok.kind == tokEOF {
+		return nil, errorAt(valTok, "expected a value after %s%s", name.text, opTok.text)
+	}
+	value, err := field.parse(valTok)
+	if err != nil {
+		return nil, err
+	}
+	return Compare{Field: name.text, Op: op, Value: value}, p.advance()
+}
+
+func (f Field) takes(op Op) bool {
+	switch f.Kind {
+	case Text:
+		return op == Eq || op == Ne || op == Contains
+	case Enum:
+		return op == Eq || op == Ne
+	default:
+		return op != Contains
+	}
+}
+
+func (f Field) parse(tok token) (any, error) {
+	switch f.Kind {
+	case Enum:
+		v := strings.ToUpper(strings.ReplaceAll(tok.text, "-", "_"))
+		if !slices.Contains(f.Values, v) {
+			return nil, errorAt(tok, "invalid value, expected one of %s", strings.Join(f.Values, ", "))
+		}
+		return v, nil
+	case Time:
+		if t, err := time.Parse(time.DateOnly, tok.text); err == nil {
+			return t.Unix(), nil
+		}
+		if t, err := time.Parse(time.RFC3339, tok.text); err == nil {
+			return t.Unix(), nil
+		}
+		if n, err := strconv.ParseInt(tok.text, 10, 64); err == nil {
+			return n, nil
+		}
+		return nil, errorAt(tok, "invalid time, expected YYYY-MM-DD, RFC 3339 or Unix seconds")
+	default:
+		return tok.text, nil
+	}
+}
+
+func fieldNames(schema Schema) []string {
+	names := make([]string, 0, len(schema))
+	for name := range schema {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// flatten returns the only expression of a one-element And, and merges
+// nested Ands.
+func flatten(and And) Expr {
+	if len(and) == 1 {
+		return and[0]
+	}
+	var res And
+	for _, e := range and {
+		if inner, ok := e.(And); ok {
+			res = append(res, inner...)
+		} else {
+			res = append(res, e)
+		}
+	}
+	return res
+}
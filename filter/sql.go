@@ -0,0 +1,56 @@
+package filter
+
+import (
+	"strings"
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SQL translates e into the condition of a WHERE clause with a ? placeholder
+// for each of args, naming each field by its column in columns. Time fields
+// that are not set must be NULL. Every comparison is true or false, never
+// NULL, so that NOT inverts it as it does in Mongo. A nil e translates to
+// "TRUE".
+func SQL(e Expr, columns map[string]string) (where string, args []any) {
+	var b strings.Builder
+	writeSQL(&b, &args, e, columns)
+	return b.String(), args
+}
+
+func writeSQL(b *strings.Builder, args *[]any, e Expr, columns map[string]string) {
+	switch e := e.(type) {
+	case And:
+		writeSQLAll(b, args, e, " AND ", columns)
+	case Or:
+		writeSQLAll(b, args, e, " OR ", columns)
+	case Not:
+		b.WriteString("NOT ")
+		writeSQL(b, args, e.X, columns)
+	case Compare:
+		col := columns[e.Field]
+		switch e.Op {
+		case Ne:
+			b.WriteString("(" + col + " IS NULL OR " + col + " <> ?)")
+			*args = append(*args, e.Value)
+		case Contains:
+			b.WriteString("(" + col + " IS NOT NULL AND LOWER(" + col + `) LIKE ? ESCAPE '\')`)
+			*args = append(*args, "%"+likeEscaper.Replace(strings.ToLower(e.Value.(string)))+"%")
+		default:
+			b.WriteString("(" + col + " IS NOT NULL AND " + col + " " + string(e.Op) + " ?)")
+			*args = append(*args, e.Value)
+		}
+	default:
+		b.WriteString("TRUE")
+	}
+}
+
+func writeSQLAll(b *strings.Builder, args *[]any, exprs []Expr, sep string, columns map[string]string) {
+	b.WriteString("(")
+	for i, e := range exprs {
+		if i > 0 {
+			b.WriteString(sep)
+		}
+		writeSQL(b, args, e, columns)
+	}
+	b.WriteString(")")
+}
@@ -0,0 +1,30 @@
+package filter
+
+import "fmt"
+
+// Version is the version of the filter and order-by format that Parse and
+// ParseOrderBy read. Stored filters record the version they were written
+// in, so that they keep working when a later version renames fields.
+const Version = 1
+
+// renamed holds, for each version after the first, the fields it renamed,
+// by their names in the version before.
+var renamed = map[int]map[string]string{}
+
+// upgradeField returns the name in version to of a field named name in
+// version from.
+func upgradeField(name string, from, to int) string {
+	for v := from + 1; v <= to; v++ {
+		if newName, ok := renamed[v][name]; ok {
+			name = newName
+		}
+	}
+	return name
+}
+
+func checkVersion(version int) error {
+	if version < 1 || version > Version {
+		return fmt.Errorf("unsupported filter version %d, expected 1 to %d", version, Version)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"net/textproto"
+	"strings"
+
+	"grpc-todo/proto"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// forwardedHeaders are passed between HTTP and gRPC metadata unchanged, so
+// that REST clients see the same request IDs and retry hints as gRPC clients.
+var forwardedHeaders = map[string]bool{
+	"x-request-id": true,
+	"retry-after":  true,
+}
+
+// New returns an HTTP handler that translates the REST routes declared in
+// todo.proto into calls to the gRPC server listening on grpcAddr. Going
+// through the gRPC server keeps authentication, validation, rate limiting
+// and error-code mapping identical for both transports.
+func New(ctx context.Context, grpcAddr string) (http.Handler, error) {
+	mux := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(incomingHeaderMatcher),
+		runtime.WithOutgoingHeaderMatcher(outgoingHeaderMatcher),
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions: protojson.MarshalOptions{
+				EmitUnpopulated: true,
+			},
+			UnmarshalOptions: protojson.UnmarshalOptions{
+				DiscardUnknown: true,
+			},
+		}),
+	)
+
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	if err := proto.RegisterToDoServiceHandlerFromEndpoint(ctx, mux, grpcAddr, opts); err != nil {
+		return nil, err
+	}
+
+	return dropForwardedFor(mux), nil
+}
+
+// dropForwardedFor removes the X-Forwarded-For header clients send, so that
+// the one the gateway passes on holds only the address the request came
+// from. The gRPC server trusts that address to rate limit and log clients.
+func dropForwardedFor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Header.Del("X-Forwarded-For")
+		next.ServeHTTP(w, r)
+	})
+}
+
+func incomingHeaderMatcher(key string) (string, bool) {
+	if forwardedHeaders[strings.ToLower(key)] {
+		return key, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+func outgoingHeaderMatcher(key string) (string, bool) {
+	if forwardedHeaders[strings.ToLower(key)] {
+		return textproto.CanonicalMIMEHeaderKey(key), true
+	}
+	return runtime.MetadataHeaderPrefix + key, true
+}
@@ -0,0 +1,163 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"grpc-todo/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type stubServer struct {
+	proto.UnimplementedToDoServiceServer
+	md       metadata.MD
+	allTasks *proto.GetAllTasksRequest
+}
+
+func (s *stubServer) CreateTask(ctx context.Context, req *proto.CreateTaskRequest) (*proto.CreateTaskResponse, error) {
+	s.md, _ = metadata.FromIncomingContext(ctx)
+	_ = grpc.SetHeader(ctx, metadata.Pairs("x-request-id", "req-1"))
+	return &proto.CreateTaskResponse{Task: &proto.Task{Id: "1", Title: req.Title, Status: proto.Status_TODO}}, nil
+}
+
+func (s *stubServer) DeleteTask(ctx context.Context, req *proto.DeleteTaskRequest) (*proto.DeleteTaskResponse, error) {
+	return nil, status.Errorf(codes.NotFound, "task with ID %s not found", req.Id)
+}
+
+// GetAllTasks records the request and then stays unimplemented.
+func (s *stubServer) GetAllTasks(ctx context.Context, req *proto.GetAllTasksRequest) (*proto.GetAllTasksResponse, error) {
+	s.allTasks = req
+	return s.UnimplementedToDoServiceServer.GetAllTasks(ctx, req)
+}
+
+func setupGateway(t *testing.T) (*httptest.Server, *stubServer) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	stub := &stubServer{}
+	grpcServer := grpc.NewServer()
+	proto.RegisterToDoServiceServer(grpcServer, stub)
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	handler, err := New(ctx, lis.Addr().String())
+	if err != nil {
+		t.Fatalf("Failed to create gateway: %v", err)
+	}
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return srv, stub
+}
+
+func TestGateway_CreateTask(t *testing.T) {
+	srv, stub := setupGateway(t)
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/v1/tasks", strings.NewReader(`{"title": "Task 1"}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("X-Request-Id", "from-client")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST /v1/tasks failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Task struct {
+			Title  string `json:"title"`
+			Status string `json:"status"`
+		} `json:"task"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if body.Task.Title != "Task 1" || body.Task.Status != "TODO" {
+		t.Errorf("Unexpected task in response: %+v", body.Task)
+	}
+
+	if got := stub.md.Get("authorization"); len(got) == 0 || got[0] != "Bearer secret" {
+		t.Errorf("Expected authorization to be forwarded, got %v", got)
+	}
+	if got := stub.md.Get("x-request-id"); len(got) == 0 || got[0] != "from-client" {
+		t.Errorf("Expected x-request-id to be forwarded, got %v", got)
+	}
+	if got := resp.Header.Get("X-Request-Id"); got != "req-1" {
+		t.Errorf("Expected X-Request-Id response header, got %q", got)
+	}
+}
+
+func TestGateway_SpoofedForwardedFor(t *testing.T) {
+	srv, stub := setupGateway(t)
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/v1/tasks", strings.NewReader(`{"title": "Task 1"}`))
+	req.Header.Set("X-Forwarded-For", "203.0.113.7, 198.51.100.1")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST /v1/tasks failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if got := stub.md.Get("x-forwarded-for"); len(got) != 1 || got[0] != "127.0.0.1" {
+		t.Errorf("Expected x-forwarded-for to hold only the client address, got %v", got)
+	}
+}
+
+func TestGateway_GetAllTasksAsOf(t *testing.T) {
+	srv, stub := setupGateway(t)
+
+	resp, err := http.Get(srv.URL + "/v1/tasks?as_of=1714521600&filter=status:DONE")
+	if err != nil {
+		t.Fatalf("GET /v1/tasks failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if stub.allTasks.GetAsOf() != 1714521600 || stub.allTasks.GetFilter() != "status:DONE" {
+		t.Errorf("Expected as_of and filter to reach the server, got %v", stub.allTasks)
+	}
+}
+
+func TestGateway_ErrorMapping(t *testing.T) {
+	srv, _ := setupGateway(t)
+
+	req, _ := http.NewRequest(http.MethodDelete, srv.URL+"/v1/tasks/abc", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("DELETE /v1/tasks/abc failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for codes.NotFound, got %d", resp.StatusCode)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, srv.URL+"/v1/tasks", nil)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /v1/tasks failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotImplemented {
+		t.Errorf("Expected 501 for codes.Unimplemented, got %d", resp.StatusCode)
+	}
+}
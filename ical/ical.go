@@ -0,0 +1,363 @@
+// Package ical converts tasks to and from iCalendar (RFC 5545) VTODO
+// components.
+//
+// Tasks keep their identity across calendars through the UID: tasks created
+// here get "<task ID>@grpc-todo", and tasks imported from elsewhere keep the
+// UID they arrived with. STATUS has no PAUSED value, so paused tasks are
+// exported as IN-PROCESS with an X-GRPC-TODO-STATUS property that restores
+// them on import.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"grpc-todo/domain"
+	"grpc-todo/proto"
+)
+
+const (
+	prodID    = "-//grpc-todo//ToDoService//EN"
+	uidDomain = "grpc-todo"
+
+	statusProperty = "X-GRPC-TODO-STATUS"
+
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102T150405"
+
+	// maxLineOctets is the longest content line RFC 5545 allows before it
+	// must be folded.
+	maxLineOctets = 75
+)
+
+var taskID = regexp.MustCompile(`^[0-9a-f]{24}$`)
+
+// TaskUID is the UID of the VTODO for a task.
+func TaskUID(t *domain.Task) string {
+	if t.ICalUID != "" {
+		return t.ICalUID
+	}
+	return t.Id + "@" + uidDomain
+}
+
+// TaskID returns the ID of the task a UID written by TaskUID refers to.
+func TaskID(uid string) (string, bool) {
+	id, ok := strings.CutSuffix(uid, "@"+uidDomain)
+	if !ok || !taskID.MatchString(id) {
+		return "", false
+	}
+	return id, true
+}
+
+// Encode writes tasks as a VCALENDAR with one VTODO per task. now is used
+// for DTSTAMP.
+func Encode(w io.Writer, tasks []*domain.Task, now time.Time) error {
+	bw := bufio.NewWriter(w)
+	line := func(name, value string) {
+		writeFolded(bw, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", prodID)
+	line("CALSCALE", "GREGORIAN")
+	line("X-WR-CALNAME", "Tasks")
+
+	stamp := formatDateTime(now.Unix())
+	for _, t := range tasks {
+		line("BEGIN", "VTODO")
+		line("UID", escapeText(TaskUID(t)))
+		line("DTSTAMP", stamp)
+		if t.CreatedAt != 0 {
+			line("CREATED", formatDateTime(t.CreatedAt))
+		}
+		line("SUMMARY", escapeText(t.Title))
+		if t.Description != "" {
+			line("DESCRIPTION", escapeText(t.Description))
+		}
+		if t.DueAt != 0 {
+			line("DUE", formatDateTime(t.DueAt))
+		}
+
+		switch t.Status {
+		case proto.Status_IN_PROGRESS.String():
+			line("STATUS", "IN-PROCESS")
+		case proto.Status_PAUSED.String():
+			line("STATUS", "IN-PROCESS")
+			line(statusProperty, t.Status)
+		case proto.Status_DONE.String():
+			line("STATUS", "COMPLETED")
+			line("PERCENT-COMPLETE", "100")
+		default:
+			line("STATUS", "NEEDS-ACTION")
+		}
+		line("END", "VTODO")
+	}
+
+	line("END", "VCALENDAR")
+	return bw.Flush()
+}
+
+func formatDateTime(unix int64) string {
+	return time.Unix(unix, 0).UTC().Format(dateTimeLayout) + "Z"
+}
+
+// writeFolded writes a content line, folding it so that no physical line is
+// longer than 75 octets and no UTF-8 sequence is split.
+func writeFolded(w *bufio.Writer, s string) {
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.WriteString(s[:cut])
+		w.WriteString("\r\n ")
+		s = s[cut:]
+		// Continuation lines start with a space, which counts.
+		limit = maxLineOctets - 1
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+func unescapeText(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// Todo is a VTODO read from a calendar.
+type Todo struct {
+	// Line is the line of its BEGIN:VTODO, for error reports.
+	Line int
+	UID  string
+	// Task holds the fields of the VTODO. Its ID is set when the UID was
+	// written by TaskUID.
+	Task *domain.Task
+	// Err is the first property that could not be read. The other fields
+	// are still filled in.
+	Err error
+}
+
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Decode reads the VTODO components of a calendar. Other components, and
+// components nested inside a VTODO such as VALARM, are skipped.
+func Decode(r io.Reader) ([]Todo, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var todos []Todo
+	var current *Todo
+	var status, override string
+	depth := 0
+
+	for _, l := range lines {
+		p, ok := parseProperty(l.text)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case p.name == "BEGIN" && current == nil:
+			if strings.EqualFold(p.value, "VTODO") {
+				current = &Todo{Line: l.number, Task: &domain.Task{}}
+				status, override = "", ""
+			}
+			continue
+		case p.name == "BEGIN":
+			depth++
+			continue
+		case p.name == "END" && depth > 0:
+			depth--
+			continue
+		case p.name == "END" && current != nil:
+			current.Task.Status = taskStatus(status, override)
+			if id, ok := TaskID(current.UID); ok {
+				current.Task.Id = id
+			}
+			todos = append(todos, *current)
+			current = nil
+			continue
+		}
+		if current == nil || depth > 0 {
+			continue
+		}
+
+		var err error
+		switch p.name {
+		case "UID":
+			current.UID = unescapeText(p.value)
+		case "SUMMARY":
+			current.Task.Title = unescapeText(p.value)
+		case "DESCRIPTION":
+			current.Task.Description = unescapeText(p.value)
+		case "STATUS":
+			status = strings.ToUpper(p.value)
+		case statusProperty:
+			override = strings.ToUpper(p.value)
+		case "CREATED":
+			current.Task.CreatedAt, err = parseDateTime(p)
+		case "DUE":
+			current.Task.DueAt, err = parseDateTime(p)
+		}
+		if err != nil && current.Err == nil {
+			current.Err = fmt.Errorf("%s: %v", p.name, err)
+		}
+	}
+
+	if current != nil {
+		return todos, fmt.Errorf("line %d: VTODO is not terminated", current.Line)
+	}
+	return todos, nil
+}
+
+func taskStatus(status, override string) string {
+	if v, ok := proto.Status_value[override]; ok && proto.Status(v) != proto.Status_UNKNOWN {
+		return override
+	}
+	switch status {
+	case "IN-PROCESS":
+		return proto.Status_IN_PROGRESS.String()
+	case "COMPLETED", "CANCELLED":
+		return proto.Status_DONE.String()
+	}
+	return proto.Status_TODO.String()
+}
+
+type contentLine struct {
+	number int
+	text   string
+}
+
+// unfold joins folded lines and remembers where each logical line started.
+func unfold(r io.Reader) ([]contentLine, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var lines []contentLine
+	for n := 1; scanner.Scan(); n++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1].text += text[1:]
+			continue
+		}
+		if text != "" {
+			lines = append(lines, contentLine{number: n, text: text})
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// parseProperty splits `NAME;PARAM=VALUE:value`, honouring quoted parameter
+// values that contain colons or semicolons.
+func parseProperty(line string) (property, bool) {
+	inQuotes := false
+	sep := -1
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '"':
+			inQuotes = !inQuotes
+		case ':':
+			if !inQuotes {
+				sep = i
+			}
+		}
+		if sep >= 0 {
+			break
+		}
+	}
+	if sep <= 0 {
+		return property{}, false
+	}
+
+	p := property{value: line[sep+1:], params: make(map[string]string)}
+	parts := splitParams(line[:sep])
+	p.name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		p.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+	return p, true
+}
+
+func splitParams(s string) []string {
+	var parts []string
+	inQuotes := false
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			inQuotes = !inQuotes
+		case ';':
+			if !inQuotes {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// parseDateTime reads DATE and DATE-TIME values. Dates are midnight UTC;
+// local times use their TZID, or UTC when it is missing or unknown.
+func parseDateTime(p property) (int64, error) {
+	value := strings.TrimSpace(p.value)
+	if strings.EqualFold(p.params["VALUE"], "DATE") || len(value) == len(dateLayout) {
+		t, err := time.Parse(dateLayout, value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid date %q", value)
+		}
+		return t.Unix(), nil
+	}
+
+	if utc, ok := strings.CutSuffix(value, "Z"); ok {
+		t, err := time.Parse(dateTimeLayout, utc)
+		if err != nil {
+			return 0, fmt.Errorf("invalid date-time %q", value)
+		}
+		return t.Unix(), nil
+	}
+
+	loc := time.UTC
+	if tzid := p.params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	t, err := time.ParseInLocation(dateTimeLayout, value, loc)
+	if err != nil {
+		return 0, fmt.Errorf("invalid date-time %q", value)
+	}
+	return t.Unix(), nil
+}
@@ -0,0 +1,106 @@
+package ical
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"grpc-todo/domain"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	tasks := []*domain.Task{
+		{Id: "5f1d7e4b9c2a3b0012345678", Title: "Write docs; then, ship", Description: "README\nand examples " + strings.Repeat("é", 60), Status: "PAUSED", CreatedAt: 1714557600, DueAt: 1714730400},
+		{Id: "5f1d7e4b9c2a3b0012345679", Title: "Sync", Status: "DONE", CreatedAt: 1714557600, ICalUID: "abc-123@example.com"},
+	}
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, tasks, time.Unix(1714600000, 0)); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	for _, line := range strings.Split(buf.String(), "\r\n") {
+		if len(line) > maxLineOctets {
+			t.Errorf("Line longer than %d octets: %q", maxLineOctets, line)
+		}
+	}
+	for _, want := range []string{"STATUS:IN-PROCESS", "DUE:20240503T100000Z", "CREATED:20240501T100000Z", `SUMMARY:Write docs\; then\, ship`, "UID:abc-123@example.com"} {
+		if !strings.Contains(buf.String(), want+"\r\n") {
+			t.Errorf("Expected %s in\n%s", want, buf.String())
+		}
+	}
+
+	todos, err := Decode(&buf)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if len(todos) != 2 {
+		t.Fatalf("Expected 2 todos, got %d", len(todos))
+	}
+
+	want := *tasks[0]
+	if got := *todos[0].Task; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+	if todos[1].UID != "abc-123@example.com" || todos[1].Task.Id != "" || todos[1].Task.Status != "DONE" {
+		t.Errorf("Expected the foreign UID to be kept without a task ID, got %+v", todos[1])
+	}
+}
+
+func TestDecodeForeignCalendar(t *testing.T) {
+	input := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VTODO\r\n" +
+		"UID:42@other\r\n" +
+		"SUMMARY:Buy\r\n  milk\r\n" +
+		"DUE;VALUE=DATE:20240503\r\n" +
+		"CREATED;TZID=\"Europe/Berlin\":20240501T120000\r\n" +
+		"BEGIN:VALARM\r\nSUMMARY:Reminder\r\nEND:VALARM\r\n" +
+		"END:VTODO\r\n" +
+		"BEGIN:VTODO\r\n" +
+		"UID:43@other\r\n" +
+		"SUMMARY:Broken\r\n" +
+		"DUE:tomorrow\r\n" +
+		"STATUS:CANCELLED\r\n" +
+		"END:VTODO\r\n" +
+		"END:VCALENDAR\r\n"
+
+	todos, err := Decode(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if len(todos) != 2 {
+		t.Fatalf("Expected 2 todos, got %d", len(todos))
+	}
+
+	first := todos[0]
+	if first.Line != 2 || first.Task.Title != "Buy milk" || first.Task.Status != "TODO" {
+		t.Errorf("Expected the first todo from line 2, got %+v", first)
+	}
+	if first.Task.DueAt != time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC).Unix() {
+		t.Errorf("Expected a date-only due date at midnight UTC, got %d", first.Task.DueAt)
+	}
+	if first.Err != nil {
+		t.Errorf("Expected no error, got %v", first.Err)
+	}
+
+	if todos[1].Err == nil || todos[1].Task.Status != "DONE" {
+		t.Errorf("Expected a DUE error on a DONE todo, got %+v", todos[1])
+	}
+
+	if _, err := Decode(strings.NewReader("BEGIN:VTODO\r\nSUMMARY:x\r\n")); err == nil {
+		t.Errorf("Expected an error for an unterminated VTODO")
+	}
+}
+
+func TestTaskID(t *testing.T) {
+	if id, ok := TaskID("5f1d7e4b9c2a3b0012345678@grpc-todo"); !ok || id != "5f1d7e4b9c2a3b0012345678" {
+		t.Errorf("Expected our UID to map to a task ID, got %q", id)
+	}
+	for _, uid := range []string{"5f1d7e4b9c2a3b0012345678@other", "x@grpc-todo"} {
+		if _, ok := TaskID(uid); ok {
+			t.Errorf("TaskID(%q): expected no task ID", uid)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package interceptor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"testing"
+
+	"grpc-todo/auth"
+	"grpc-todo/logging"
+	"grpc-todo/proto"
+	"grpc-todo/ratelimit"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+var testInfo = &grpc.UnaryServerInfo{FullMethod: "/todo.ToDoService/CreateTask"}
+
+func chain(interceptors ...grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		next := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			ic, h := interceptors[i], next
+			next = func(ctx context.Context, req any) (any, error) {
+				return ic(ctx, req, info, h)
+			}
+		}
+		return next(ctx, req)
+	}
+}
+
+func TestUnaryLogging(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	authn := auth.NewAuthenticator(map[string]string{"secret": "alice"})
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"x-request-id", "req-1",
+		"authorization", "Bearer secret",
+	))
+
+	var seenRequestID string
+	_, err := chain(UnaryLogging(logger), UnaryAuth(authn))(ctx, nil, testInfo, func(ctx context.Context, _ any) (any, error) {
+		seenRequestID = logging.RequestIDFromContext(ctx)
+		return nil, status.Error(codes.NotFound, "missing")
+	})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("Expected NotFound, got %v", err)
+	}
+
+	if seenRequestID != "req-1" {
+		t.Errorf("Expected request ID req-1 in handler context, got %q", seenRequestID)
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to decode log line %q: %v", buf.String(), err)
+	}
+
+	expected := map[string]any{
+		"request_id": "req-1",
+		"method":     testInfo.FullMethod,
+		"principal":  "alice",
+		"code":       "NotFound",
+	}
+	for key, value := range expected {
+		if entry[key] != value {
+			t.Errorf("Expected %s=%v in log line, got %v", key, value, entry[key])
+		}
+	}
+	if _, ok := entry["duration"]; !ok {
+		t.Errorf("Expected duration in log line")
+	}
+}
+
+func TestUnaryLoggingGeneratesRequestID(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+
+	var seenRequestID string
+	_, err := UnaryLogging(logger)(context.Background(), nil, testInfo, func(ctx context.Context, _ any) (any, error) {
+		seenRequestID = logging.RequestIDFromContext(ctx)
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if seenRequestID == "" {
+		t.Errorf("Expected a generated request ID")
+	}
+}
+
+func TestUnaryAuthRejectsInvalidToken(t *testing.T) {
+	authn := auth.NewAuthenticator(map[string]string{"secret": "alice"})
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer wrong"))
+
+	_, err := UnaryAuth(authn)(ctx, nil, testInfo, func(ctx context.Context, _ any) (any, error) {
+		t.Fatal("handler should not be called")
+		return nil, nil
+	})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated, got %v", err)
+	}
+}
+
+func TestUnaryRecovery(t *testing.T) {
+	_, err := UnaryRecovery()(context.Background(), nil, testInfo, func(ctx context.Context, _ any) (any, error) {
+		panic("boom")
+	})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("Expected Internal, got %v", err)
+	}
+}
+
+func TestUnaryValidation(t *testing.T) {
+	_, err := UnaryValidation()(context.Background(), &proto.CreateTaskRequest{}, testInfo, func(ctx context.Context, _ any) (any, error) {
+		t.Fatal("handler should not be called")
+		return nil, nil
+	})
+
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument, got %v", err)
+	}
+
+	if len(st.Details()) != 1 {
+		t.Fatalf("Expected BadRequest details, got %v", st.Details())
+	}
+	badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+	if !ok || badRequest.FieldViolations[0].Field != "title" {
+		t.Errorf("Expected field violation on title, got %v", st.Details()[0])
+	}
+}
+
+func TestUnaryRateLimit(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.Limit{Rate: 0.5, Burst: 1}, nil)
+	ctx := auth.NewContext(context.Background(), "alice")
+	handler := func(ctx context.Context, _ any) (any, error) { return nil, nil }
+
+	if _, err := UnaryRateLimit(limiter)(ctx, nil, testInfo, handler); err != nil {
+		t.Fatalf("Expected first call to pass, got %v", err)
+	}
+
+	_, err := UnaryRateLimit(limiter)(ctx, nil, testInfo, handler)
+	st := status.Convert(err)
+	if st.Code() != codes.ResourceExhausted {
+		t.Fatalf("Expected ResourceExhausted, got %v", err)
+	}
+
+	if len(st.Details()) != 1 {
+		t.Fatalf("Expected RetryInfo details, got %v", st.Details())
+	}
+	retryInfo, ok := st.Details()[0].(*errdetails.RetryInfo)
+	if !ok || retryInfo.RetryDelay.AsDuration() <= 0 {
+		t.Errorf("Expected positive retry delay, got %v", st.Details()[0])
+	}
+}
+
+func TestClientAddrUsesLastForwardedHop(t *testing.T) {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 5000}})
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-forwarded-for", "203.0.113.7, 198.51.100.1"))
+
+	if got := clientAddr(ctx); got != "198.51.100.1" {
+		t.Errorf("Expected the hop the gateway appended, got %q", got)
+	}
+}
@@ -0,0 +1,80 @@
+package interceptor
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"grpc-todo/logging"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const requestIDHeader = "x-request-id"
+
+func UnaryLogging(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, fields := startRequest(ctx, logger, info.FullMethod)
+		start := time.Now()
+
+		resp, err := handler(ctx, req)
+
+		logRequest(ctx, fields, time.Since(start), err)
+		return resp, err
+	}
+}
+
+func StreamLogging(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, fields := startRequest(ss.Context(), logger, info.FullMethod)
+		start := time.Now()
+
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+
+		logRequest(ctx, fields, time.Since(start), err)
+		return err
+	}
+}
+
+func startRequest(ctx context.Context, logger *slog.Logger, method string) (context.Context, *logging.Fields) {
+	requestID := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestIDHeader); len(values) > 0 {
+			requestID = values[0]
+		}
+	}
+	if requestID == "" {
+		requestID = logging.NewRequestID()
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDHeader, requestID))
+
+	attrs := []any{slog.String("request_id", requestID), slog.String("method", method)}
+	if addr := clientAddr(ctx); addr != "" {
+		attrs = append(attrs, slog.String("peer", addr))
+	}
+
+	ctx = logging.WithRequestID(ctx, requestID)
+	ctx = logging.NewContext(ctx, logger.With(attrs...))
+	return logging.NewFieldsContext(ctx)
+}
+
+func logRequest(ctx context.Context, fields *logging.Fields, duration time.Duration, err error) {
+	code := status.Code(err)
+
+	attrs := append(fields.Attrs(),
+		slog.String("code", code.String()),
+		slog.Duration("duration", duration),
+	)
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+
+	level := slog.LevelInfo
+	if err != nil {
+		level = slog.LevelError
+	}
+
+	logging.FromContext(ctx).LogAttrs(ctx, level, "finished call", attrs...)
+}
@@ -0,0 +1,40 @@
+package interceptor
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// clientAddr returns the address of the calling client. Requests relayed by
+// the in-process HTTP gateway arrive from loopback, so for those the last
+// x-forwarded-for hop, the one the gateway appends, is used instead. Earlier
+// hops are chosen by the client and are not trusted.
+func clientAddr(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	addr := p.Addr.String()
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get("x-forwarded-for"); len(values) > 0 {
+				hops := strings.Split(values[len(values)-1], ",")
+				if forwarded := strings.TrimSpace(hops[len(hops)-1]); forwarded != "" {
+					return forwarded
+				}
+			}
+		}
+	}
+
+	return host
+}
@@ -2,66 +2,245 @@ package main
 
 import (
 	"context"
-	"log"
+	"errors"
+	"log/slog"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"grpc-todo/auth"
+	"grpc-todo/calendar"
+	"grpc-todo/config"
+	"grpc-todo/connectapi"
+	"grpc-todo/gateway"
+	"grpc-todo/interceptor"
+	"grpc-todo/logging"
+	"grpc-todo/openapi"
+	"grpc-todo/outbox"
 	"grpc-todo/proto"
+	"grpc-todo/ratelimit"
 	"grpc-todo/repository"
+	"grpc-todo/retention"
 	"grpc-todo/server"
+	"grpc-todo/webhook"
 
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/reflection"
 )
 
 func main() {
-	mongoURI := os.Getenv("MONGO_URI")
-	if mongoURI == "" {
-		mongoURI = "mongodb://localhost:27017"
+	cfg, err := config.Load()
+	if err != nil {
+		slog.Error("Failed to load config", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	logger, err := logging.New(os.Stdout, cfg.LogFormat, cfg.LogLevel)
+	if err != nil {
+		slog.Error("Failed to create logger", slog.String("error", err.Error()))
+		os.Exit(1)
 	}
+	slog.SetDefault(logger)
 
-	mongoClient, err := repository.ConnectToMongoDB(mongoURI)
+	mongoClient, err := repository.ConnectToMongoDB(cfg.MongoURI)
 	if err != nil {
-		log.Fatalf("Failed to connect to MongoDB: %v", err)
+		logger.Error("Failed to connect to MongoDB", slog.String("error", err.Error()))
+		os.Exit(1)
 	}
 	defer mongoClient.Disconnect(context.Background())
 
 	db := mongoClient.Database("grpc_todo_db")
-	repo := repository.NewRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	dispatcher := webhook.NewDispatcher(webhookRepo,
+		webhook.WithMaxAttempts(cfg.WebhookMaxAttempts),
+		webhook.WithAllowedNetworks(cfg.WebhookAllowedNetworks...),
+	)
+
+	// The outbox carries every task event, so webhook deliveries are stored
+	// with the change they report.
+	publishers := []outbox.Publisher{server.WebhookPublisher(dispatcher)}
+	if cfg.OutboxFile != "" {
+		filePublisher, err := outbox.NewFilePublisher(cfg.OutboxFile)
+		if err != nil {
+			logger.Error("Failed to open outbox file", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		defer filePublisher.Close()
+		publishers = append(publishers, filePublisher)
+	}
+	repo := repository.NewRepository(db, repository.WithOutbox())
+	if cfg.TaskStore == "mongo" {
+		supported, err := repository.SupportsTransactions(context.Background(), mongoClient)
+		if err == nil && !supported {
+			logger.Warn("MongoDB has no transactions; task changes and their outbox events are written separately and can be lost in between. Run a replica set to store them together.")
+		}
+	}
+	outboxRepo := repository.NewOutboxRepository(db)
+	if cfg.TaskStore == "events" {
+		eventLog := repository.NewMongoEventLog(db)
+		eventOpts := []repository.EventSourcedOption{repository.WithSnapshotEvery(cfg.EventSnapshotEvery)}
+		if cfg.EventLogDir != "" {
+			eventLog, err = repository.NewFileEventLog(cfg.EventLogDir)
+			if err != nil {
+				logger.Error("Failed to open event log", slog.String("error", err.Error()))
+				os.Exit(1)
+			}
+		} else {
+			// Servers sharing the Mongo log share task reservations too.
+			eventOpts = append(eventOpts, repository.WithSharedQuota(db))
+		}
+		repo, err = repository.NewEventSourcedRepository(context.Background(), eventLog, eventOpts...)
+		if err != nil {
+			logger.Error("Failed to load task events", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		// The log is its own outbox.
+		outboxRepo, err = repository.NewEventOutboxRepository(eventLog)
+		if err != nil {
+			logger.Error("Failed to set up the outbox", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+	}
+	auditRepo, err := repository.NewAuditRepository(context.Background(), db, cfg.AuditTTL)
+	if err != nil {
+		logger.Error("Failed to set up the audit log", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	viewRepo, err := repository.NewViewRepository(context.Background(), db)
+	if err != nil {
+		logger.Error("Failed to set up saved views", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
 
-	grpcServer := grpc.NewServer()
-	todoServer := server.NewToDoServer(repo)
+	authn := auth.NewAuthenticator(cfg.AuthTokens)
+	limiter := ratelimit.New(cfg.RateLimit, cfg.MethodRateLimits)
+
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			interceptor.UnaryLogging(logger),
+			interceptor.UnaryRecovery(),
+			interceptor.UnaryAuth(authn),
+			interceptor.UnaryRateLimit(limiter),
+			interceptor.UnaryValidation(),
+		),
+		grpc.ChainStreamInterceptor(
+			interceptor.StreamLogging(logger),
+			interceptor.StreamRecovery(),
+			interceptor.StreamAuth(authn),
+			interceptor.StreamRateLimit(limiter),
+			interceptor.StreamValidation(),
+		),
+	)
+	serverOpts := []server.Option{
+		server.WithMaxTasksPerTenant(cfg.MaxTasksPerTenant),
+		server.WithAudit(auditRepo),
+		server.WithViews(viewRepo),
+		server.WithRetention(cfg.RetentionSchedule, cfg.RetentionPolicies, retention.WithDryRun(cfg.RetentionDryRun)),
+		server.WithWebhooks(webhookRepo, dispatcher),
+	}
+	var calendarSigner *calendar.Signer
+	if cfg.CalendarSecret != "" {
+		calendarSigner = calendar.NewSigner(cfg.CalendarSecret)
+		serverOpts = append(serverOpts, server.WithCalendarFeed(calendarSigner, cfg.CalendarBaseURL))
+	}
+	todoServer := server.NewToDoServer(repo, serverOpts...)
 	proto.RegisterToDoServiceServer(grpcServer, todoServer)
 	reflection.Register(grpcServer)
 
-	lis, err := net.Listen("tcp", ":50051")
+	lis, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+	if err != nil {
+		logger.Error("Failed to listen", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	gatewayHandler, err := gateway.New(ctx, "localhost:"+cfg.GRPCPort)
+	if err != nil {
+		logger.Error("Failed to create HTTP gateway", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	docsHandler, err := openapi.Handler()
+	if err != nil {
+		logger.Error("Failed to load OpenAPI spec", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	loopback, err := grpc.NewClient("localhost:"+cfg.GRPCPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		logger.Error("Failed to create loopback gRPC client", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	defer loopback.Close()
+
+	httpMux := http.NewServeMux()
+	httpMux.Handle("/", gatewayHandler)
+	httpMux.Handle(connectapi.New(proto.NewToDoServiceClient(loopback)))
+	httpMux.Handle("/openapi.json", docsHandler)
+	httpMux.Handle("/openapi.yaml", docsHandler)
+	httpMux.Handle("/docs/", docsHandler)
+	if calendarSigner != nil {
+		httpMux.Handle("/calendar/", calendar.Handler(repo, calendarSigner))
+	}
+
+	httpServer := &http.Server{
+		Addr:              ":" + cfg.HTTPPort,
+		Handler:           h2c.NewHandler(connectapi.WithCORS(cfg.CORSAllowedOrigins, httpMux), &http2.Server{}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	cronJob, err := todoServer.StartCronJob()
 	if err != nil {
-		log.Fatalf("Failed to listen: %v", err)
+		logger.Error("Failed to start cron job", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	if cronJob != nil {
+		defer cronJob.Stop()
 	}
 
-	cronJob := todoServer.StartCronJob()
-	defer cronJob.Stop()
+	relay := outbox.NewRelay(outboxRepo, outbox.Fanout(publishers...))
+	go relay.Run(logging.NewContext(ctx, logger.With(slog.String("job", "outbox"))))
+	go dispatcher.Run(logging.NewContext(ctx, logger.With(slog.String("job", "webhooks"))))
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 
-	errChan := make(chan error, 1)
+	errChan := make(chan error, 2)
 
 	go func() {
-		log.Println("Server is running on port :50051")
+		logger.Info("Server is running", slog.String("addr", lis.Addr().String()))
 		if err := grpcServer.Serve(lis); err != nil {
 			errChan <- err
 		}
 	}()
 
+	go func() {
+		logger.Info("HTTP gateway is running", slog.String("addr", httpServer.Addr))
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errChan <- err
+		}
+	}()
+
 	select {
 	case <-quit:
-		log.Println("Shutting down server...")
+		logger.Info("Shutting down server...")
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer shutdownCancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			logger.Error("HTTP gateway shutdown failed", slog.String("error", err.Error()))
+		}
 		grpcServer.GracefulStop()
-		log.Println("Server gracefully stopped.")
+		logger.Info("Server gracefully stopped.")
 	case err := <-errChan:
-		log.Fatalf("Server error: %v", err)
+		logger.Error("Server error", slog.String("error", err.Error()))
+		os.Exit(1)
 	}
-}
\ No newline at end of file
+}
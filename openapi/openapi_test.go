@@ -0,0 +1,204 @@
+package openapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"grpc-todo/proto"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	protobuf "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+type spec struct {
+	OpenAPI    string                          `json:"openapi"`
+	Paths      map[string]map[string]operation `json:"paths"`
+	Components struct {
+		Schemas map[string]schema `json:"schemas"`
+	} `json:"components"`
+}
+
+type operation struct {
+	OperationID string `json:"operationId"`
+}
+
+type schema struct {
+	Properties map[string]struct {
+		Enum []string `json:"enum"`
+		// Items describes the elements of repeated fields.
+		Items struct {
+			Enum []string `json:"enum"`
+		} `json:"items"`
+	} `json:"properties"`
+}
+
+func fetchSpec(t *testing.T) spec {
+	t.Helper()
+
+	handler, err := Handler()
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200 for /openapi.json, got %d", rec.Code)
+	}
+
+	var s spec
+	if err := json.Unmarshal(rec.Body.Bytes(), &s); err != nil {
+		t.Fatalf("Failed to decode served spec: %v", err)
+	}
+	return s
+}
+
+func httpRule(m protoreflect.MethodDescriptor) (verb, path string) {
+	rule, ok := protobuf.GetExtension(m.Options(), annotations.E_Http).(*annotations.HttpRule)
+	if !ok || rule == nil {
+		return "", ""
+	}
+	switch p := rule.Pattern.(type) {
+	case *annotations.HttpRule_Get:
+		return "get", p.Get
+	case *annotations.HttpRule_Post:
+		return "post", p.Post
+	case *annotations.HttpRule_Put:
+		return "put", p.Put
+	case *annotations.HttpRule_Patch:
+		return "patch", p.Patch
+	case *annotations.HttpRule_Delete:
+		return "delete", p.Delete
+	}
+	return "", ""
+}
+
+// TestSpecInSyncWithProto fails when proto/todo.proto changed without
+// re-running `make generate`.
+func TestSpecInSyncWithProto(t *testing.T) {
+	s := fetchSpec(t)
+	if !strings.HasPrefix(s.OpenAPI, "3.") {
+		t.Fatalf("Expected an OpenAPI v3 document, got version %q", s.OpenAPI)
+	}
+
+	service := proto.File_proto_todo_proto.Services().ByName("ToDoService")
+	routes := make(map[string]bool)
+
+	methods := service.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		m := methods.Get(i)
+		verb, path := httpRule(m)
+		if path == "" {
+			continue
+		}
+		routes[verb+" "+path] = true
+
+		op, ok := s.Paths[path][verb]
+		if !ok {
+			t.Errorf("%s: %s %s missing from spec", m.Name(), strings.ToUpper(verb), path)
+			continue
+		}
+		if want := "ToDoService_" + string(m.Name()); op.OperationID != want {
+			t.Errorf("%s: expected operationId %s, got %s", m.Name(), want, op.OperationID)
+		}
+
+		checkSchema(t, s, m.Output())
+		if verb != "get" && verb != "delete" {
+			checkSchema(t, s, m.Input())
+		}
+	}
+
+	for path, ops := range s.Paths {
+		for verb := range ops {
+			if !routes[verb+" "+path] {
+				t.Errorf("Spec has %s %s with no matching RPC", strings.ToUpper(verb), path)
+			}
+		}
+	}
+}
+
+func checkSchema(t *testing.T, s spec, md protoreflect.MessageDescriptor) {
+	t.Helper()
+
+	// Well-known types have their own JSON mapping rather than one property
+	// per field.
+	if md.ParentFile().Package() == "google.protobuf" {
+		return
+	}
+
+	sc, ok := s.Components.Schemas[string(md.Name())]
+	if !ok {
+		t.Errorf("Schema %s missing from spec", md.Name())
+		return
+	}
+
+	var want, got []string
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		want = append(want, fd.JSONName())
+
+		if fd.Message() != nil {
+			checkSchema(t, s, fd.Message())
+		}
+		if fd.Enum() != nil {
+			prop := sc.Properties[fd.JSONName()]
+			if fd.IsList() {
+				checkEnum(t, md, fd, prop.Items.Enum)
+			} else {
+				checkEnum(t, md, fd, prop.Enum)
+			}
+		}
+	}
+	for name := range sc.Properties {
+		got = append(got, name)
+	}
+
+	sort.Strings(want)
+	sort.Strings(got)
+	if strings.Join(want, ",") != strings.Join(got, ",") {
+		t.Errorf("Schema %s has properties %v, proto has %v", md.Name(), got, want)
+	}
+}
+
+func checkEnum(t *testing.T, md protoreflect.MessageDescriptor, fd protoreflect.FieldDescriptor, got []string) {
+	t.Helper()
+
+	var want []string
+	values := fd.Enum().Values()
+	for i := 0; i < values.Len(); i++ {
+		want = append(want, string(values.Get(i).Name()))
+	}
+
+	if strings.Join(want, ",") != strings.Join(got, ",") {
+		t.Errorf("%s.%s has enum values %v, proto has %v", md.Name(), fd.Name(), got, want)
+	}
+}
+
+func TestSwaggerUI(t *testing.T) {
+	handler, err := Handler()
+	if err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+
+	for _, path := range []string{"/docs/", "/docs/swagger-ui-bundle.js", "/docs/swagger-ui.css"} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected 200 for %s, got %d", path, rec.Code)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs/", nil))
+	body, _ := io.ReadAll(rec.Body)
+	if !strings.Contains(string(body), `url: "/openapi.json"`) {
+		t.Errorf("Expected Swagger UI page to load /openapi.json")
+	}
+}
@@ -0,0 +1,142 @@
+// Package outbox publishes the task events that the repository writes to its
+// outbox collection alongside every change, or that an event-sourced
+// repository appends to its log.
+//
+// Delivery is at least once: a message is removed from the outbox only after
+// its publisher accepted it, so a crash in between publishes it again.
+// Consumers drop duplicates by the message ID, which never changes.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"grpc-todo/domain"
+	"grpc-todo/logging"
+	"grpc-todo/repository"
+)
+
+// Publisher hands messages to whatever consumes them. A message counts as
+// published once Publish returns nil.
+type Publisher interface {
+	Publish(ctx context.Context, msg *domain.OutboxMessage) error
+}
+
+// Envelope is the JSON form of a message written by the publishers in this
+// package.
+type Envelope struct {
+	ID        string          `json:"id"`
+	Event     string          `json:"event"`
+	TaskID    string          `json:"task_id"`
+	Owner     string          `json:"owner"`
+	CreatedAt time.Time       `json:"created_at"`
+	Task      json.RawMessage `json:"task"`
+}
+
+func NewEnvelope(msg *domain.OutboxMessage) Envelope {
+	return Envelope{
+		ID:        msg.Id,
+		Event:     msg.Event,
+		TaskID:    msg.TaskId,
+		Owner:     msg.Owner,
+		CreatedAt: msg.CreatedAt.UTC(),
+		Task:      msg.Payload,
+	}
+}
+
+type Relay struct {
+	repo      repository.OutboxRepository
+	publisher Publisher
+
+	batchSize    int
+	pollInterval time.Duration
+	lease        time.Duration
+	now          func() time.Time
+}
+
+type Option func(*Relay)
+
+// WithPollInterval sets how often Run looks for new messages.
+func WithPollInterval(interval time.Duration) Option {
+	return func(r *Relay) {
+		r.pollInterval = interval
+	}
+}
+
+// WithBatchSize sets how many messages are claimed at a time.
+func WithBatchSize(n int) Option {
+	return func(r *Relay) {
+		if n > 0 {
+			r.batchSize = n
+		}
+	}
+}
+
+func NewRelay(repo repository.OutboxRepository, publisher Publisher, opts ...Option) *Relay {
+	r := &Relay{
+		repo:         repo,
+		publisher:    publisher,
+		batchSize:    100,
+		pollInterval: time.Second,
+		lease:        time.Minute,
+		now:          time.Now,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Run publishes outbox messages until ctx is canceled.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			n, err := r.RelayPending(ctx)
+			if err != nil {
+				logging.FromContext(ctx).Error("outbox relay failed", slog.String("error", err.Error()))
+			}
+			if err != nil || n < r.batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayPending publishes up to one batch of messages in the order they were
+// written and returns how many it published. It stops at the first message
+// the publisher rejects, which is retried on a later call once its claim
+// expires, so that later messages do not overtake it.
+func (r *Relay) RelayPending(ctx context.Context) (int, error) {
+	messages, err := r.repo.ClaimOutbox(ctx, r.now(), r.lease, r.batchSize)
+	if err != nil && len(messages) == 0 {
+		return 0, err
+	}
+
+	var published []string
+	for _, msg := range messages {
+		if err = r.publisher.Publish(ctx, msg); err != nil {
+			break
+		}
+		published = append(published, msg.Id)
+	}
+
+	if len(published) > 0 {
+		if ackErr := r.repo.AckOutbox(ctx, published); ackErr != nil {
+			// Unacknowledged messages are published again, which
+			// consumers tolerate.
+			return 0, ackErr
+		}
+	}
+	return len(published), err
+}
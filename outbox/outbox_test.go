@@ -0,0 +1,199 @@
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"grpc-todo/domain"
+)
+
+type mockOutboxRepository struct {
+	messages []*domain.OutboxMessage
+	locked   map[string]time.Time
+	ackErr   error
+}
+
+func newMockOutboxRepository(n int) *mockOutboxRepository {
+	m := &mockOutboxRepository{locked: make(map[string]time.Time)}
+	for i := 1; i <= n; i++ {
+		m.messages = append(m.messages, &domain.OutboxMessage{
+			Id:      fmt.Sprintf("%024x", i),
+			Event:   domain.EventTaskCreated,
+			TaskId:  fmt.Sprint(i),
+			Payload: []byte(fmt.Sprintf(`{"id":"%d"}`, i)),
+		})
+	}
+	return m
+}
+
+func (m *mockOutboxRepository) ClaimOutbox(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.OutboxMessage, error) {
+	var res []*domain.OutboxMessage
+	for _, msg := range m.messages {
+		if len(res) < limit && !m.locked[msg.Id].After(now) {
+			m.locked[msg.Id] = now.Add(lease)
+			res = append(res, msg)
+		}
+	}
+	return res, nil
+}
+
+func (m *mockOutboxRepository) AckOutbox(ctx context.Context, ids []string) error {
+	if m.ackErr != nil {
+		return m.ackErr
+	}
+	acked := make(map[string]bool)
+	for _, id := range ids {
+		acked[id] = true
+	}
+	var kept []*domain.OutboxMessage
+	for _, msg := range m.messages {
+		if !acked[msg.Id] {
+			kept = append(kept, msg)
+		}
+	}
+	m.messages = kept
+	return nil
+}
+
+// flakyPublisher rejects the message with the given task ID once.
+type flakyPublisher struct {
+	*MemoryPublisher
+	failTask string
+}
+
+func (p *flakyPublisher) Publish(ctx context.Context, msg *domain.OutboxMessage) error {
+	if msg.TaskId == p.failTask {
+		p.failTask = ""
+		return errors.New("broker unavailable")
+	}
+	return p.MemoryPublisher.Publish(ctx, msg)
+}
+
+func taskIDs(messages []*domain.OutboxMessage) string {
+	var ids string
+	for _, msg := range messages {
+		ids += msg.TaskId
+	}
+	return ids
+}
+
+func TestRelayKeepsOrder(t *testing.T) {
+	repo := newMockOutboxRepository(5)
+	pub := &flakyPublisher{MemoryPublisher: NewMemoryPublisher(), failTask: "3"}
+	clock := time.Now()
+	r := NewRelay(repo, pub, WithBatchSize(10))
+	r.now = func() time.Time { return clock }
+
+	n, err := r.RelayPending(context.Background())
+	if err == nil || n != 2 {
+		t.Fatalf("Expected 2 messages published and an error, got %d (%v)", n, err)
+	}
+	if len(repo.messages) != 3 {
+		t.Errorf("Expected 3 messages left in the outbox, got %d", len(repo.messages))
+	}
+
+	// The rest stay claimed until the lease runs out.
+	if n, _ := r.RelayPending(context.Background()); n != 0 {
+		t.Errorf("Expected nothing to be published before the lease expired, got %d", n)
+	}
+
+	clock = clock.Add(2 * time.Minute)
+	if n, err := r.RelayPending(context.Background()); err != nil || n != 3 {
+		t.Fatalf("Expected 3 messages published, got %d (%v)", n, err)
+	}
+	if got := taskIDs(pub.Messages()); got != "12345" {
+		t.Errorf("Expected messages in order 12345, got %s", got)
+	}
+	if len(repo.messages) != 0 {
+		t.Errorf("Expected an empty outbox, got %d messages", len(repo.messages))
+	}
+}
+
+func TestRelayRedeliversUnacknowledged(t *testing.T) {
+	repo := newMockOutboxRepository(2)
+	repo.ackErr = errors.New("connection reset")
+	pub := NewMemoryPublisher()
+	clock := time.Now()
+	r := NewRelay(repo, pub)
+	r.now = func() time.Time { return clock }
+
+	if _, err := r.RelayPending(context.Background()); err == nil {
+		t.Fatal("Expected the ack error")
+	}
+
+	repo.ackErr = nil
+	clock = clock.Add(2 * time.Minute)
+	if n, err := r.RelayPending(context.Background()); err != nil || n != 2 {
+		t.Fatalf("Expected the messages to be published again, got %d (%v)", n, err)
+	}
+	if len(pub.Messages()) != 2 {
+		t.Errorf("Expected the publisher to drop duplicates, got %d messages", len(pub.Messages()))
+	}
+}
+
+func TestFanout(t *testing.T) {
+	repo := newMockOutboxRepository(2)
+	first, second := NewMemoryPublisher(), &flakyPublisher{MemoryPublisher: NewMemoryPublisher(), failTask: "2"}
+	clock := time.Now()
+	r := NewRelay(repo, Fanout(first, second))
+	r.now = func() time.Time { return clock }
+
+	if n, err := r.RelayPending(context.Background()); err == nil || n != 1 {
+		t.Fatalf("Expected 1 message published and an error, got %d (%v)", n, err)
+	}
+
+	clock = clock.Add(2 * time.Minute)
+	if n, err := r.RelayPending(context.Background()); err != nil || n != 1 {
+		t.Fatalf("Expected the rejected message to be published, got %d (%v)", n, err)
+	}
+	for _, p := range []*MemoryPublisher{first, second.MemoryPublisher} {
+		if got := taskIDs(p.Messages()); got != "12" {
+			t.Errorf("Expected every publisher to get messages 12, got %s", got)
+		}
+	}
+}
+
+func TestFilePublisher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	pub, err := NewFilePublisher(path)
+	if err != nil {
+		t.Fatalf("NewFilePublisher failed: %v", err)
+	}
+
+	repo := newMockOutboxRepository(3)
+	if _, err := NewRelay(repo, pub).RelayPending(context.Background()); err != nil {
+		t.Fatalf("RelayPending failed: %v", err)
+	}
+	if err := pub.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer f.Close()
+
+	var lines []Envelope
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Envelope
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("Expected JSON lines, got %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, e)
+	}
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %d", len(lines))
+	}
+	if e := lines[1]; e.ID != fmt.Sprintf("%024x", 2) || e.Event != domain.EventTaskCreated || string(e.Task) != `{"id":"2"}` {
+		t.Errorf("Unexpected envelope %+v", e)
+	}
+}
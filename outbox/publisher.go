@@ -0,0 +1,96 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"grpc-todo/domain"
+)
+
+// MemoryPublisher keeps published messages in memory, dropping redelivered
+// ones by ID the way a consumer should. It is meant for tests and for
+// embedding the relay in another process.
+type MemoryPublisher struct {
+	mu       sync.Mutex
+	seen     map[string]bool
+	messages []*domain.OutboxMessage
+}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{seen: make(map[string]bool)}
+}
+
+func (p *MemoryPublisher) Publish(_ context.Context, msg *domain.OutboxMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.seen[msg.Id] {
+		return nil
+	}
+	p.seen[msg.Id] = true
+	p.messages = append(p.messages, msg)
+	return nil
+}
+
+// Messages returns the distinct messages published so far, in order.
+func (p *MemoryPublisher) Messages() []*domain.OutboxMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]*domain.OutboxMessage(nil), p.messages...)
+}
+
+// FilePublisher appends each message to a file as a line of JSON (an
+// Envelope) and syncs it to disk before reporting it published. A message
+// may appear more than once after a crash.
+type FilePublisher struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+func NewFilePublisher(path string) (*FilePublisher, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &FilePublisher{f: f}, nil
+}
+
+func (p *FilePublisher) Publish(_ context.Context, msg *domain.OutboxMessage) error {
+	line, err := json.Marshal(NewEnvelope(msg))
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, err := p.f.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return p.f.Sync()
+}
+
+func (p *FilePublisher) Close() error {
+	return p.f.Close()
+}
+
+// Fanout publishes each message to every one of publishers in turn. A
+// message that one of them rejects is published to all of them again when
+// the relay retries it, so they, or their consumers, must drop repeated
+// message IDs.
+func Fanout(publishers ...Publisher) Publisher {
+	return fanout(publishers)
+}
+
+type fanout []Publisher
+
+func (f fanout) Publish(ctx context.Context, msg *domain.OutboxMessage) error {
+	for _, p := range f {
+		if err := p.Publish(ctx, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,1049 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: proto/todo.proto
+
+package protoconnect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	proto "grpc-todo/proto"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// ToDoServiceName is the fully-qualified name of the ToDoService service.
+	ToDoServiceName = "todo.ToDoService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// ToDoServiceCreateTaskProcedure is the fully-qualified name of the ToDoService's CreateTask RPC.
+	ToDoServiceCreateTaskProcedure = "/todo.ToDoService/CreateTask"
+	// ToDoServiceGetAllTasksProcedure is the fully-qualified name of the ToDoService's GetAllTasks RPC.
+	ToDoServiceGetAllTasksProcedure = "/todo.ToDoService/GetAllTasks"
+	// ToDoServiceGetTaskProcedure is the fully-qualified name of the ToDoService's GetTask RPC.
+	ToDoServiceGetTaskProcedure = "/todo.ToDoService/GetTask"
+	// ToDoServiceUpdateTaskProcedure is the fully-qualified name of the ToDoService's UpdateTask RPC.
+	ToDoServiceUpdateTaskProcedure = "/todo.ToDoService/UpdateTask"
+	// ToDoServiceUpdateTaskStatusProcedure is the fully-qualified name of the ToDoService's
+	// UpdateTaskStatus RPC.
+	ToDoServiceUpdateTaskStatusProcedure = "/todo.ToDoService/UpdateTaskStatus"
+	// ToDoServiceDeleteTaskProcedure is the fully-qualified name of the ToDoService's DeleteTask RPC.
+	ToDoServiceDeleteTaskProcedure = "/todo.ToDoService/DeleteTask"
+	// ToDoServiceBatchCreateTasksProcedure is the fully-qualified name of the ToDoService's
+	// BatchCreateTasks RPC.
+	ToDoServiceBatchCreateTasksProcedure = "/todo.ToDoService/BatchCreateTasks"
+	// ToDoServiceBatchUpdateTaskStatusProcedure is the fully-qualified name of the ToDoService's
+	// BatchUpdateTaskStatus RPC.
+	ToDoServiceBatchUpdateTaskStatusProcedure = "/todo.ToDoService/BatchUpdateTaskStatus"
+	// ToDoServiceBatchDeleteTasksProcedure is the fully-qualified name of the ToDoService's
+	// BatchDeleteTasks RPC.
+	ToDoServiceBatchDeleteTasksProcedure = "/todo.ToDoService/BatchDeleteTasks"
+	// ToDoServiceImportTasksProcedure is the fully-qualified name of the ToDoService's ImportTasks RPC.
+	ToDoServiceImportTasksProcedure = "/todo.ToDoService/ImportTasks"
+	// ToDoServiceExportTasksProcedure is the fully-qualified name of the ToDoService's ExportTasks RPC.
+	ToDoServiceExportTasksProcedure = "/todo.ToDoService/ExportTasks"
+	// ToDoServiceExportDocumentProcedure is the fully-qualified name of the ToDoService's
+	// ExportDocument RPC.
+	ToDoServiceExportDocumentProcedure = "/todo.ToDoService/ExportDocument"
+	// ToDoServiceImportDocumentProcedure is the fully-qualified name of the ToDoService's
+	// ImportDocument RPC.
+	ToDoServiceImportDocumentProcedure = "/todo.ToDoService/ImportDocument"
+	// ToDoServiceGetCalendarFeedProcedure is the fully-qualified name of the ToDoService's
+	// GetCalendarFeed RPC.
+	ToDoServiceGetCalendarFeedProcedure = "/todo.ToDoService/GetCalendarFeed"
+	// ToDoServiceImportCalendarProcedure is the fully-qualified name of the ToDoService's
+	// ImportCalendar RPC.
+	ToDoServiceImportCalendarProcedure = "/todo.ToDoService/ImportCalendar"
+	// ToDoServiceCreateWebhookProcedure is the fully-qualified name of the ToDoService's CreateWebhook
+	// RPC.
+	ToDoServiceCreateWebhookProcedure = "/todo.ToDoService/CreateWebhook"
+	// ToDoServiceListWebhooksProcedure is the fully-qualified name of the ToDoService's ListWebhooks
+	// RPC.
+	ToDoServiceListWebhooksProcedure = "/todo.ToDoService/ListWebhooks"
+	// ToDoServiceDeleteWebhookProcedure is the fully-qualified name of the ToDoService's DeleteWebhook
+	// RPC.
+	ToDoServiceDeleteWebhookProcedure = "/todo.ToDoService/DeleteWebhook"
+	// ToDoServiceArchiveTaskProcedure is the fully-qualified name of the ToDoService's ArchiveTask RPC.
+	ToDoServiceArchiveTaskProcedure = "/todo.ToDoService/ArchiveTask"
+	// ToDoServiceListArchivedTasksProcedure is the fully-qualified name of the ToDoService's
+	// ListArchivedTasks RPC.
+	ToDoServiceListArchivedTasksProcedure = "/todo.ToDoService/ListArchivedTasks"
+	// ToDoServiceRestoreTaskProcedure is the fully-qualified name of the ToDoService's RestoreTask RPC.
+	ToDoServiceRestoreTaskProcedure = "/todo.ToDoService/RestoreTask"
+	// ToDoServiceListTrashProcedure is the fully-qualified name of the ToDoService's ListTrash RPC.
+	ToDoServiceListTrashProcedure = "/todo.ToDoService/ListTrash"
+	// ToDoServiceUndeleteTaskProcedure is the fully-qualified name of the ToDoService's UndeleteTask
+	// RPC.
+	ToDoServiceUndeleteTaskProcedure = "/todo.ToDoService/UndeleteTask"
+	// ToDoServicePurgeTaskProcedure is the fully-qualified name of the ToDoService's PurgeTask RPC.
+	ToDoServicePurgeTaskProcedure = "/todo.ToDoService/PurgeTask"
+	// ToDoServiceListAuditEntriesProcedure is the fully-qualified name of the ToDoService's
+	// ListAuditEntries RPC.
+	ToDoServiceListAuditEntriesProcedure = "/todo.ToDoService/ListAuditEntries"
+	// ToDoServiceGetTaskHistoryProcedure is the fully-qualified name of the ToDoService's
+	// GetTaskHistory RPC.
+	ToDoServiceGetTaskHistoryProcedure = "/todo.ToDoService/GetTaskHistory"
+	// ToDoServiceSearchTasksProcedure is the fully-qualified name of the ToDoService's SearchTasks RPC.
+	ToDoServiceSearchTasksProcedure = "/todo.ToDoService/SearchTasks"
+	// ToDoServiceCreateViewProcedure is the fully-qualified name of the ToDoService's CreateView RPC.
+	ToDoServiceCreateViewProcedure = "/todo.ToDoService/CreateView"
+	// ToDoServiceGetViewProcedure is the fully-qualified name of the ToDoService's GetView RPC.
+	ToDoServiceGetViewProcedure = "/todo.ToDoService/GetView"
+	// ToDoServiceListViewsProcedure is the fully-qualified name of the ToDoService's ListViews RPC.
+	ToDoServiceListViewsProcedure = "/todo.ToDoService/ListViews"
+	// ToDoServiceUpdateViewProcedure is the fully-qualified name of the ToDoService's UpdateView RPC.
+	ToDoServiceUpdateViewProcedure = "/todo.ToDoService/UpdateView"
+	// ToDoServiceDeleteViewProcedure is the fully-qualified name of the ToDoService's DeleteView RPC.
+	ToDoServiceDeleteViewProcedure = "/todo.ToDoService/DeleteView"
+	// ToDoServiceListTasksByViewProcedure is the fully-qualified name of the ToDoService's
+	// ListTasksByView RPC.
+	ToDoServiceListTasksByViewProcedure = "/todo.ToDoService/ListTasksByView"
+	// ToDoServiceGetTaskStatsProcedure is the fully-qualified name of the ToDoService's GetTaskStats
+	// RPC.
+	ToDoServiceGetTaskStatsProcedure = "/todo.ToDoService/GetTaskStats"
+)
+
+// ToDoServiceClient is a client for the todo.ToDoService service.
+type ToDoServiceClient interface {
+	CreateTask(context.Context, *connect.Request[proto.CreateTaskRequest]) (*connect.Response[proto.CreateTaskResponse], error)
+	GetAllTasks(context.Context, *connect.Request[proto.GetAllTasksRequest]) (*connect.Response[proto.GetAllTasksResponse], error)
+	GetTask(context.Context, *connect.Request[proto.GetTaskRequest]) (*connect.Response[proto.GetTaskResponse], error)
+	UpdateTask(context.Context, *connect.Request[proto.UpdateTaskRequest]) (*connect.Response[proto.UpdateTaskResponse], error)
+	UpdateTaskStatus(context.Context, *connect.Request[proto.UpdateTaskStatusRequest]) (*connect.Response[proto.UpdateTaskStatusResponse], error)
+	DeleteTask(context.Context, *connect.Request[proto.DeleteTaskRequest]) (*connect.Response[proto.DeleteTaskResponse], error)
+	BatchCreateTasks(context.Context, *connect.Request[proto.BatchCreateTasksRequest]) (*connect.Response[proto.BatchCreateTasksResponse], error)
+	BatchUpdateTaskStatus(context.Context, *connect.Request[proto.BatchUpdateTaskStatusRequest]) (*connect.Response[proto.BatchUpdateTaskStatusResponse], error)
+	BatchDeleteTasks(context.Context, *connect.Request[proto.BatchDeleteTasksRequest]) (*connect.Response[proto.BatchDeleteTasksResponse], error)
+	ImportTasks(context.Context) *connect.ClientStreamForClient[proto.ImportTasksRequest, proto.ImportTasksResponse]
+	ExportTasks(context.Context, *connect.Request[proto.ExportTasksRequest]) (*connect.ServerStreamForClient[proto.ExportTasksResponse], error)
+	ExportDocument(context.Context, *connect.Request[proto.ExportDocumentRequest]) (*connect.Response[proto.ExportDocumentResponse], error)
+	ImportDocument(context.Context, *connect.Request[proto.ImportDocumentRequest]) (*connect.Response[proto.ImportDocumentResponse], error)
+	GetCalendarFeed(context.Context, *connect.Request[proto.GetCalendarFeedRequest]) (*connect.Response[proto.GetCalendarFeedResponse], error)
+	ImportCalendar(context.Context, *connect.Request[proto.ImportCalendarRequest]) (*connect.Response[proto.ImportCalendarResponse], error)
+	CreateWebhook(context.Context, *connect.Request[proto.CreateWebhookRequest]) (*connect.Response[proto.CreateWebhookResponse], error)
+	ListWebhooks(context.Context, *connect.Request[proto.ListWebhooksRequest]) (*connect.Response[proto.ListWebhooksResponse], error)
+	DeleteWebhook(context.Context, *connect.Request[proto.DeleteWebhookRequest]) (*connect.Response[proto.DeleteWebhookResponse], error)
+	ArchiveTask(context.Context, *connect.Request[proto.ArchiveTaskRequest]) (*connect.Response[proto.ArchiveTaskResponse], error)
+	ListArchivedTasks(context.Context, *connect.Request[proto.ListArchivedTasksRequest]) (*connect.Response[proto.ListArchivedTasksResponse], error)
+	RestoreTask(context.Context, *connect.Request[proto.RestoreTaskRequest]) (*connect.Response[proto.RestoreTaskResponse], error)
+	ListTrash(context.Context, *connect.Request[proto.ListTrashRequest]) (*connect.Response[proto.ListTrashResponse], error)
+	UndeleteTask(context.Context, *connect.Request[proto.UndeleteTaskRequest]) (*connect.Response[proto.UndeleteTaskResponse], error)
+	PurgeTask(context.Context, *connect.Request[proto.PurgeTaskRequest]) (*connect.Response[proto.PurgeTaskResponse], error)
+	ListAuditEntries(context.Context, *connect.Request[proto.ListAuditEntriesRequest]) (*connect.Response[proto.ListAuditEntriesResponse], error)
+	GetTaskHistory(context.Context, *connect.Request[proto.GetTaskHistoryRequest]) (*connect.Response[proto.GetTaskHistoryResponse], error)
+	SearchTasks(context.Context, *connect.Request[proto.SearchTasksRequest]) (*connect.Response[proto.SearchTasksResponse], error)
+	CreateView(context.Context, *connect.Request[proto.CreateViewRequest]) (*connect.Response[proto.CreateViewResponse], error)
+	GetView(context.Context, *connect.Request[proto.GetViewRequest]) (*connect.Response[proto.GetViewResponse], error)
+	ListViews(context.Context, *connect.Request[proto.ListViewsRequest]) (*connect.Response[proto.ListViewsResponse], error)
+	UpdateView(context.Context, *connect.Request[proto.UpdateViewRequest]) (*connect.Response[proto.UpdateViewResponse], error)
+	DeleteView(context.Context, *connect.Request[proto.DeleteViewRequest]) (*connect.Response[proto.DeleteViewResponse], error)
+	ListTasksByView(context.Context, *connect.Request[proto.ListTasksByViewRequest]) (*connect.Response[proto.ListTasksByViewResponse], error)
+	GetTaskStats(context.Context, *connect.Request[proto.GetTaskStatsRequest]) (*connect.Response[proto.GetTaskStatsResponse], error)
+}
+
+// NewToDoServiceClient constructs a client for the todo.ToDoService service. By default, it uses
+// the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewToDoServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) ToDoServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	toDoServiceMethods := proto.File_proto_todo_proto.Services().ByName("ToDoService").Methods()
+	return &toDoServiceClient{
+		createTask: connect.NewClient[proto.CreateTaskRequest, proto.CreateTaskResponse](
+			httpClient,
+			baseURL+ToDoServiceCreateTaskProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("CreateTask")),
+			connect.WithClientOptions(opts...),
+		),
+		getAllTasks: connect.NewClient[proto.GetAllTasksRequest, proto.GetAllTasksResponse](
+			httpClient,
+			baseURL+ToDoServiceGetAllTasksProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("GetAllTasks")),
+			connect.WithClientOptions(opts...),
+		),
+		getTask: connect.NewClient[proto.GetTaskRequest, proto.GetTaskResponse](
+			httpClient,
+			baseURL+ToDoServiceGetTaskProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("GetTask")),
+			connect.WithClientOptions(opts...),
+		),
+		updateTask: connect.NewClient[proto.UpdateTaskRequest, proto.UpdateTaskResponse](
+			httpClient,
+			baseURL+ToDoServiceUpdateTaskProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("UpdateTask")),
+			connect.WithClientOptions(opts...),
+		),
+		updateTaskStatus: connect.NewClient[proto.UpdateTaskStatusRequest, proto.UpdateTaskStatusResponse](
+			httpClient,
+			baseURL+ToDoServiceUpdateTaskStatusProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("UpdateTaskStatus")),
+			connect.WithClientOptions(opts...),
+		),
+		deleteTask: connect.NewClient[proto.DeleteTaskRequest, proto.DeleteTaskResponse](
+			httpClient,
+			baseURL+ToDoServiceDeleteTaskProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("DeleteTask")),
+			connect.WithClientOptions(opts...),
+		),
+		batchCreateTasks: connect.NewClient[proto.BatchCreateTasksRequest, proto.BatchCreateTasksResponse](
+			httpClient,
+			baseURL+ToDoServiceBatchCreateTasksProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("BatchCreateTasks")),
+			connect.WithClientOptions(opts...),
+		),
+		batchUpdateTaskStatus: connect.NewClient[proto.BatchUpdateTaskStatusRequest, proto.BatchUpdateTaskStatusResponse](
+			httpClient,
+			baseURL+ToDoServiceBatchUpdateTaskStatusProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("BatchUpdateTaskStatus")),
+			connect.WithClientOptions(opts...),
+		),
+		batchDeleteTasks: connect.NewClient[proto.BatchDeleteTasksRequest, proto.BatchDeleteTasksResponse](
+			httpClient,
+			baseURL+ToDoServiceBatchDeleteTasksProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("BatchDeleteTasks")),
+			connect.WithClientOptions(opts...),
+		),
+		importTasks: connect.NewClient[proto.ImportTasksRequest, proto.ImportTasksResponse](
+			httpClient,
+			baseURL+ToDoServiceImportTasksProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("ImportTasks")),
+			connect.WithClientOptions(opts...),
+		),
+		exportTasks: connect.NewClient[proto.ExportTasksRequest, proto.ExportTasksResponse](
+			httpClient,
+			baseURL+ToDoServiceExportTasksProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("ExportTasks")),
+			connect.WithClientOptions(opts...),
+		),
+		exportDocument: connect.NewClient[proto.ExportDocumentRequest, proto.ExportDocumentResponse](
+			httpClient,
+			baseURL+ToDoServiceExportDocumentProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("ExportDocument")),
+			connect.WithClientOptions(opts...),
+		),
+		importDocument: connect.NewClient[proto.ImportDocumentRequest, proto.ImportDocumentResponse](
+			httpClient,
+			baseURL+ToDoServiceImportDocumentProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("ImportDocument")),
+			connect.WithClientOptions(opts...),
+		),
+		getCalendarFeed: connect.NewClient[proto.GetCalendarFeedRequest, proto.GetCalendarFeedResponse](
+			httpClient,
+			baseURL+ToDoServiceGetCalendarFeedProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("GetCalendarFeed")),
+			connect.WithClientOptions(opts...),
+		),
+		importCalendar: connect.NewClient[proto.ImportCalendarRequest, proto.ImportCalendarResponse](
+			httpClient,
+			baseURL+ToDoServiceImportCalendarProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("ImportCalendar")),
+			connect.WithClientOptions(opts...),
+		),
+		createWebhook: connect.NewClient[proto.CreateWebhookRequest, proto.CreateWebhookResponse](
+			httpClient,
+			baseURL+ToDoServiceCreateWebhookProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("CreateWebhook")),
+			connect.WithClientOptions(opts...),
+		),
+		listWebhooks: connect.NewClient[proto.ListWebhooksRequest, proto.ListWebhooksResponse](
+			httpClient,
+			baseURL+ToDoServiceListWebhooksProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("ListWebhooks")),
+			connect.WithClientOptions(opts...),
+		),
+		deleteWebhook: connect.NewClient[proto.DeleteWebhookRequest, proto.DeleteWebhookResponse](
+			httpClient,
+			baseURL+ToDoServiceDeleteWebhookProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("DeleteWebhook")),
+			connect.WithClientOptions(opts...),
+		),
+		archiveTask: connect.NewClient[proto.ArchiveTaskRequest, proto.ArchiveTaskResponse](
+			httpClient,
+			baseURL+ToDoServiceArchiveTaskProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("ArchiveTask")),
+			connect.WithClientOptions(opts...),
+		),
+		listArchivedTasks: connect.NewClient[proto.ListArchivedTasksRequest, proto.ListArchivedTasksResponse](
+			httpClient,
+			baseURL+ToDoServiceListArchivedTasksProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("ListArchivedTasks")),
+			connect.WithClientOptions(opts...),
+		),
+		restoreTask: connect.NewClient[proto.RestoreTaskRequest, proto.RestoreTaskResponse](
+			httpClient,
+			baseURL+ToDoServiceRestoreTaskProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("RestoreTask")),
+			connect.WithClientOptions(opts...),
+		),
+		listTrash: connect.NewClient[proto.ListTrashRequest, proto.ListTrashResponse](
+			httpClient,
+			baseURL+ToDoServiceListTrashProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("ListTrash")),
+			connect.WithClientOptions(opts...),
+		),
+		undeleteTask: connect.NewClient[proto.UndeleteTaskRequest, proto.UndeleteTaskResponse](
+			httpClient,
+			baseURL+ToDoServiceUndeleteTaskProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("UndeleteTask")),
+			connect.WithClientOptions(opts...),
+		),
+		purgeTask: connect.NewClient[proto.PurgeTaskRequest, proto.PurgeTaskResponse](
+			httpClient,
+			baseURL+ToDoServicePurgeTaskProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("PurgeTask")),
+			connect.WithClientOptions(opts...),
+		),
+		listAuditEntries: connect.NewClient[proto.ListAuditEntriesRequest, proto.ListAuditEntriesResponse](
+			httpClient,
+			baseURL+ToDoServiceListAuditEntriesProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("ListAuditEntries")),
+			connect.WithClientOptions(opts...),
+		),
+		getTaskHistory: connect.NewClient[proto.GetTaskHistoryRequest, proto.GetTaskHistoryResponse](
+			httpClient,
+			baseURL+ToDoServiceGetTaskHistoryProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("GetTaskHistory")),
+			connect.WithClientOptions(opts...),
+		),
+		searchTasks: connect.NewClient[proto.SearchTasksRequest, proto.SearchTasksResponse](
+			httpClient,
+			baseURL+ToDoServiceSearchTasksProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("SearchTasks")),
+			connect.WithClientOptions(opts...),
+		),
+		createView: connect.NewClient[proto.CreateViewRequest, proto.CreateViewResponse](
+			httpClient,
+			baseURL+ToDoServiceCreateViewProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("CreateView")),
+			connect.WithClientOptions(opts...),
+		),
+		getView: connect.NewClient[proto.GetViewRequest, proto.GetViewResponse](
+			httpClient,
+			baseURL+ToDoServiceGetViewProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("GetView")),
+			connect.WithClientOptions(opts...),
+		),
+		listViews: connect.NewClient[proto.ListViewsRequest, proto.ListViewsResponse](
+			httpClient,
+			baseURL+ToDoServiceListViewsProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("ListViews")),
+			connect.WithClientOptions(opts...),
+		),
+		updateView: connect.NewClient[proto.UpdateViewRequest, proto.UpdateViewResponse](
+			httpClient,
+			baseURL+ToDoServiceUpdateViewProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("UpdateView")),
+			connect.WithClientOptions(opts...),
+		),
+		deleteView: connect.NewClient[proto.DeleteViewRequest, proto.DeleteViewResponse](
+			httpClient,
+			baseURL+ToDoServiceDeleteViewProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("DeleteView")),
+			connect.WithClientOptions(opts...),
+		),
+		listTasksByView: connect.NewClient[proto.ListTasksByViewRequest, proto.ListTasksByViewResponse](
+			httpClient,
+			baseURL+ToDoServiceListTasksByViewProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("ListTasksByView")),
+			connect.WithClientOptions(opts...),
+		),
+		getTaskStats: connect.NewClient[proto.GetTaskStatsRequest, proto.GetTaskStatsResponse](
+			httpClient,
+			baseURL+ToDoServiceGetTaskStatsProcedure,
+			connect.WithSchema(toDoServiceMethods.ByName("GetTaskStats")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// toDoServiceClient implements ToDoServiceClient.
+type toDoServiceClient struct {
+	createTask            *connect.Client[proto.CreateTaskRequest, proto.CreateTaskResponse]
+	getAllTasks           *connect.Client[proto.GetAllTasksRequest, proto.GetAllTasksResponse]
+	getTask               *connect.Client[proto.GetTaskRequest, proto.GetTaskResponse]
+	updateTask            *connect.Client[proto.UpdateTaskRequest, proto.UpdateTaskResponse]
+	updateTaskStatus      *connect.Client[proto.UpdateTaskStatusRequest, proto.UpdateTaskStatusResponse]
+	deleteTask            *connect.Client[proto.DeleteTaskRequest, proto.DeleteTaskResponse]
+	batchCreateTasks      *connect.Client[proto.BatchCreateTasksRequest, proto.BatchCreateTasksResponse]
+	batchUpdateTaskStatus *connect.Client[proto.BatchUpdateTaskStatusRequest, proto.BatchUpdateTaskStatusResponse]
+	batchDeleteTasks      *connect.Client[proto.BatchDeleteTasksRequest, proto.BatchDeleteTasksResponse]
+	importTasks           *connect.Client[proto.ImportTasksRequest, proto.ImportTasksResponse]
+	exportTasks           *connect.Client[proto.ExportTasksRequest, proto.ExportTasksResponse]
+	exportDocument        *connect.Client[proto.ExportDocumentRequest, proto.ExportDocumentResponse]
+	importDocument        *connect.Client[proto.ImportDocumentRequest, proto.ImportDocumentResponse]
+	getCalendarFeed       *connect.Client[proto.GetCalendarFeedRequest, proto.GetCalendarFeedResponse]
+	importCalendar        *connect.Client[proto.ImportCalendarRequest, proto.ImportCalendarResponse]
+	createWebhook         *connect.Client[proto.CreateWebhookRequest, proto.CreateWebhookResponse]
+	listWebhooks          *connect.Client[proto.ListWebhooksRequest, proto.ListWebhooksResponse]
+	deleteWebhook         *connect.Client[proto.DeleteWebhookRequest, proto.DeleteWebhookResponse]
+	archiveTask           *connect.Client[proto.ArchiveTaskRequest, proto.ArchiveTaskResponse]
+	listArchivedTasks     *connect.Client[proto.ListArchivedTasksRequest, proto.ListArchivedTasksResponse]
+	restoreTask           *connect.Client[proto.RestoreTaskRequest, proto.RestoreTaskResponse]
+	listTrash             *connect.Client[proto.ListTrashRequest, proto.ListTrashResponse]
+	undeleteTask          *connect.Client[proto.UndeleteTaskRequest, proto.UndeleteTaskResponse]
+	purgeTask             *connect.Client[proto.PurgeTaskRequest, proto.PurgeTaskResponse]
+	listAuditEntries      *connect.Client[proto.ListAuditEntriesRequest, proto.ListAuditEntriesResponse]
+	getTaskHistory        *connect.Client[proto.GetTaskHistoryRequest, proto.GetTaskHistoryResponse]
+	searchTasks           *connect.Client[proto.SearchTasksRequest, proto.SearchTasksResponse]
+	createView            *connect.Client[proto.CreateViewRequest, proto.CreateViewResponse]
+	getView               *connect.Client[proto.GetViewRequest, proto.GetViewResponse]
+	listViews             *connect.Client[proto.ListViewsRequest, proto.ListViewsResponse]
+	updateView            *connect.Client[proto.UpdateViewRequest, proto.UpdateViewResponse]
+	deleteView            *connect.Client[proto.DeleteViewRequest, proto.DeleteViewResponse]
+	listTasksByView       *connect.Client[proto.ListTasksByViewRequest, proto.ListTasksByViewResponse]
+	getTaskStats          *connect.Client[proto.GetTaskStatsRequest, proto.GetTaskStatsResponse]
+}
+
+// CreateTask calls todo.ToDoService.CreateTask.
+func (c *toDoServiceClient) CreateTask(ctx context.Context, req *connect.Request[proto.CreateTaskRequest]) (*connect.Response[proto.CreateTaskResponse], error) {
+	return c.createTask.CallUnary(ctx, req)
+}
+
+// GetAllTasks calls todo.ToDoService.GetAllTasks.
+func (c *toDoServiceClient) GetAllTasks(ctx context.Context, req *connect.Request[proto.GetAllTasksRequest]) (*connect.Response[proto.GetAllTasksResponse], error) {
+	return c.getAllTasks.CallUnary(ctx, req)
+}
+
+// GetTask calls todo.ToDoService.GetTask.
+func (c *toDoServiceClient) GetTask(ctx context.Context, req *connect.Request[proto.GetTaskRequest]) (*connect.Response[proto.GetTaskResponse], error) {
+	return c.getTask.CallUnary(ctx, req)
+}
+
+// UpdateTask calls todo.ToDoService.UpdateTask.
+func (c *toDoServiceClient) UpdateTask(ctx context.Context, req *connect.Request[proto.UpdateTaskRequest]) (*connect.Response[proto.UpdateTaskResponse], error) {
+	return c.updateTask.CallUnary(ctx, req)
+}
+
+// UpdateTaskStatus calls todo.ToDoService.UpdateTaskStatus.
+func (c *toDoServiceClient) UpdateTaskStatus(ctx context.Context, req *connect.Request[proto.UpdateTaskStatusRequest]) (*connect.Response[proto.UpdateTaskStatusResponse], error) {
+	return c.updateTaskStatus.CallUnary(ctx, req)
+}
+
+// DeleteTask calls todo.ToDoService.DeleteTask.
+func (c *toDoServiceClient) DeleteTask(ctx context.Context, req *connect.Request[proto.DeleteTaskRequest]) (*connect.Response[proto.DeleteTaskResponse], error) {
+	return c.deleteTask.CallUnary(ctx, req)
+}
+
+// BatchCreateTasks calls todo.ToDoService.BatchCreateTasks.
+func (c *toDoServiceClient) BatchCreateTasks(ctx context.Context, req *connect.Request[proto.BatchCreateTasksRequest]) (*connect.Response[proto.BatchCreateTasksResponse], error) {
+	return c.batchCreateTasks.CallUnary(ctx, req)
+}
+
+// BatchUpdateTaskStatus calls todo.ToDoService.BatchUpdateTaskStatus.
+func (c *toDoServiceClient) BatchUpdateTaskStatus(ctx context.Context, req *connect.Request[proto.BatchUpdateTaskStatusRequest]) (*connect.Response[proto.BatchUpdateTaskStatusResponse], error) {
+	return c.batchUpdateTaskStatus.CallUnary(ctx, req)
+}
+
+// BatchDeleteTasks calls todo.ToDoService.BatchDeleteTasks.
+func (c *toDoServiceClient) BatchDeleteTasks(ctx context.Context, req *connect.Request[proto.BatchDeleteTasksRequest]) (*connect.Response[proto.BatchDeleteTasksResponse], error) {
+	return c.batchDeleteTasks.CallUnary(ctx, req)
+}
+
+// ImportTasks calls todo.ToDoService.ImportTasks.
+func (c *toDoServiceClient) ImportTasks(ctx context.Context) *connect.ClientStreamForClient[proto.ImportTasksRequest, proto.ImportTasksResponse] {
+	return c.importTasks.CallClientStream(ctx)
+}
+
+// ExportTasks calls todo.ToDoService.ExportTasks.
+func (c *toDoServiceClient) ExportTasks(ctx context.Context, req *connect.Request[proto.ExportTasksRequest]) (*connect.ServerStreamForClient[proto.ExportTasksResponse], error) {
+	return c.exportTasks.CallServerStream(ctx, req)
+}
+
+// ExportDocument calls todo.ToDoService.ExportDocument.
+func (c *toDoServiceClient) ExportDocument(ctx context.Context, req *connect.Request[proto.ExportDocumentRequest]) (*connect.Response[proto.ExportDocumentResponse], error) {
+	return c.exportDocument.CallUnary(ctx, req)
+}
+
+// ImportDocument calls todo.ToDoService.ImportDocument.
+func (c *toDoServiceClient) ImportDocument(ctx context.Context, req *connect.Request[proto.ImportDocumentRequest]) (*connect.Response[proto.ImportDocumentResponse], error) {
+	return c.importDocument.CallUnary(ctx, req)
+}
+
+// GetCalendarFeed calls todo.ToDoService.GetCalendarFeed.
+func (c *toDoServiceClient) GetCalendarFeed(ctx context.Context, req *connect.Request[proto.GetCalendarFeedRequest]) (*connect.Response[proto.GetCalendarFeedResponse], error) {
+	return c.getCalendarFeed.CallUnary(ctx, req)
+}
+
+// ImportCalendar calls todo.ToDoService.ImportCalendar.
+func (c *toDoServiceClient) ImportCalendar(ctx context.Context, req *connect.Request[proto.ImportCalendarRequest]) (*connect.Response[proto.ImportCalendarResponse], error) {
+	return c.importCalendar.CallUnary(ctx, req)
+}
+
+// CreateWebhook calls todo.ToDoService.CreateWebhook.
+func (c *toDoServiceClient) CreateWebhook(ctx context.Context, req *connect.Request[proto.CreateWebhookRequest]) (*connect.Response[proto.CreateWebhookResponse], error) {
+	return c.createWebhook.CallUnary(ctx, req)
+}
+
+// ListWebhooks calls todo.ToDoService.ListWebhooks.
+func (c *toDoServiceClient) ListWebhooks(ctx context.Context, req *connect.Request[proto.ListWebhooksRequest]) (*connect.Response[proto.ListWebhooksResponse], error) {
+	return c.listWebhooks.CallUnary(ctx, req)
+}
+
+// DeleteWebhook calls todo.ToDoService.DeleteWebhook.
+func (c *toDoServiceClient) DeleteWebhook(ctx context.Context, req *connect.Request[proto.DeleteWebhookRequest]) (*connect.Response[proto.DeleteWebhookResponse], error) {
+	return c.deleteWebhook.CallUnary(ctx, req)
+}
+
+// ArchiveTask calls todo.ToDoService.ArchiveTask.
+func (c *toDoServiceClient) ArchiveTask(ctx context.Context, req *connect.Request[proto.ArchiveTaskRequest]) (*connect.Response[proto.ArchiveTaskResponse], error) {
+	return c.archiveTask.CallUnary(ctx, req)
+}
+
+// ListArchivedTasks calls todo.ToDoService.ListArchivedTasks.
+func (c *toDoServiceClient) ListArchivedTasks(ctx context.Context, req *connect.Request[proto.ListArchivedTasksRequest]) (*connect.Response[proto.ListArchivedTasksResponse], error) {
+	return c.listArchivedTasks.CallUnary(ctx, req)
+}
+
+// RestoreTask calls todo.ToDoService.RestoreTask.
+func (c *toDoServiceClient) RestoreTask(ctx context.Context, req *connect.Request[proto.RestoreTaskRequest]) (*connect.Response[proto.RestoreTaskResponse], error) {
+	return c.restoreTask.CallUnary(ctx, req)
+}
+
+// ListTrash calls todo.ToDoService.ListTrash.
+func (c *toDoServiceClient) ListTrash(ctx context.Context, req *connect.Request[proto.ListTrashRequest]) (*connect.Response[proto.ListTrashResponse], error) {
+	return c.listTrash.CallUnary(ctx, req)
+}
+
+// UndeleteTask calls todo.ToDoService.UndeleteTask.
+func (c *toDoServiceClient) UndeleteTask(ctx context.Context, req *connect.Request[proto.UndeleteTaskRequest]) (*connect.Response[proto.UndeleteTaskResponse], error) {
+	return c.undeleteTask.CallUnary(ctx, req)
+}
+
+// PurgeTask calls todo.ToDoService.PurgeTask.
+func (c *toDoServiceClient) PurgeTask(ctx context.Context, req *connect.Request[proto.PurgeTaskRequest]) (*connect.Response[proto.PurgeTaskResponse], error) {
+	return c.purgeTask.CallUnary(ctx, req)
+}
+
+// ListAuditEntries calls todo.ToDoService.ListAuditEntries.
+func (c *toDoServiceClient) ListAuditEntries(ctx context.Context, req *connect.Request[proto.ListAuditEntriesRequest]) (*connect.Response[proto.ListAuditEntriesResponse], error) {
+	return c.listAuditEntries.CallUnary(ctx, req)
+}
+
+// GetTaskHistory calls todo.ToDoService.GetTaskHistory.
+func (c *toDoServiceClient) GetTaskHistory(ctx context.Context, req *connect.Request[proto.GetTaskHistoryRequest]) (*connect.Response[proto.GetTaskHistoryResponse], error) {
+	return c.getTaskHistory.CallUnary(ctx, req)
+}
+
+// SearchTasks calls todo.ToDoService.SearchTasks.
+func (c *toDoServiceClient) SearchTasks(ctx context.Context, req *connect.Request[proto.SearchTasksRequest]) (*connect.Response[proto.SearchTasksResponse], error) {
+	return c.searchTasks.CallUnary(ctx, req)
+}
+
+// CreateView calls todo.ToDoService.CreateView.
+func (c *toDoServiceClient) CreateView(ctx context.Context, req *connect.Request[proto.CreateViewRequest]) (*connect.Response[proto.CreateViewResponse], error) {
+	return c.createView.CallUnary(ctx, req)
+}
+
+// GetView calls todo.ToDoService.GetView.
+func (c *toDoServiceClient) GetView(ctx context.Context, req *connect.Request[proto.GetViewRequest]) (*connect.Response[proto.GetViewResponse], error) {
+	return c.getView.CallUnary(ctx, req)
+}
+
+// ListViews calls todo.ToDoService.ListViews.
+func (c *toDoServiceClient) ListViews(ctx context.Context, req *connect.Request[proto.ListViewsRequest]) (*connect.Response[proto.ListViewsResponse], error) {
+	return c.listViews.CallUnary(ctx, req)
+}
+
+// UpdateView calls todo.ToDoService.UpdateView.
+func (c *toDoServiceClient) UpdateView(ctx context.Context, req *connect.Request[proto.UpdateViewRequest]) (*connect.Response[proto.UpdateViewResponse], error) {
+	return c.updateView.CallUnary(ctx, req)
+}
+
+// DeleteView calls todo.ToDoService.DeleteView.
+func (c *toDoServiceClient) DeleteView(ctx context.Context, req *connect.Request[proto.DeleteViewRequest]) (*connect.Response[proto.DeleteViewResponse], error) {
+	return c.deleteView.CallUnary(ctx, req)
+}
+
+// ListTasksByView calls todo.ToDoService.ListTasksByView.
+func (c *toDoServiceClient) ListTasksByView(ctx context.Context, req *connect.Request[proto.ListTasksByViewRequest]) (*connect.Response[proto.ListTasksByViewResponse], error) {
+	return c.listTasksByView.CallUnary(ctx, req)
+}
+
+// GetTaskStats calls todo.ToDoService.GetTaskStats.
+func (c *toDoServiceClient) GetTaskStats(ctx context.Context, req *connect.Request[proto.GetTaskStatsRequest]) (*connect.Response[proto.GetTaskStatsResponse], error) {
+	return c.getTaskStats.CallUnary(ctx, req)
+}
+
+// ToDoServiceHandler is an implementation of the todo.ToDoService service.
+type ToDoServiceHandler interface {
+	CreateTask(context.Context, *connect.Request[proto.CreateTaskRequest]) (*connect.Response[proto.CreateTaskResponse], error)
+	GetAllTasks(context.Context, *connect.Request[proto.GetAllTasksRequest]) (*connect.Response[proto.GetAllTasksResponse], error)
+	GetTask(context.Context, *connect.Request[proto.GetTaskRequest]) (*connect.Response[proto.GetTaskResponse], error)
+	UpdateTask(context.Context, *connect.Request[proto.UpdateTaskRequest]) (*connect.Response[proto.UpdateTaskResponse], error)
+	UpdateTaskStatus(context.Context, *connect.Request[proto.UpdateTaskStatusRequest]) (*connect.Response[proto.UpdateTaskStatusResponse], error)
+	DeleteTask(context.Context, *connect.Request[proto.DeleteTaskRequest]) (*connect.Response[proto.DeleteTaskResponse], error)
+	BatchCreateTasks(context.Context, *connect.Request[proto.BatchCreateTasksRequest]) (*connect.Response[proto.BatchCreateTasksResponse], error)
+	BatchUpdateTaskStatus(context.Context, *connect.Request[proto.BatchUpdateTaskStatusRequest]) (*connect.Response[proto.BatchUpdateTaskStatusResponse], error)
+	BatchDeleteTasks(context.Context, *connect.Request[proto.BatchDeleteTasksRequest]) (*connect.Response[proto.BatchDeleteTasksResponse], error)
+	ImportTasks(context.Context, *connect.ClientStream[proto.ImportTasksRequest]) (*connect.Response[proto.ImportTasksResponse], error)
+	ExportTasks(context.Context, *connect.Request[proto.ExportTasksRequest], *connect.ServerStream[proto.ExportTasksResponse]) error
+	ExportDocument(context.Context, *connect.Request[proto.ExportDocumentRequest]) (*connect.Response[proto.ExportDocumentResponse], error)
+	ImportDocument(context.Context, *connect.Request[proto.ImportDocumentRequest]) (*connect.Response[proto.ImportDocumentResponse], error)
+	GetCalendarFeed(context.Context, *connect.Request[proto.GetCalendarFeedRequest]) (*connect.Response[proto.GetCalendarFeedResponse], error)
+	ImportCalendar(context.Context, *connect.Request[proto.ImportCalendarRequest]) (*connect.Response[proto.ImportCalendarResponse], error)
+	CreateWebhook(context.Context, *connect.Request[proto.CreateWebhookRequest]) (*connect.Response[proto.CreateWebhookResponse], error)
+	ListWebhooks(context.Context, *connect.Request[proto.ListWebhooksRequest]) (*connect.Response[proto.ListWebhooksResponse], error)
+	DeleteWebhook(context.Context, *connect.Request[proto.DeleteWebhookRequest]) (*connect.Response[proto.DeleteWebhookResponse], error)
+	ArchiveTask(context.Context, *connect.Request[proto.ArchiveTaskRequest]) (*connect.Response[proto.ArchiveTaskResponse], error)
+	ListArchivedTasks(context.Context, *connect.Request[proto.ListArchivedTasksRequest]) (*connect.Response[proto.ListArchivedTasksResponse], error)
+	RestoreTask(context.Context, *connect.Request[proto.RestoreTaskRequest]) (*connect.Response[proto.RestoreTaskResponse], error)
+	ListTrash(context.Context, *connect.Request[proto.ListTrashRequest]) (*connect.Response[proto.ListTrashResponse], error)
+	UndeleteTask(context.Context, *connect.Request[proto.UndeleteTaskRequest]) (*connect.Response[proto.UndeleteTaskResponse], error)
+	PurgeTask(context.Context, *connect.Request[proto.PurgeTaskRequest]) (*connect.Response[proto.PurgeTaskResponse], error)
+	ListAuditEntries(context.Context, *connect.Request[proto.ListAuditEntriesRequest]) (*connect.Response[proto.ListAuditEntriesResponse], error)
+	GetTaskHistory(context.Context, *connect.Request[proto.GetTaskHistoryRequest]) (*connect.Response[proto.GetTaskHistoryResponse], error)
+	SearchTasks(context.Context, *connect.Request[proto.SearchTasksRequest]) (*connect.Response[proto.SearchTasksResponse], error)
+	CreateView(context.Context, *connect.Request[proto.CreateViewRequest]) (*connect.Response[proto.CreateViewResponse], error)
+	GetView(context.Context, *connect.Request[proto.GetViewRequest]) (*connect.Response[proto.GetViewResponse], error)
+	ListViews(context.Context, *connect.Request[proto.ListViewsRequest]) (*connect.Response[proto.ListViewsResponse], error)
+	UpdateView(context.Context, *connect.Request[proto.UpdateViewRequest]) (*connect.Response[proto.UpdateViewResponse], error)
+	DeleteView(context.Context, *connect.Request[proto.DeleteViewRequest]) (*connect.Response[proto.DeleteViewResponse], error)
+	ListTasksByView(context.Context, *connect.Request[proto.ListTasksByViewRequest]) (*connect.Response[proto.ListTasksByViewResponse], error)
+	GetTaskStats(context.Context, *connect.Request[proto.GetTaskStatsRequest]) (*connect.Response[proto.GetTaskStatsResponse], error)
+}
+
+// NewToDoServiceHandler builds an HTTP handler from the service implementation. It returns the path
+// on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewToDoServiceHandler(svc ToDoServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	toDoServiceMethods := proto.File_proto_todo_proto.Services().ByName("ToDoService").Methods()
+	toDoServiceCreateTaskHandler := connect.NewUnaryHandler(
+		ToDoServiceCreateTaskProcedure,
+		svc.CreateTask,
+		connect.WithSchema(toDoServiceMethods.ByName("CreateTask")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceGetAllTasksHandler := connect.NewUnaryHandler(
+		ToDoServiceGetAllTasksProcedure,
+		svc.GetAllTasks,
+		connect.WithSchema(toDoServiceMethods.ByName("GetAllTasks")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceGetTaskHandler := connect.NewUnaryHandler(
+		ToDoServiceGetTaskProcedure,
+		svc.GetTask,
+		connect.WithSchema(toDoServiceMethods.ByName("GetTask")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceUpdateTaskHandler := connect.NewUnaryHandler(
+		ToDoServiceUpdateTaskProcedure,
+		svc.UpdateTask,
+		connect.WithSchema(toDoServiceMethods.ByName("UpdateTask")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceUpdateTaskStatusHandler := connect.NewUnaryHandler(
+		ToDoServiceUpdateTaskStatusProcedure,
+		svc.UpdateTaskStatus,
+		connect.WithSchema(toDoServiceMethods.ByName("UpdateTaskStatus")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceDeleteTaskHandler := connect.NewUnaryHandler(
+		ToDoServiceDeleteTaskProcedure,
+		svc.DeleteTask,
+		connect.WithSchema(toDoServiceMethods.ByName("DeleteTask")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceBatchCreateTasksHandler := connect.NewUnaryHandler(
+		ToDoServiceBatchCreateTasksProcedure,
+		svc.BatchCreateTasks,
+		connect.WithSchema(toDoServiceMethods.ByName("BatchCreateTasks")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceBatchUpdateTaskStatusHandler := connect.NewUnaryHandler(
+		ToDoServiceBatchUpdateTaskStatusProcedure,
+		svc.BatchUpdateTaskStatus,
+		connect.WithSchema(toDoServiceMethods.ByName("BatchUpdateTaskStatus")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceBatchDeleteTasksHandler := connect.NewUnaryHandler(
+		ToDoServiceBatchDeleteTasksProcedure,
+		svc.BatchDeleteTasks,
+		connect.WithSchema(toDoServiceMethods.ByName("BatchDeleteTasks")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceImportTasksHandler := connect.NewClientStreamHandler(
+		ToDoServiceImportTasksProcedure,
+		svc.ImportTasks,
+		connect.WithSchema(toDoServiceMethods.ByName("ImportTasks")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceExportTasksHandler := connect.NewServerStreamHandler(
+		ToDoServiceExportTasksProcedure,
+		svc.ExportTasks,
+		connect.WithSchema(toDoServiceMethods.ByName("ExportTasks")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceExportDocumentHandler := connect.NewUnaryHandler(
+		ToDoServiceExportDocumentProcedure,
+		svc.ExportDocument,
+		connect.WithSchema(toDoServiceMethods.ByName("ExportDocument")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceImportDocumentHandler := connect.NewUnaryHandler(
+		ToDoServiceImportDocumentProcedure,
+		svc.ImportDocument,
+		connect.WithSchema(toDoServiceMethods.ByName("ImportDocument")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceGetCalendarFeedHandler := connect.NewUnaryHandler(
+		ToDoServiceGetCalendarFeedProcedure,
+		svc.GetCalendarFeed,
+		connect.WithSchema(toDoServiceMethods.ByName("GetCalendarFeed")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceImportCalendarHandler := connect.NewUnaryHandler(
+		ToDoServiceImportCalendarProcedure,
+		svc.ImportCalendar,
+		connect.WithSchema(toDoServiceMethods.ByName("ImportCalendar")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceCreateWebhookHandler := connect.NewUnaryHandler(
+		ToDoServiceCreateWebhookProcedure,
+		svc.CreateWebhook,
+		connect.WithSchema(toDoServiceMethods.ByName("CreateWebhook")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceListWebhooksHandler := connect.NewUnaryHandler(
+		ToDoServiceListWebhooksProcedure,
+		svc.ListWebhooks,
+		connect.WithSchema(toDoServiceMethods.ByName("ListWebhooks")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceDeleteWebhookHandler := connect.NewUnaryHandler(
+		ToDoServiceDeleteWebhookProcedure,
+		svc.DeleteWebhook,
+		connect.WithSchema(toDoServiceMethods.ByName("DeleteWebhook")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceArchiveTaskHandler := connect.NewUnaryHandler(
+		ToDoServiceArchiveTaskProcedure,
+		svc.ArchiveTask,
+		connect.WithSchema(toDoServiceMethods.ByName("ArchiveTask")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceListArchivedTasksHandler := connect.NewUnaryHandler(
+		ToDoServiceListArchivedTasksProcedure,
+		svc.ListArchivedTasks,
+		connect.WithSchema(toDoServiceMethods.ByName("ListArchivedTasks")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceRestoreTaskHandler := connect.NewUnaryHandler(
+		ToDoServiceRestoreTaskProcedure,
+		svc.RestoreTask,
+		connect.WithSchema(toDoServiceMethods.ByName("RestoreTask")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceListTrashHandler := connect.NewUnaryHandler(
+		ToDoServiceListTrashProcedure,
+		svc.ListTrash,
+		connect.WithSchema(toDoServiceMethods.ByName("ListTrash")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceUndeleteTaskHandler := connect.NewUnaryHandler(
+		ToDoServiceUndeleteTaskProcedure,
+		svc.UndeleteTask,
+		connect.WithSchema(toDoServiceMethods.ByName("UndeleteTask")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServicePurgeTaskHandler := connect.NewUnaryHandler(
+		ToDoServicePurgeTaskProcedure,
+		svc.PurgeTask,
+		connect.WithSchema(toDoServiceMethods.ByName("PurgeTask")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceListAuditEntriesHandler := connect.NewUnaryHandler(
+		ToDoServiceListAuditEntriesProcedure,
+		svc.ListAuditEntries,
+		connect.WithSchema(toDoServiceMethods.ByName("ListAuditEntries")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceGetTaskHistoryHandler := connect.NewUnaryHandler(
+		ToDoServiceGetTaskHistoryProcedure,
+		svc.GetTaskHistory,
+		connect.WithSchema(toDoServiceMethods.ByName("GetTaskHistory")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceSearchTasksHandler := connect.NewUnaryHandler(
+		ToDoServiceSearchTasksProcedure,
+		svc.SearchTasks,
+		connect.WithSchema(toDoServiceMethods.ByName("SearchTasks")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceCreateViewHandler := connect.NewUnaryHandler(
+		ToDoServiceCreateViewProcedure,
+		svc.CreateView,
+		connect.WithSchema(toDoServiceMethods.ByName("CreateView")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceGetViewHandler := connect.NewUnaryHandler(
+		ToDoServiceGetViewProcedure,
+		svc.GetView,
+		connect.WithSchema(toDoServiceMethods.ByName("GetView")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceListViewsHandler := connect.NewUnaryHandler(
+		ToDoServiceListViewsProcedure,
+		svc.ListViews,
+		connect.WithSchema(toDoServiceMethods.ByName("ListViews")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceUpdateViewHandler := connect.NewUnaryHandler(
+		ToDoServiceUpdateViewProcedure,
+		svc.UpdateView,
+		connect.WithSchema(toDoServiceMethods.ByName("UpdateView")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceDeleteViewHandler := connect.NewUnaryHandler(
+		ToDoServiceDeleteViewProcedure,
+		svc.DeleteView,
+		connect.WithSchema(toDoServiceMethods.ByName("DeleteView")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceListTasksByViewHandler := connect.NewUnaryHandler(
+		ToDoServiceListTasksByViewProcedure,
+		svc.ListTasksByView,
+		connect.WithSchema(toDoServiceMethods.ByName("ListTasksByView")),
+		connect.WithHandlerOptions(opts...),
+	)
+	toDoServiceGetTaskStatsHandler := connect.NewUnaryHandler(
+		ToDoServiceGetTaskStatsProcedure,
+		svc.GetTaskStats,
+		connect.WithSchema(toDoServiceMethods.ByName("GetTaskStats")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/todo.ToDoService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case ToDoServiceCreateTaskProcedure:
+			toDoServiceCreateTaskHandler.ServeHTTP(w, r)
+		case ToDoServiceGetAllTasksProcedure:
+			toDoServiceGetAllTasksHandler.ServeHTTP(w, r)
+		case ToDoServiceGetTaskProcedure:
+			toDoServiceGetTaskHandler.ServeHTTP(w, r)
+		case ToDoServiceUpdateTaskProcedure:
+			toDoServiceUpdateTaskHandler.ServeHTTP(w, r)
+		case ToDoServiceUpdateTaskStatusProcedure:
+			toDoServiceUpdateTaskStatusHandler.ServeHTTP(w, r)
+		case ToDoServiceDeleteTaskProcedure:
+			toDoServiceDeleteTaskHandler.ServeHTTP(w, r)
+		case ToDoServiceBatchCreateTasksProcedure:
+			toDoServiceBatchCreateTasksHandler.ServeHTTP(w, r)
+		case ToDoServiceBatchUpdateTaskStatusProcedure:
+			toDoServiceBatchUpdateTaskStatusHandler.ServeHTTP(w, r)
+		case ToDoServiceBatchDeleteTasksProcedure:
+			toDoServiceBatchDeleteTasksHandler.ServeHTTP(w, r)
+		case ToDoServiceImportTasksProcedure:
+			toDoServiceImportTasksHandler.ServeHTTP(w, r)
+		case ToDoServiceExportTasksProcedure:
+			toDoServiceExportTasksHandler.ServeHTTP(w, r)
+		case ToDoServiceExportDocumentProcedure:
+			toDoServiceExportDocumentHandler.ServeHTTP(w, r)
+		case ToDoServiceImportDocumentProcedure:
+			toDoServiceImportDocumentHandler.ServeHTTP(w, r)
+		case ToDoServiceGetCalendarFeedProcedure:
+			toDoServiceGetCalendarFeedHandler.ServeHTTP(w, r)
+		case ToDoServiceImportCalendarProcedure:
+			toDoServiceImportCalendarHandler.ServeHTTP(w, r)
+		case ToDoServiceCreateWebhookProcedure:
+			toDoServiceCreateWebhookHandler.ServeHTTP(w, r)
+		case ToDoServiceListWebhooksProcedure:
+			toDoServiceListWebhooksHandler.ServeHTTP(w, r)
+		case ToDoServiceDeleteWebhookProcedure:
+			toDoServiceDeleteWebhookHandler.ServeHTTP(w, r)
+		case ToDoServiceArchiveTaskProcedure:
+			toDoServiceArchiveTaskHandler.ServeHTTP(w, r)
+		case ToDoServiceListArchivedTasksProcedure:
+			toDoServiceListArchivedTasksHandler.ServeHTTP(w, r)
+		case ToDoServiceRestoreTaskProcedure:
+			toDoServiceRestoreTaskHandler.ServeHTTP(w, r)
+		case ToDoServiceListTrashProcedure:
+			toDoServiceListTrashHandler.ServeHTTP(w, r)
+		case ToDoServiceUndeleteTaskProcedure:
+			toDoServiceUndeleteTaskHandler.ServeHTTP(w, r)
+		case ToDoServicePurgeTaskProcedure:
+			toDoServicePurgeTaskHandler.ServeHTTP(w, r)
+		case ToDoServiceListAuditEntriesProcedure:
+			toDoServiceListAuditEntriesHandler.ServeHTTP(w, r)
+		case ToDoServiceGetTaskHistoryProcedure:
+			toDoServiceGetTaskHistoryHandler.ServeHTTP(w, r)
+		case ToDoServiceSearchTasksProcedure:
+			toDoServiceSearchTasksHandler.ServeHTTP(w, r)
+		case ToDoServiceCreateViewProcedure:
+			toDoServiceCreateViewHandler.ServeHTTP(w, r)
+		case ToDoServiceGetViewProcedure:
+			toDoServiceGetViewHandler.ServeHTTP(w, r)
+		case ToDoServiceListViewsProcedure:
+			toDoServiceListViewsHandler.ServeHTTP(w, r)
+		case ToDoServiceUpdateViewProcedure:
+			toDoServiceUpdateViewHandler.ServeHTTP(w, r)
+		case ToDoServiceDeleteViewProcedure:
+			toDoServiceDeleteViewHandler.ServeHTTP(w, r)
+		case ToDoServiceListTasksByViewProcedure:
+			toDoServiceListTasksByViewHandler.ServeHTTP(w, r)
+		case ToDoServiceGetTaskStatsProcedure:
+			toDoServiceGetTaskStatsHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedToDoServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedToDoServiceHandler struct{}
+
+func (UnimplementedToDoServiceHandler) CreateTask(context.Context, *connect.Request[proto.CreateTaskRequest]) (*connect.Response[proto.CreateTaskResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.CreateTask is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) GetAllTasks(context.Context, *connect.Request[proto.GetAllTasksRequest]) (*connect.Response[proto.GetAllTasksResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.GetAllTasks is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) GetTask(context.Context, *connect.Request[proto.GetTaskRequest]) (*connect.Response[proto.GetTaskResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.GetTask is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) UpdateTask(context.Context, *connect.Request[proto.UpdateTaskRequest]) (*connect.Response[proto.UpdateTaskResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.UpdateTask is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) UpdateTaskStatus(context.Context, *connect.Request[proto.UpdateTaskStatusRequest]) (*connect.Response[proto.UpdateTaskStatusResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.UpdateTaskStatus is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) DeleteTask(context.Context, *connect.Request[proto.DeleteTaskRequest]) (*connect.Response[proto.DeleteTaskResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.DeleteTask is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) BatchCreateTasks(context.Context, *connect.Request[proto.BatchCreateTasksRequest]) (*connect.Response[proto.BatchCreateTasksResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.BatchCreateTasks is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) BatchUpdateTaskStatus(context.Context, *connect.Request[proto.BatchUpdateTaskStatusRequest]) (*connect.Response[proto.BatchUpdateTaskStatusResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.BatchUpdateTaskStatus is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) BatchDeleteTasks(context.Context, *connect.Request[proto.BatchDeleteTasksRequest]) (*connect.Response[proto.BatchDeleteTasksResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.BatchDeleteTasks is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) ImportTasks(context.Context, *connect.ClientStream[proto.ImportTasksRequest]) (*connect.Response[proto.ImportTasksResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.ImportTasks is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) ExportTasks(context.Context, *connect.Request[proto.ExportTasksRequest], *connect.ServerStream[proto.ExportTasksResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.ExportTasks is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) ExportDocument(context.Context, *connect.Request[proto.ExportDocumentRequest]) (*connect.Response[proto.ExportDocumentResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.ExportDocument is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) ImportDocument(context.Context, *connect.Request[proto.ImportDocumentRequest]) (*connect.Response[proto.ImportDocumentResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.ImportDocument is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) GetCalendarFeed(context.Context, *connect.Request[proto.GetCalendarFeedRequest]) (*connect.Response[proto.GetCalendarFeedResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.GetCalendarFeed is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) ImportCalendar(context.Context, *connect.Request[proto.ImportCalendarRequest]) (*connect.Response[proto.ImportCalendarResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.ImportCalendar is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) CreateWebhook(context.Context, *connect.Request[proto.CreateWebhookRequest]) (*connect.Response[proto.CreateWebhookResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.CreateWebhook is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) ListWebhooks(context.Context, *connect.Request[proto.ListWebhooksRequest]) (*connect.Response[proto.ListWebhooksResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.ListWebhooks is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) DeleteWebhook(context.Context, *connect.Request[proto.DeleteWebhookRequest]) (*connect.Response[proto.DeleteWebhookResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.DeleteWebhook is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) ArchiveTask(context.Context, *connect.Request[proto.ArchiveTaskRequest]) (*connect.Response[proto.ArchiveTaskResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.ArchiveTask is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) ListArchivedTasks(context.Context, *connect.Request[proto.ListArchivedTasksRequest]) (*connect.Response[proto.ListArchivedTasksResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.ListArchivedTasks is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) RestoreTask(context.Context, *connect.Request[proto.RestoreTaskRequest]) (*connect.Response[proto.RestoreTaskResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.RestoreTask is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) ListTrash(context.Context, *connect.Request[proto.ListTrashRequest]) (*connect.Response[proto.ListTrashResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.ListTrash is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) UndeleteTask(context.Context, *connect.Request[proto.UndeleteTaskRequest]) (*connect.Response[proto.UndeleteTaskResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.UndeleteTask is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) PurgeTask(context.Context, *connect.Request[proto.PurgeTaskRequest]) (*connect.Response[proto.PurgeTaskResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.PurgeTask is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) ListAuditEntries(context.Context, *connect.Request[proto.ListAuditEntriesRequest]) (*connect.Response[proto.ListAuditEntriesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.ListAuditEntries is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) GetTaskHistory(context.Context, *connect.Request[proto.GetTaskHistoryRequest]) (*connect.Response[proto.GetTaskHistoryResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.GetTaskHistory is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) SearchTasks(context.Context, *connect.Request[proto.SearchTasksRequest]) (*connect.Response[proto.SearchTasksResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.SearchTasks is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) CreateView(context.Context, *connect.Request[proto.CreateViewRequest]) (*connect.Response[proto.CreateViewResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.CreateView is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) GetView(context.Context, *connect.Request[proto.GetViewRequest]) (*connect.Response[proto.GetViewResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.GetView is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) ListViews(context.Context, *connect.Request[proto.ListViewsRequest]) (*connect.Response[proto.ListViewsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.ListViews is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) UpdateView(context.Context, *connect.Request[proto.UpdateViewRequest]) (*connect.Response[proto.UpdateViewResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.UpdateView is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) DeleteView(context.Context, *connect.Request[proto.DeleteViewRequest]) (*connect.Response[proto.DeleteViewResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.DeleteView is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) ListTasksByView(context.Context, *connect.Request[proto.ListTasksByViewRequest]) (*connect.Response[proto.ListTasksByViewResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.ListTasksByView is not implemented"))
+}
+
+func (UnimplementedToDoServiceHandler) GetTaskStats(context.Context, *connect.Request[proto.GetTaskStatsRequest]) (*connect.Response[proto.GetTaskStatsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.ToDoService.GetTaskStats is not implemented"))
+}
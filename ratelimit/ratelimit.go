@@ -0,0 +1,143 @@
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// idleTTL is how long an unused bucket is kept before it is evicted.
+const idleTTL = 10 * time.Minute
+
+// Limit is a token bucket refilled at Rate tokens per second holding at
+// most Burst tokens. A zero Rate disables limiting.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// Limiter keeps one token bucket per (method, client) pair.
+type Limiter struct {
+	defaultLimit Limit
+	methodLimits map[string]Limit
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// New creates a Limiter. methodLimits is keyed by the short RPC method name,
+// e.g. "CreateTask", and overrides defaultLimit for that method.
+func New(defaultLimit Limit, methodLimits map[string]Limit) *Limiter {
+	return &Limiter{
+		defaultLimit: defaultLimit,
+		methodLimits: methodLimits,
+		buckets:      make(map[string]*bucket),
+		now:          time.Now,
+	}
+}
+
+// Allow consumes a token for the caller identified by key. When the bucket
+// is empty it returns false together with the time until a token is available.
+func (l *Limiter) Allow(fullMethod, key string) (bool, time.Duration) {
+	method := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+
+	limit, ok := l.methodLimits[method]
+	if !ok {
+		limit = l.defaultLimit
+	}
+	if limit.Rate <= 0 {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	id := method + "|" + key
+	b, ok := l.buckets[id]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(rate.Limit(limit.Rate), limit.Burst)}
+		l.buckets[id] = b
+	}
+	b.lastSeen = now
+
+	r := b.limiter.ReserveN(now, 1)
+	if !r.OK() {
+		return false, time.Second
+	}
+	if delay := r.DelayFrom(now); delay > 0 {
+		r.CancelAt(now)
+		return false, delay
+	}
+
+	return true, 0
+}
+
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < idleTTL {
+		return
+	}
+	l.lastSweep = now
+
+	for id, b := range l.buckets {
+		if now.Sub(b.lastSeen) > idleTTL {
+			delete(l.buckets, id)
+		}
+	}
+}
+
+// ParseLimit reads a limit in the form "rate:burst", e.g. "5:10".
+func ParseLimit(s string) (Limit, error) {
+	rateStr, burstStr, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, expected rate:burst", s)
+	}
+
+	r, err := strconv.ParseFloat(rateStr, 64)
+	if err != nil || r < 0 {
+		return Limit{}, fmt.Errorf("invalid rate in %q", s)
+	}
+
+	burst, err := strconv.Atoi(burstStr)
+	if err != nil || burst < 1 {
+		return Limit{}, fmt.Errorf("invalid burst in %q", s)
+	}
+
+	return Limit{Rate: r, Burst: burst}, nil
+}
+
+// ParseMethodLimits reads a comma-separated list of Method=rate:burst entries.
+func ParseMethodLimits(s string) (map[string]Limit, error) {
+	limits := make(map[string]Limit)
+	if strings.TrimSpace(s) == "" {
+		return limits, nil
+	}
+
+	for _, entry := range strings.Split(s, ",") {
+		method, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || method == "" {
+			return nil, fmt.Errorf("invalid method rate limit %q, expected Method=rate:burst", entry)
+		}
+
+		limit, err := ParseLimit(value)
+		if err != nil {
+			return nil, err
+		}
+		limits[method] = limit
+	}
+
+	return limits, nil
+}
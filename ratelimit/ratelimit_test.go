@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiterAllow(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	l := New(Limit{Rate: 1, Burst: 2}, map[string]Limit{"GetAllTasks": {}})
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.Allow("/todo.ToDoService/CreateTask", "alice"); !ok {
+			t.Fatalf("Expected call %d to be allowed", i+1)
+		}
+	}
+
+	ok, retryAfter := l.Allow("/todo.ToDoService/CreateTask", "alice")
+	if ok {
+		t.Fatalf("Expected third call to be limited")
+	}
+	if retryAfter <= 0 || retryAfter > time.Second {
+		t.Errorf("Expected retry after within 1s, got %s", retryAfter)
+	}
+
+	if ok, _ := l.Allow("/todo.ToDoService/CreateTask", "bob"); !ok {
+		t.Errorf("Expected a different client to have its own bucket")
+	}
+
+	if ok, _ := l.Allow("/todo.ToDoService/GetAllTasks", "alice"); !ok {
+		t.Errorf("Expected unlimited method to be allowed")
+	}
+
+	now = now.Add(time.Second)
+	if ok, _ := l.Allow("/todo.ToDoService/CreateTask", "alice"); !ok {
+		t.Errorf("Expected bucket to refill after 1s")
+	}
+}
+
+func TestParseMethodLimits(t *testing.T) {
+	limits, err := ParseMethodLimits("CreateTask=1:5, GetAllTasks=20.5:40")
+	if err != nil {
+		t.Fatalf("ParseMethodLimits failed: %v", err)
+	}
+
+	if limits["CreateTask"] != (Limit{Rate: 1, Burst: 5}) || limits["GetAllTasks"] != (Limit{Rate: 20.5, Burst: 40}) {
+		t.Errorf("Unexpected limits: %v", limits)
+	}
+
+	if _, err := ParseMethodLimits("CreateTask=fast"); err == nil {
+		t.Errorf("Expected error for malformed limit")
+	}
+}
@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"grpc-todo/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	archiveCollection = "tasks_archive"
+	defaultPageSize   = 100
+)
+
+// mongoArchivedTask is a task in the archive collection.
+type mongoArchivedTask struct {
+	mongoTask  `bson:",inline"`
+	ArchivedAt int64 `bson:"archived_at"`
+}
+
+func (mt mongoArchivedTask) toDomain() *domain.Task {
+	task := mt.mongoTask.toDomain()
+	task.ArchivedAt = mt.ArchivedAt
+	return task
+}
+
+// archiveOne moves the task matching filter to the archive. It returns
+// mongo.ErrNoDocuments when no task matches.
+func (r *mongoRepository) archiveOne(ctx context.Context, filter bson.M, now int64) (*domain.Task, error) {
+	var mt mongoTask
+	if err := r.collection.FindOneAndDelete(ctx, filter).Decode(&mt); err != nil {
+		return nil, err
+	}
+
+	doc := mongoArchivedTask{mongoTask: mt, ArchivedAt: now}
+	if _, err := r.archive.InsertOne(ctx, doc); err != nil {
+		// Outside a transaction the task is already gone, so put it back.
+		if mongo.SessionFromContext(ctx) == nil {
+			if _, rerr := r.collection.InsertOne(ctx, mt); rerr != nil {
+				logDBError(ctx, "failed to restore task after failed archive", rerr, slog.String("task_id", mt.ID.Hex()))
+			}
+		}
+		return nil, err
+	}
+	return doc.toDomain(), nil
+}
+
+func (r *mongoRepository) ArchiveTask(ctx context.Context, id string) (*domain.Task, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidID, err)
+	}
+
+	var task *domain.Task
+	err = r.atomically(ctx, func(ctx context.Context) error {
+		var err error
+		if task, err = r.archiveOne(ctx, live(bson.M{"_id": objectID}), time.Now().Unix()); err != nil {
+			return err
+		}
+		return r.record(ctx, domain.EventTaskArchived, task)
+	})
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("task with ID %s: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		logDBError(ctx, "failed to archive task", err, slog.String("task_id", id))
+		return nil, fmt.Errorf("failed to archive task: %v", err)
+	}
+
+	return task, nil
+}
+
+func (r *mongoRepository) ListArchivedTasks(ctx context.Context, pageSize int, pageToken string) ([]*domain.Task, string, error) {
+	tasks, next, err := listPage(ctx, r.archive, bson.M{}, pageSize, pageToken)
+	if err != nil && !errors.Is(err, ErrInvalidPageToken) {
+		logDBError(ctx, "failed to find archived tasks", err)
+		return nil, "", fmt.Errorf("failed to find archived tasks: %v", err)
+	}
+	return tasks, next, err
+}
+
+// listPage returns a page of the tasks in coll that match filter, in ID
+// order, and the token of the next page. Live and trashed tasks decode with
+// a zero ArchivedAt.
+func listPage(ctx context.Context, coll *mongo.Collection, filter bson.M, pageSize int, pageToken string) ([]*domain.Task, string, error) {
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if pageToken != "" {
+		after, err := decodePageToken(pageToken)
+		if err != nil {
+			return nil, "", err
+		}
+		filter["_id"] = bson.M{"$gt": after}
+	}
+
+	// One task more than asked for tells whether there is another page.
+	opts := options.Find().SetSort(bson.M{"_id": 1}).SetLimit(int64(pageSize) + 1)
+
+	var docs []mongoArchivedTask
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err == nil {
+		err = cursor.All(ctx, &docs)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if len(docs) > pageSize {
+		docs = docs[:pageSize]
+		next = encodePageToken(docs[pageSize-1].ID)
+	}
+
+	tasks := make([]*domain.Task, len(docs))
+	for i, mt := range docs {
+		tasks[i] = mt.toDomain()
+	}
+	return tasks, next, nil
+}
+
+func (r *mongoRepository) GetArchivedTask(ctx context.Context, id string) (*domain.Task, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidID, err)
+	}
+
+	var doc mongoArchivedTask
+	err = r.archive.FindOne(ctx, bson.M{"_id": objectID}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("archived task with ID %s: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		logDBError(ctx, "failed to find archived task", err, slog.String("task_id", id))
+		return nil, fmt.Errorf("failed to find archived task: %v", err)
+	}
+
+	return doc.toDomain(), nil
+}
+
+// RestoreTask moves the task back with its original ID. Its status counts as
+// entered at the time of the restore, so that the retention policy that
+// archived it does not archive it again right away.
+func (r *mongoRepository) RestoreTask(ctx context.Context, id string) (*domain.Task, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidID, err)
+	}
+
+	var task *domain.Task
+	err = r.atomically(ctx, func(ctx context.Context) error {
+		var doc mongoArchivedTask
+		if err := r.archive.FindOneAndDelete(ctx, bson.M{"_id": objectID}).Decode(&doc); err != nil {
+			return err
+		}
+
+		mt := doc.mongoTask
+		mt.StatusChangedAt = time.Now().Unix()
+		if _, err := r.collection.InsertOne(ctx, mt); err != nil {
+			if mongo.SessionFromContext(ctx) == nil {
+				if _, rerr := r.archive.InsertOne(ctx, doc); rerr != nil {
+					logDBError(ctx, "failed to re-archive task after failed restore", rerr, slog.String("task_id", id))
+				}
+			}
+			return err
+		}
+
+		task = mt.toDomain()
+		return r.record(ctx, domain.EventTaskRestored, task)
+	})
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("archived task with ID %s: %w", id, ErrNotFound)
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, fmt.Errorf("task with ID %s: %w", id, ErrAlreadyExists)
+	}
+	if err != nil {
+		logDBError(ctx, "failed to restore task", err, slog.String("task_id", id))
+		return nil, fmt.Errorf("failed to restore task: %v", err)
+	}
+
+	return task, nil
+}
+
+// Page tokens are the last ID of the previous page, kept opaque so that the
+// ordering can change without breaking clients.
+func encodePageToken(id primitive.ObjectID) string {
+	return base64.RawURLEncoding.EncodeToString(id[:])
+}
+
+func decodePageToken(token string) (primitive.ObjectID, error) {
+	var id primitive.ObjectID
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(b) != len(id) {
+		return id, fmt.Errorf("%w: %q", ErrInvalidPageToken, token)
+	}
+	copy(id[:], b)
+	return id, nil
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"grpc-todo/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	auditCollection = "audit_log"
+	// indexOptionsConflict is the error code for creating an index that
+	// exists with other options.
+	indexOptionsConflict = 85
+)
+
+// AuditFilter selects audit entries. Empty fields match everything; entries
+// match from Since up to but excluding Until.
+type AuditFilter struct {
+	TaskID string
+	Actor  string
+	Since  time.Time
+	Until  time.Time
+}
+
+// AuditRepository appends to and reads the audit log. Entries cannot be
+// changed or removed; the database drops them once they are older than the
+// log's TTL.
+type AuditRepository interface {
+	AppendAudit(ctx context.Context, entries ...*domain.AuditEntry) error
+	// ListAuditEntries returns a page of the entries that match f, oldest
+	// first, like ListArchivedTasks.
+	ListAuditEntries(ctx context.Context, f AuditFilter, pageSize int, pageToken string) ([]*domain.AuditEntry, string, error)
+}
+
+type mongoFieldChange struct {
+	Field  string `bson:"field"`
+	Before string `bson:"before,omitempty"`
+	After  string `bson:"after,omitempty"`
+}
+
+type mongoAuditEntry struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	TaskID    string             `bson:"task_id"`
+	Actor     string             `bson:"actor"`
+	Method    string             `bson:"method"`
+	RequestID string             `bson:"request_id,omitempty"`
+	Changes   []mongoFieldChange `bson:"changes"`
+	Timestamp time.Time          `bson:"timestamp"`
+}
+
+func (me mongoAuditEntry) toDomain() *domain.AuditEntry {
+	e := &domain.AuditEntry{
+		Id:        me.ID.Hex(),
+		TaskId:    me.TaskID,
+		Actor:     me.Actor,
+		Method:    me.Method,
+		RequestId: me.RequestID,
+		Timestamp: me.Timestamp,
+	}
+	for _, c := range me.Changes {
+		e.Changes = append(e.Changes, domain.FieldChange{Field: c.Field, Before: c.Before, After: c.After})
+	}
+	return e
+}
+
+type mongoAuditRepository struct {
+	audit *mongo.Collection
+}
+
+// NewAuditRepository returns the audit log and makes sure its entries expire
+// after ttl, updating the TTL of an existing log.
+func NewAuditRepository(ctx context.Context, db *mongo.Database, ttl time.Duration) (AuditRepository, error) {
+	r := &mongoAuditRepository{audit: db.Collection(auditCollection)}
+
+	keys := bson.D{{Key: "timestamp", Value: 1}}
+	seconds := int32(ttl / time.Second)
+	_, err := r.audit.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    keys,
+		Options: options.Index().SetExpireAfterSeconds(seconds),
+	})
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code == indexOptionsConflict {
+		err = db.RunCommand(ctx, bson.D{
+			{Key: "collMod", Value: auditCollection},
+			{Key: "index", Value: bson.D{{Key: "keyPattern", Value: keys}, {Key: "expireAfterSeconds", Value: seconds}}},
+		}).Err()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create audit log index: %v", err)
+	}
+
+	if _, err := r.audit.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "actor", Value: 1}, {Key: "_id", Value: 1}}},
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create audit log index: %v", err)
+	}
+	return r, nil
+}
+
+func (r *mongoAuditRepository) AppendAudit(ctx context.Context, entries ...*domain.AuditEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, len(entries))
+	for i, e := range entries {
+		me := mongoAuditEntry{
+			ID:        primitive.NewObjectID(),
+			TaskID:    e.TaskId,
+			Actor:     e.Actor,
+			Method:    e.Method,
+			RequestID: e.RequestId,
+			Timestamp: e.Timestamp,
+		}
+		for _, c := range e.Changes {
+			me.Changes = append(me.Changes, mongoFieldChange{Field: c.Field, Before: c.Before, After: c.After})
+		}
+		docs[i] = me
+		e.Id = me.ID.Hex()
+	}
+
+	if _, err := r.audit.InsertMany(ctx, docs); err != nil {
+		logDBError(ctx, "failed to write audit entries", err, slog.Int("count", len(entries)))
+		return fmt.Errorf("failed to write audit entries: %v", err)
+	}
+	return nil
+}
+
+func (r *mongoAuditRepository) ListAuditEntries(ctx context.Context, f AuditFilter, pageSize int, pageToken string) ([]*domain.AuditEntry, string, error) {
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+
+	filter := bson.M{}
+	if f.TaskID != "" {
+		filter["task_id"] = f.TaskID
+	}
+	if f.Actor != "" {
+		filter["actor"] = f.Actor
+	}
+	timestamp := bson.M{}
+	if !f.Since.IsZero() {
+		timestamp["$gte"] = f.Since
+	}
+	if !f.Until.IsZero() {
+		timestamp["$lt"] = f.Until
+	}
+	if len(timestamp) > 0 {
+		filter["timestamp"] = timestamp
+	}
+	if pageToken != "" {
+		after, err := decodePageToken(pageToken)
+		if err != nil {
+			return nil, "", err
+		}
+		filter["_id"] = bson.M{"$gt": after}
+	}
+
+	opts := options.Find().SetSort(bson.M{"_id": 1}).SetLimit(int64(pageSize) + 1)
+
+	var docs []mongoAuditEntry
+	cursor, err := r.audit.Find(ctx, filter, opts)
+	if err == nil {
+		err = cursor.All(ctx, &docs)
+	}
+	if err != nil {
+		logDBError(ctx, "failed to find audit entries", err)
+		return nil, "", fmt.Errorf("failed to find audit entries: %v", err)
+	}
+
+	var next string
+	if len(docs) > pageSize {
+		docs = docs[:pageSize]
+		next = encodePageToken(docs[pageSize-1].ID)
+	}
+
+	entries := make([]*domain.AuditEntry, len(docs))
+	for i, me := range docs {
+		entries[i] = me.toDomain()
+	}
+	return entries, next, nil
+}
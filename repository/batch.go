@@ -0,0 +1,371 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"grpc-todo/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const duplicateKeyCode = 11000
+
+// StatusUpdate is one item of BatchUpdateTaskStatus.
+type StatusUpdate struct {
+	ID     string
+	Status string
+}
+
+func (r *mongoRepository) BatchCreateTasks(ctx context.Context, tasks []*domain.Task, allOrNothing bool) ([]error, error) {
+	// IDs are assigned up front so that a failed all-or-nothing batch can be
+	// undone on deployments without transactions.
+	ids := make([]primitive.ObjectID, len(tasks))
+	initial := make([]error, len(tasks))
+	var docs []interface{}
+	var index []int
+	for i, task := range tasks {
+		ids[i] = primitive.NewObjectID()
+		if task.Id != "" {
+			id, err := primitive.ObjectIDFromHex(task.Id)
+			if err != nil {
+				initial[i] = fmt.Errorf("%w: %v", ErrInvalidID, err)
+				continue
+			}
+			ids[i] = id
+		}
+		doc := newMongoTask(task)
+		doc.ID = ids[i]
+		docs = append(docs, doc)
+		index = append(index, i)
+	}
+
+	apply := func(ctx context.Context, errs []error) error {
+		if len(docs) == 0 {
+			return nil
+		}
+		_, err := r.collection.InsertMany(ctx, docs, options.InsertMany().SetOrdered(allOrNothing))
+		if err := writeErrors(err, errs, index, "failed to insert task"); err != nil {
+			return err
+		}
+		if allOrNothing && hasFailures(errs) {
+			return nil
+		}
+
+		var created []*domain.Task
+		for _, i := range index {
+			if errs[i] == nil {
+				task := *tasks[i]
+				task.Id = ids[i].Hex()
+				created = append(created, &task)
+			}
+		}
+		return r.record(ctx, domain.EventTaskCreated, created...)
+	}
+	// The insert is ordered when undo is needed, so everything before the
+	// first failed item was written and nothing after it was.
+	undo := func(ctx context.Context, errs []error) error {
+		var written []primitive.ObjectID
+		for _, i := range index {
+			if errs[i] != nil {
+				break
+			}
+			written = append(written, ids[i])
+		}
+		_, err := r.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": written}})
+		return err
+	}
+
+	errs, err := r.runBatch(ctx, initial, allOrNothing, nil, apply, undo)
+	if err != nil {
+		logDBError(ctx, "failed to insert tasks", err, slog.Int("count", len(tasks)))
+		return nil, fmt.Errorf("failed to insert tasks: %v", err)
+	}
+
+	for i, task := range tasks {
+		if errs[i] == nil {
+			task.Id = ids[i].Hex()
+		}
+	}
+	return errs, nil
+}
+
+func (r *mongoRepository) BatchUpdateTaskStatus(ctx context.Context, updates []StatusUpdate, changedBy string, allOrNothing bool) ([]error, error) {
+	hexIDs := make([]string, len(updates))
+	for i, u := range updates {
+		hexIDs[i] = u.ID
+	}
+	ids, initial := parseIDs(hexIDs)
+
+	check := func(ctx context.Context, errs []error) error {
+		return r.markMissing(ctx, hexIDs, ids, errs)
+	}
+	apply := func(ctx context.Context, errs []error) error {
+		now := time.Now().Unix()
+		var models []mongo.WriteModel
+		var index []int
+		for i, u := range updates {
+			if errs[i] != nil {
+				continue
+			}
+			models = append(models, mongo.NewUpdateOneModel().
+				SetFilter(live(bson.M{"_id": ids[i]})).
+				SetUpdate(statusUpdate(u.Status, changedBy, now)))
+			index = append(index, i)
+		}
+
+		if err := r.bulkWrite(ctx, models, index, errs, allOrNothing, "failed to update task"); err != nil {
+			return err
+		}
+		if allOrNothing && hasFailures(errs) {
+			return nil
+		}
+		if r.outbox == nil {
+			return nil
+		}
+
+		updated, err := r.findTasks(ctx, applied(ids, index, errs))
+		if err != nil {
+			return err
+		}
+		return r.recordItems(ctx, domain.EventTaskStatusUpdated, ids, index, errs, updated)
+	}
+
+	errs, err := r.runBatch(ctx, initial, allOrNothing, check, apply, nil)
+	if err != nil {
+		logDBError(ctx, "failed to update tasks", err, slog.Int("count", len(updates)))
+		return nil, fmt.Errorf("failed to update tasks: %v", err)
+	}
+	return errs, nil
+}
+
+func (r *mongoRepository) BatchDeleteTasks(ctx context.Context, hexIDs []string, deletedBy string, allOrNothing bool) ([]error, error) {
+	ids, initial := parseIDs(hexIDs)
+
+	check := func(ctx context.Context, errs []error) error {
+		return r.markMissing(ctx, hexIDs, ids, errs)
+	}
+	apply := func(ctx context.Context, errs []error) error {
+		update := trashUpdate(deletedBy, time.Now().Unix())
+		var models []mongo.WriteModel
+		var index []int
+		for i := range hexIDs {
+			if errs[i] != nil {
+				continue
+			}
+			models = append(models, mongo.NewUpdateOneModel().
+				SetFilter(live(bson.M{"_id": ids[i]})).
+				SetUpdate(update))
+			index = append(index, i)
+		}
+
+		// Deletion events carry the task, so read them while they exist.
+		var deleted map[primitive.ObjectID]*domain.Task
+		if r.outbox != nil {
+			var err error
+			if deleted, err = r.findTasks(ctx, applied(ids, index, errs)); err != nil {
+				return err
+			}
+		}
+
+		if err := r.bulkWrite(ctx, models, index, errs, allOrNothing, "failed to delete task"); err != nil {
+			return err
+		}
+		if allOrNothing && hasFailures(errs) {
+			return nil
+		}
+		return r.recordItems(ctx, domain.EventTaskDeleted, ids, index, errs, deleted)
+	}
+
+	errs, err := r.runBatch(ctx, initial, allOrNothing, check, apply, nil)
+	if err != nil {
+		logDBError(ctx, "failed to delete tasks", err, slog.Int("count", len(hexIDs)))
+		return nil, fmt.Errorf("failed to delete tasks: %v", err)
+	}
+	return errs, nil
+}
+
+// runBatch applies a batch and returns the per-item errors. check and apply
+// record item failures in the slice they are given, which starts as a copy of
+// initial on every attempt because transactions may be retried.
+//
+// All-or-nothing batches stop before apply if check reported a failure. They
+// run in a transaction when the deployment supports one; otherwise undo, if
+// set, compensates for whatever apply wrote before an item failed. Other
+// batches never run in a transaction, because a failed item would abort it,
+// so their outbox messages are written after the change rather than with it.
+func (r *mongoRepository) runBatch(ctx context.Context, initial []error, allOrNothing bool, check, apply, undo func(context.Context, []error) error) ([]error, error) {
+	errs := make([]error, len(initial))
+	attempt := func(ctx context.Context) error {
+		copy(errs, initial)
+		if check != nil {
+			if err := check(ctx, errs); err != nil {
+				return err
+			}
+		}
+		if allOrNothing && hasFailures(errs) {
+			return ErrBatchAborted
+		}
+		if err := apply(ctx, errs); err != nil {
+			return err
+		}
+		if allOrNothing && hasFailures(errs) {
+			return ErrBatchAborted
+		}
+		return nil
+	}
+
+	var err error
+	switch {
+	case !allOrNothing:
+		err = attempt(ctx)
+	case r.supportsTransactions(ctx):
+		err = r.inTransaction(ctx, attempt)
+	default:
+		err = attempt(ctx)
+		if err != nil && undo != nil {
+			if uerr := undo(ctx, errs); uerr != nil {
+				logDBError(ctx, "failed to undo batch", uerr)
+			}
+		}
+	}
+
+	if errors.Is(err, ErrBatchAborted) {
+		for i := range errs {
+			if errs[i] == nil {
+				errs[i] = ErrBatchAborted
+			}
+		}
+		return errs, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return errs, nil
+}
+
+// bulkWrite runs models, where models[i] belongs to item index[i], and
+// records write failures against their items.
+func (r *mongoRepository) bulkWrite(ctx context.Context, models []mongo.WriteModel, index []int, errs []error, ordered bool, msg string) error {
+	if len(models) == 0 {
+		return nil
+	}
+	_, err := r.collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(ordered))
+	return writeErrors(err, errs, index, msg)
+}
+
+// writeErrors spreads the write errors of a bulk operation over the items
+// they belong to. A nil index means the operation covered every item in
+// order. Errors that are not about individual documents are returned.
+func writeErrors(err error, errs []error, index []int, msg string) error {
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil || len(bulkErr.WriteErrors) == 0 {
+		return err
+	}
+
+	for _, we := range bulkErr.WriteErrors {
+		i := we.Index
+		if index != nil {
+			i = index[i]
+		}
+		if we.Code == duplicateKeyCode {
+			errs[i] = fmt.Errorf("%s: %w", msg, ErrAlreadyExists)
+			continue
+		}
+		errs[i] = fmt.Errorf("%s: %s", msg, we.Message)
+	}
+	return nil
+}
+
+// markMissing records ErrNotFound for the items whose task does not exist.
+func (r *mongoRepository) markMissing(ctx context.Context, hexIDs []string, ids []primitive.ObjectID, errs []error) error {
+	var lookup []primitive.ObjectID
+	for i, id := range ids {
+		if errs[i] == nil {
+			lookup = append(lookup, id)
+		}
+	}
+	if len(lookup) == 0 {
+		return nil
+	}
+
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+	cursor, err := r.collection.Find(ctx, live(bson.M{"_id": bson.M{"$in": lookup}}), opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	found := make(map[primitive.ObjectID]bool, len(lookup))
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+		found[doc.ID] = true
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+
+	for i, id := range ids {
+		if errs[i] == nil && !found[id] {
+			errs[i] = fmt.Errorf("task with ID %s: %w", hexIDs[i], ErrNotFound)
+		}
+	}
+	return nil
+}
+
+// applied returns the IDs of the items in index that have not failed.
+func applied(ids []primitive.ObjectID, index []int, errs []error) []primitive.ObjectID {
+	var res []primitive.ObjectID
+	for _, i := range index {
+		if errs[i] == nil {
+			res = append(res, ids[i])
+		}
+	}
+	return res
+}
+
+// recordItems writes outbox messages for the items in index that were
+// applied, using the task snapshots in tasks.
+func (r *mongoRepository) recordItems(ctx context.Context, event string, ids []primitive.ObjectID, index []int, errs []error, tasks map[primitive.ObjectID]*domain.Task) error {
+	var changed []*domain.Task
+	for _, i := range index {
+		if t := tasks[ids[i]]; errs[i] == nil && t != nil {
+			changed = append(changed, t)
+		}
+	}
+	return r.record(ctx, event, changed...)
+}
+
+func parseIDs(hexIDs []string) ([]primitive.ObjectID, []error) {
+	ids := make([]primitive.ObjectID, len(hexIDs))
+	errs := make([]error, len(hexIDs))
+	for i, hex := range hexIDs {
+		id, err := primitive.ObjectIDFromHex(hex)
+		if err != nil {
+			errs[i] = fmt.Errorf("%w: %v", ErrInvalidID, err)
+			continue
+		}
+		ids[i] = id
+	}
+	return ids, errs
+}
+
+func hasFailures(errs []error) bool {
+	for _, err := range errs {
+		if err != nil {
+			return true
+		}
+	}
+	return false
+}
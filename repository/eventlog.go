@@ -0,0 +1,430 @@
+package repository
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	eventsCollection    = "task_events"
+	snapshotsCollection = "task_snapshots"
+	relayCollection     = "task_events_relay"
+)
+
+// ErrConflict is returned by EventLog.Append when another writer has
+// already appended a commit with the same sequence number.
+var ErrConflict = errors.New("conflicting commit")
+
+// TaskEvent is one change to a task. Type is one of the domain.EventTask
+// constants; task.deleted moves a task to the trash and task.purged removes
+// it for good, whether or not it was in the trash.
+type TaskEvent struct {
+	Type   string `json:"type" bson:"type"`
+	TaskID string `json:"task_id" bson:"task_id"`
+	// Actor is who deleted the task or changed its status.
+	Actor string `json:"actor,omitempty" bson:"actor,omitempty"`
+	// Task is the new task of a task.created event.
+	Task *TaskState `json:"task,omitempty" bson:"task,omitempty"`
+	// Title, Description and DueAt are set by task.updated; a nil DueAt
+	// leaves the due date alone.
+	Title       string `json:"title,omitempty" bson:"title,omitempty"`
+	Description string `json:"description,omitempty" bson:"description,omitempty"`
+	DueAt       *int64 `json:"due_at,omitempty" bson:"due_at,omitempty"`
+	// Status is set by task.status_updated.
+	Status string `json:"status,omitempty" bson:"status,omitempty"`
+}
+
+// Commit is a group of events written together. Commits are numbered from
+// 1 without gaps, and At is when the commit was written; every time a
+// projection derives from the events, such as when a status changed, is
+// At.
+type Commit struct {
+	Seq    int64       `json:"seq" bson:"_id"`
+	At     time.Time   `json:"at" bson:"at"`
+	Events []TaskEvent `json:"events" bson:"events"`
+}
+
+// StateTransition is a status change kept with the task it belongs to.
+type StateTransition struct {
+	From      string `json:"from" bson:"from"`
+	To        string `json:"to" bson:"to"`
+	ChangedBy string `json:"changed_by,omitempty" bson:"changed_by,omitempty"`
+	ChangedAt int64  `json:"changed_at" bson:"changed_at"`
+}
+
+// TaskState is a task as the events of a log leave it.
+type TaskState struct {
+	ID              string            `json:"id" bson:"id"`
+	Title           string            `json:"title" bson:"title"`
+	Description     string            `json:"description,omitempty" bson:"description,omitempty"`
+	Status          string            `json:"status" bson:"status"`
+	CreatedAt       int64             `json:"created_at" bson:"created_at"`
+	Owner           string            `json:"owner,omitempty" bson:"owner,omitempty"`
+	DueAt           int64             `json:"due_at,omitempty" bson:"due_at,omitempty"`
+	ICalUID         string            `json:"ical_uid,omitempty" bson:"ical_uid,omitempty"`
+	CompletedAt     int64             `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+	StatusChangedAt int64             `json:"status_changed_at,omitempty" bson:"status_changed_at,omitempty"`
+	ArchivedAt      int64             `json:"archived_at,omitempty" bson:"archived_at,omitempty"`
+	DeletedAt       int64             `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+	DeletedBy       string            `json:"deleted_by,omitempty" bson:"deleted_by,omitempty"`
+	History         []StateTransition `json:"history,omitempty" bson:"history,omitempty"`
+}
+
+// Snapshot is every task as of the commit numbered Seq, written at At.
+type Snapshot struct {
+	Seq   int64        `json:"seq" bson:"_id"`
+	At    time.Time    `json:"at" bson:"at"`
+	Tasks []*TaskState `json:"tasks" bson:"tasks"`
+}
+
+// EventLog is the append-only log an event-sourced repository keeps its
+// tasks in, along with snapshots of the tasks at some of its commits.
+type EventLog interface {
+	// Append writes c, which must be numbered one after the last commit.
+	// It fails with ErrConflict when that number is taken.
+	Append(ctx context.Context, c *Commit) error
+	// Read calls fn for every commit numbered after seq, in order, until
+	// fn returns an error, which Read returns.
+	Read(ctx context.Context, after int64, fn func(*Commit) error) error
+	SaveSnapshot(ctx context.Context, s *Snapshot) error
+	// LatestSnapshot returns the latest snapshot taken at or before at, or
+	// the latest of all when at is zero. It returns nil when there is none.
+	LatestSnapshot(ctx context.Context, at time.Time) (*Snapshot, error)
+}
+
+type mongoEventLog struct {
+	events    *mongo.Collection
+	snapshots *mongo.Collection
+	relay     *mongo.Collection
+}
+
+// NewMongoEventLog returns a log kept in the task_events and task_snapshots
+// collections, with the position of its outbox relay in task_events_relay.
+// Several processes may share it.
+func NewMongoEventLog(db *mongo.Database) EventLog {
+	return &mongoEventLog{
+		events:    db.Collection(eventsCollection),
+		snapshots: db.Collection(snapshotsCollection),
+		relay:     db.Collection(relayCollection),
+	}
+}
+
+func (l *mongoEventLog) Append(ctx context.Context, c *Commit) error {
+	_, err := l.events.InsertOne(ctx, c)
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("commit %d: %w", c.Seq, ErrConflict)
+	}
+	return err
+}
+
+func (l *mongoEventLog) Read(ctx context.Context, after int64, fn func(*Commit) error) error {
+	opts := options.Find().SetSort(bson.M{"_id": 1})
+	cursor, err := l.events.Find(ctx, bson.M{"_id": bson.M{"$gt": after}}, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var c Commit
+		if err := cursor.Decode(&c); err != nil {
+			return err
+		}
+		if err := fn(&c); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+func (l *mongoEventLog) SaveSnapshot(ctx context.Context, s *Snapshot) error {
+	opts := options.Replace().SetUpsert(true)
+	_, err := l.snapshots.ReplaceOne(ctx, bson.M{"_id": s.Seq}, s, opts)
+	return err
+}
+
+func (l *mongoEventLog) LatestSnapshot(ctx context.Context, at time.Time) (*Snapshot, error) {
+	filter := bson.M{}
+	if !at.IsZero() {
+		filter["at"] = bson.M{"$lte": at}
+	}
+	opts := options.FindOne().SetSort(bson.M{"_id": -1})
+
+	var s Snapshot
+	err := l.snapshots.FindOne(ctx, filter, opts).Decode(&s)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// relayPosition is the last commit an outbox relay has published and
+// until when a relay holds the log.
+type relayPosition struct {
+	Seq         int64     `json:"seq" bson:"seq"`
+	LockedUntil time.Time `json:"-" bson:"locked_until"`
+}
+
+const relayID = "outbox"
+
+func (l *mongoEventLog) claimRelay(ctx context.Context, now time.Time, lease time.Duration) (int64, bool, error) {
+	filter := bson.M{"_id": relayID, "locked_until": bson.M{"$lte": now}}
+	update := bson.M{
+		"$set":         bson.M{"locked_until": now.Add(lease)},
+		"$setOnInsert": bson.M{"seq": int64(0)},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var pos relayPosition
+	err := l.relay.FindOneAndUpdate(ctx, filter, update, opts).Decode(&pos)
+	if mongo.IsDuplicateKeyError(err) {
+		// The document exists and its lease has not run out.
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return pos.Seq, true, nil
+}
+
+func (l *mongoEventLog) saveRelay(ctx context.Context, seq int64, release bool) error {
+	update := bson.M{"$max": bson.M{"seq": seq}}
+	if release {
+		update["$set"] = bson.M{"locked_until": time.Time{}}
+	}
+	_, err := l.relay.UpdateOne(ctx, bson.M{"_id": relayID}, update)
+	return err
+}
+
+// fileEventLog keeps commits as JSON lines in events.jsonl, each snapshot
+// in its own file under snapshots/ and the position of its outbox relay in
+// relay.json.
+type fileEventLog struct {
+	dir string
+
+	mu sync.Mutex
+	f  *os.File
+	// last is the number of the last commit in the file and end the
+	// offset just after it, so that reads of new commits start there.
+	last int64
+	end  int64
+	// relayLockedUntil is when the lease of the relay runs out; only one
+	// process uses the directory, so it need not be stored.
+	relayLockedUntil time.Time
+}
+
+// NewFileEventLog opens or creates a log in dir. Only one process may use a
+// directory at a time.
+func NewFileEventLog(dir string) (EventLog, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "snapshots"), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(filepath.Join(dir, "events.jsonl"), os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &fileEventLog{dir: dir, f: f}
+	err = l.Read(context.Background(), 0, func(*Commit) error { return nil })
+	if err == nil {
+		// Drop what a crash left of a last commit.
+		err = f.Truncate(l.end)
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *fileEventLog) Append(_ context.Context, c *Commit) error {
+	line, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if c.Seq != l.last+1 {
+		return fmt.Errorf("commit %d: %w", c.Seq, ErrConflict)
+	}
+	// A commit is one line, so a crash leaves at most a partial last line,
+	// which Read ignores and NewFileEventLog drops.
+	if _, err := l.f.WriteAt(append(line, '\n'), l.end); err != nil {
+		return err
+	}
+	if err := l.f.Sync(); err != nil {
+		return err
+	}
+	l.last, l.end = c.Seq, l.end+int64(len(line))+1
+	return nil
+}
+
+func (l *fileEventLog) Read(ctx context.Context, after int64, fn func(*Commit) error) error {
+	l.mu.Lock()
+	start, last, end := int64(0), l.last, l.end
+	if after >= l.last {
+		start = l.end
+	}
+	l.mu.Unlock()
+
+	r := bufio.NewReader(io.NewSectionReader(l.f, start, 1<<62))
+	offset := start
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		line, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// Nothing, or a line cut short by a crash.
+			break
+		}
+		if err != nil {
+			return err
+		}
+		offset += int64(len(line))
+
+		var c Commit
+		if err := json.Unmarshal(line, &c); err != nil {
+			return fmt.Errorf("corrupt commit at offset %d: %v", offset-int64(len(line)), err)
+		}
+		if c.Seq > last {
+			last, end = c.Seq, offset
+		}
+		if c.Seq <= after {
+			continue
+		}
+		if err := fn(&c); err != nil {
+			return err
+		}
+	}
+
+	l.mu.Lock()
+	if last > l.last {
+		l.last, l.end = last, end
+	}
+	l.mu.Unlock()
+	return nil
+}
+
+func (l *fileEventLog) SaveSnapshot(_ context.Context, s *Snapshot) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	// Write and rename, so that a snapshot file is either whole or absent.
+	path := filepath.Join(l.dir, "snapshots", fmt.Sprintf("%020d.json", s.Seq))
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (l *fileEventLog) LatestSnapshot(_ context.Context, at time.Time) (*Snapshot, error) {
+	entries, err := os.ReadDir(filepath.Join(l.dir, "snapshots"))
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".json") {
+			names = append(names, e.Name())
+		}
+	}
+	// The names are zero-padded commit numbers, so this is newest first.
+	slices.Sort(names)
+	slices.Reverse(names)
+
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(l.dir, "snapshots", name))
+		if err != nil {
+			return nil, err
+		}
+		var s Snapshot
+		if err := json.Unmarshal(data, &s); err != nil {
+			return nil, fmt.Errorf("corrupt snapshot %s: %v", name, err)
+		}
+		if at.IsZero() || !s.At.After(at) {
+			return &s, nil
+		}
+	}
+	return nil, nil
+}
+
+func (l *fileEventLog) claimRelay(_ context.Context, now time.Time, lease time.Duration) (int64, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Before(l.relayLockedUntil) {
+		return 0, false, nil
+	}
+	pos, err := l.readRelay()
+	if err != nil {
+		return 0, false, err
+	}
+	l.relayLockedUntil = now.Add(lease)
+	return pos.Seq, true, nil
+}
+
+func (l *fileEventLog) saveRelay(_ context.Context, seq int64, release bool) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	pos, err := l.readRelay()
+	if err != nil {
+		return err
+	}
+	if seq > pos.Seq {
+		data, err := json.Marshal(relayPosition{Seq: seq})
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(l.dir, "relay.json")
+		if err := os.WriteFile(path+".tmp", data, 0o644); err != nil {
+			return err
+		}
+		if err := os.Rename(path+".tmp", path); err != nil {
+			return err
+		}
+	}
+	if release {
+		l.relayLockedUntil = time.Time{}
+	}
+	return nil
+}
+
+// readRelay returns the stored relay position, which is zero before the
+// relay first saves one. l.mu must be held.
+func (l *fileEventLog) readRelay() (relayPosition, error) {
+	var pos relayPosition
+	data, err := os.ReadFile(filepath.Join(l.dir, "relay.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return pos, nil
+	}
+	if err != nil {
+		return pos, err
+	}
+	if err := json.Unmarshal(data, &pos); err != nil {
+		return pos, fmt.Errorf("corrupt relay position: %v", err)
+	}
+	return pos, nil
+}
@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"grpc-todo/domain"
+)
+
+// relayLog is an event log that keeps how far an outbox relay has
+// published it.
+type relayLog interface {
+	EventLog
+	// claimRelay leases the log to one relay for lease and returns the
+	// last commit published. ok is false while another relay holds it.
+	claimRelay(ctx context.Context, now time.Time, lease time.Duration) (seq int64, ok bool, err error)
+	// saveRelay records that the commits up to seq are published, and ends
+	// the lease when release is set. The position never moves back.
+	saveRelay(ctx context.Context, seq int64, release bool) error
+}
+
+type eventOutboxRepository struct {
+	log relayLog
+
+	mu sync.Mutex
+	// state is the tasks as of the last commit claimed, or nil until it
+	// is rebuilt.
+	state *projection
+	// from is the position the last claim started after, and claimed the
+	// messages it returned.
+	from    int64
+	claimed []claimedMessage
+}
+
+type claimedMessage struct {
+	id  string
+	seq int64
+}
+
+// NewEventOutboxRepository returns the outbox of an event-sourced store: a
+// message for every event in log, in order, carrying the task as the event
+// left it, or as it was before a task.purged. Published commits are
+// recorded as a position in the log rather than removed.
+func NewEventOutboxRepository(log EventLog) (OutboxRepository, error) {
+	l, ok := log.(relayLog)
+	if !ok {
+		return nil, errors.New("event log cannot keep an outbox position")
+	}
+	return &eventOutboxRepository{log: l}, nil
+}
+
+// ClaimOutbox returns the events of whole commits after the stored
+// position, until limit is reached. The log is leased as a whole, so while
+// one relay holds it others get nothing.
+func (r *eventOutboxRepository) ClaimOutbox(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.OutboxMessage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	seq, ok, err := r.log.claimRelay(ctx, now, lease)
+	if err != nil {
+		logDBError(ctx, "failed to claim task events", err)
+		return nil, fmt.Errorf("failed to claim task events: %v", err)
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	// Another process may have moved the position, or a partial ack left
+	// state ahead of it.
+	if r.state == nil || r.state.seq != seq {
+		if r.state, err = projectionAt(ctx, r.log, seq); err != nil {
+			logDBError(ctx, "failed to read task events", err)
+			return nil, fmt.Errorf("failed to read task events: %v", err)
+		}
+	}
+
+	r.from, r.claimed = seq, nil
+	var messages []*domain.OutboxMessage
+	err = r.log.Read(ctx, seq, func(c *Commit) error {
+		if len(messages) >= limit {
+			return errStopReplay
+		}
+		for i := range c.Events {
+			msg, err := r.next(c, i)
+			if err != nil {
+				return err
+			}
+			if msg != nil {
+				messages = append(messages, msg)
+				r.claimed = append(r.claimed, claimedMessage{id: msg.Id, seq: c.Seq})
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStopReplay) {
+		r.state, r.claimed = nil, nil
+		logDBError(ctx, "failed to read task events", err)
+		return nil, fmt.Errorf("failed to read task events: %v", err)
+	}
+
+	if len(messages) == 0 {
+		// Commits whose events all were skipped count as published.
+		if err := r.log.saveRelay(ctx, r.state.seq, true); err != nil {
+			logDBError(ctx, "failed to save outbox position", err)
+			return nil, fmt.Errorf("failed to save outbox position: %v", err)
+		}
+	}
+	return messages, nil
+}
+
+// next applies event i of c to r.state and returns its message, or nil when
+// the event changed no task.
+func (r *eventOutboxRepository) next(c *Commit, i int) (*domain.OutboxMessage, error) {
+	e := c.Events[i]
+	var task *domain.Task
+	if t := r.state.tasks[e.TaskID]; t != nil {
+		task = t.toDomain()
+	}
+	r.state.apply(&Commit{Seq: c.Seq, At: c.At, Events: []TaskEvent{e}})
+	if t := r.state.tasks[e.TaskID]; t != nil {
+		task = t.toDomain()
+	}
+	if task == nil {
+		return nil, nil
+	}
+
+	payload, err := encodeOutboxTask(task)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.OutboxMessage{
+		Id:        fmt.Sprintf("%d-%d", c.Seq, i),
+		Event:     e.Type,
+		TaskId:    e.TaskID,
+		Owner:     task.Owner,
+		Payload:   payload,
+		CreatedAt: c.At,
+	}, nil
+}
+
+// AckOutbox moves the position past the claimed commits whose messages are
+// all published, stopping at the first that is not, and ends the lease
+// once every claimed message is.
+func (r *eventOutboxRepository) AckOutbox(ctx context.Context, ids []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.claimed) == 0 {
+		return nil
+	}
+	acked := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		acked[id] = true
+	}
+
+	pos, all := r.from, true
+	for i, m := range r.claimed {
+		if !acked[m.id] {
+			all = false
+			break
+		}
+		if i+1 == len(r.claimed) || r.claimed[i+1].seq != m.seq {
+			pos = m.seq
+		}
+	}
+	if all {
+		pos = r.state.seq
+	} else {
+		// The unpublished commits are read again on the next claim.
+		r.state = nil
+	}
+	r.claimed = nil
+
+	if err := r.log.saveRelay(ctx, pos, all); err != nil {
+		logDBError(ctx, "failed to save outbox position", err)
+		return fmt.Errorf("failed to save outbox position: %v", err)
+	}
+	return nil
+}
+
+// projectionAt returns the tasks as of commit seq, replayed from the latest
+// snapshot at or before it.
+func projectionAt(ctx context.Context, log EventLog, seq int64) (*projection, error) {
+	if seq == 0 {
+		return newProjection(nil), nil
+	}
+
+	var at time.Time
+	err := log.Read(ctx, seq-1, func(c *Commit) error {
+		at = c.At
+		return errStopReplay
+	})
+	if err != nil && !errors.Is(err, errStopReplay) {
+		return nil, err
+	}
+	s, err := log.LatestSnapshot(ctx, at)
+	if err != nil {
+		return nil, err
+	}
+	// Commits can share a time, so the snapshot may still be of a later one.
+	if s != nil && s.Seq > seq {
+		s = nil
+	}
+
+	p := newProjection(s)
+	err = log.Read(ctx, p.seq, func(c *Commit) error {
+		if c.Seq > seq {
+			return errStopReplay
+		}
+		p.apply(c)
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStopReplay) {
+		return nil, err
+	}
+	return p, nil
+}
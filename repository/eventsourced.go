@@ -0,0 +1,777 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"grpc-todo/domain"
+	"grpc-todo/filter"
+	"grpc-todo/search"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const defaultSnapshotEvery = 1000
+
+// EventSourcedRepository is a Repository that stores nothing but an
+// append-only log of task events. Tasks are served from a projection of the
+// log kept in memory, rebuilt on start from the latest snapshot and the
+// commits after it, and brought up to date with the log before every call,
+// so several processes can share a Mongo log.
+type EventSourcedRepository interface {
+	Repository
+	// TasksAsOf returns the live tasks as they were at the given time.
+	TasksAsOf(ctx context.Context, at time.Time) ([]*domain.Task, error)
+}
+
+type eventSourcedRepository struct {
+	log           EventLog
+	snapshotEvery int64
+	now           func() time.Time
+
+	mu    sync.Mutex
+	state *projection
+	// snapshotSeq is the last commit covered by a snapshot.
+	snapshotSeq int64
+
+	// quotas, when set, holds reservations where other processes see them.
+	// Otherwise reserved counts the task slots each owner's ReserveTasks
+	// calls hold in this process.
+	quotas     *taskQuotas
+	reservedMu sync.Mutex
+	reserved   map[string]int64
+}
+
+type EventSourcedOption func(*eventSourcedRepository)
+
+// WithSnapshotEvery makes the repository snapshot its tasks every n
+// commits (1000 by default).
+func WithSnapshotEvery(n int) EventSourcedOption {
+	return func(r *eventSourcedRepository) {
+		if n > 0 {
+			r.snapshotEvery = int64(n)
+		}
+	}
+}
+
+// WithSharedQuota keeps task reservations in db's task_quotas collection, as
+// the Mongo store does. Processes that share a Mongo log need it to keep
+// together within a task quota.
+func WithSharedQuota(db *mongo.Database) EventSourcedOption {
+	return func(r *eventSourcedRepository) {
+		r.quotas = newTaskQuotas(db)
+	}
+}
+
+func NewEventSourcedRepository(ctx context.Context, log EventLog, opts ...EventSourcedOption) (EventSourcedRepository, error) {
+	r := &eventSourcedRepository{
+		log:           log,
+		snapshotEvery: defaultSnapshotEvery,
+		now:           time.Now,
+		reserved:      make(map[string]int64),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	s, err := log.LatestSnapshot(ctx, time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %v", err)
+	}
+	r.state = newProjection(s)
+	r.snapshotSeq = r.state.seq
+	if err := r.catchUp(ctx); err != nil {
+		return nil, fmt.Errorf("failed to replay task events: %v", err)
+	}
+	return r, nil
+}
+
+// catchUp applies the commits other writers have appended since the
+// projection was last brought up to date. r.mu must be held.
+func (r *eventSourcedRepository) catchUp(ctx context.Context) error {
+	err := r.log.Read(ctx, r.state.seq, func(c *Commit) error {
+		if c.Seq != r.state.seq+1 {
+			return fmt.Errorf("commit %d follows commit %d", c.Seq, r.state.seq)
+		}
+		r.state.apply(c)
+		return nil
+	})
+	r.maybeSnapshot(ctx)
+	return err
+}
+
+// maybeSnapshot saves a snapshot when enough commits have been applied
+// since the last one. A failed snapshot is only logged, since the log alone
+// is enough to rebuild the tasks. r.mu must be held.
+func (r *eventSourcedRepository) maybeSnapshot(ctx context.Context) {
+	if r.state.seq-r.snapshotSeq < r.snapshotEvery {
+		return
+	}
+	s := &Snapshot{Seq: r.state.seq, At: r.state.at, Tasks: r.state.snapshot()}
+	if err := r.log.SaveSnapshot(ctx, s); err != nil {
+		logDBError(ctx, "failed to save snapshot", err, slog.Int64("seq", s.Seq))
+		return
+	}
+	r.snapshotSeq = s.Seq
+}
+
+// view calls fn with the tasks brought up to date with the log.
+func (r *eventSourcedRepository) view(ctx context.Context, fn func(p *projection)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.catchUp(ctx); err != nil {
+		logDBError(ctx, "failed to read task events", err)
+		return fmt.Errorf("failed to read task events: %v", err)
+	}
+	fn(r.state)
+	return nil
+}
+
+// commit appends the events decide returns for the current tasks and then
+// calls done, if set, with the tasks they leave. When another writer
+// appended first, it catches up and decides again, so decide must not keep
+// state between calls. Errors from decide are returned as they are.
+func (r *eventSourcedRepository) commit(ctx context.Context, decide func(p *projection) ([]TaskEvent, error), done func(p *projection)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for {
+		if err := r.catchUp(ctx); err != nil {
+			logDBError(ctx, "failed to read task events", err)
+			return fmt.Errorf("failed to read task events: %v", err)
+		}
+
+		events, err := decide(r.state)
+		if err != nil {
+			return err
+		}
+		if len(events) > 0 {
+			// Commit times never go backwards, so that TasksAsOf can stop
+			// at the first commit after the time it is asked about.
+			at := r.now().UTC().Truncate(time.Millisecond)
+			if at.Before(r.state.at) {
+				at = r.state.at
+			}
+			c := &Commit{Seq: r.state.seq + 1, At: at, Events: events}
+			err = r.log.Append(ctx, c)
+			if errors.Is(err, ErrConflict) {
+				continue
+			}
+			if err != nil {
+				logDBError(ctx, "failed to append task events", err, slog.Int64("seq", c.Seq))
+				return fmt.Errorf("failed to append task events: %v", err)
+			}
+			r.state.apply(c)
+			r.maybeSnapshot(ctx)
+		}
+
+		if done != nil {
+			done(r.state)
+		}
+		return nil
+	}
+}
+
+var errStopReplay = errors.New("stop replay")
+
+func (r *eventSourcedRepository) TasksAsOf(ctx context.Context, at time.Time) ([]*domain.Task, error) {
+	s, err := r.log.LatestSnapshot(ctx, at)
+	if err != nil {
+		logDBError(ctx, "failed to read snapshot", err)
+		return nil, fmt.Errorf("failed to read snapshot: %v", err)
+	}
+
+	p := newProjection(s)
+	err = r.log.Read(ctx, p.seq, func(c *Commit) error {
+		if c.At.After(at) {
+			return errStopReplay
+		}
+		p.apply(c)
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStopReplay) {
+		logDBError(ctx, "failed to read task events", err)
+		return nil, fmt.Errorf("failed to read task events: %v", err)
+	}
+	return toDomainTasks(p.find((*TaskState).live)), nil
+}
+
+func checkID(id string) error {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidID, err)
+	}
+	return nil
+}
+
+// liveTask returns the task with the given ID unless it is missing,
+// archived or in the trash.
+func (p *projection) liveTask(id string) (*TaskState, error) {
+	if err := checkID(id); err != nil {
+		return nil, err
+	}
+	t := p.tasks[id]
+	if t == nil || !t.live() {
+		return nil, fmt.Errorf("task with ID %s: %w", id, ErrNotFound)
+	}
+	return t, nil
+}
+
+func (r *eventSourcedRepository) CreateTask(ctx context.Context, task *domain.Task) (*domain.Task, error) {
+	var id string
+	decide := func(p *projection) ([]TaskEvent, error) {
+		id = primitive.NewObjectID().Hex()
+		state := newTaskState(task)
+		state.ID = id
+		return []TaskEvent{{Type: domain.EventTaskCreated, TaskID: id, Task: state}}, nil
+	}
+	if err := r.commit(ctx, decide, nil); err != nil {
+		return nil, err
+	}
+
+	task.Id = id
+	return task, nil
+}
+
+func (r *eventSourcedRepository) GetAllTasks(ctx context.Context) ([]*domain.Task, error) {
+	var tasks []*domain.Task
+	err := r.view(ctx, func(p *projection) {
+		tasks = toDomainTasks(p.find((*TaskState).live))
+	})
+	return tasks, err
+}
+
+func (r *eventSourcedRepository) FilterTasks(ctx context.Context, expr filter.Expr, order []filter.OrderKey) ([]*domain.Task, error) {
+	var tasks []*domain.Task
+	err := r.view(ctx, func(p *projection) {
+		for _, t := range p.find((*TaskState).live) {
+			if task := t.toDomain(); filter.Match(expr, filter.TaskValue(task)) {
+				tasks = append(tasks, task)
+			}
+		}
+	})
+	// find sorts by ID, and the sort is stable, so ties stay in ID order.
+	slices.SortStableFunc(tasks, func(a, b *domain.Task) int {
+		return filter.CompareOrder(order, filter.TaskValue(a), filter.TaskValue(b))
+	})
+	return tasks, err
+}
+
+func (r *eventSourcedRepository) GetTask(ctx context.Context, id string) (*domain.Task, error) {
+	var task *domain.Task
+	var lookupErr error
+	err := r.view(ctx, func(p *projection) {
+		var t *TaskState
+		if t, lookupErr = p.liveTask(id); lookupErr == nil {
+			task = t.toDomain()
+		}
+	})
+	return task, cmp.Or(err, lookupErr)
+}
+
+func (r *eventSourcedRepository) UpdateTask(ctx context.Context, id string, title string, description string, dueAt *int64) (*domain.Task, error) {
+	var task *domain.Task
+	decide := func(p *projection) ([]TaskEvent, error) {
+		if _, err := p.liveTask(id); err != nil {
+			return nil, err
+		}
+		return []TaskEvent{{Type: domain.EventTaskUpdated, TaskID: id, Title: title, Description: description, DueAt: dueAt}}, nil
+	}
+	done := func(p *projection) {
+		task = p.tasks[id].toDomain()
+	}
+	if err := r.commit(ctx, decide, done); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func (r *eventSourcedRepository) UpdateTaskStatus(ctx context.Context, id string, status string, changedBy string) error {
+	return r.commit(ctx, func(p *projection) ([]TaskEvent, error) {
+		if _, err := p.liveTask(id); err != nil {
+			return nil, err
+		}
+		return []TaskEvent{{Type: domain.EventTaskStatusUpdated, TaskID: id, Status: status, Actor: changedBy}}, nil
+	}, nil)
+}
+
+func (r *eventSourcedRepository) GetTaskHistory(ctx context.Context, id string) ([]*domain.StatusTransition, error) {
+	if err := checkID(id); err != nil {
+		return nil, err
+	}
+
+	var transitions []*domain.StatusTransition
+	err := r.view(ctx, func(p *projection) {
+		t := p.tasks[id]
+		if t == nil {
+			return
+		}
+		for _, h := range t.History {
+			transitions = append(transitions, &domain.StatusTransition{
+				TaskId:    id,
+				From:      h.From,
+				To:        h.To,
+				ChangedBy: h.ChangedBy,
+				ChangedAt: h.ChangedAt,
+			})
+		}
+	})
+	return transitions, err
+}
+
+func (r *eventSourcedRepository) DeleteTask(ctx context.Context, id string, deletedBy string) error {
+	return r.commit(ctx, func(p *projection) ([]TaskEvent, error) {
+		if _, err := p.liveTask(id); err != nil {
+			return nil, err
+		}
+		return []TaskEvent{{Type: domain.EventTaskDeleted, TaskID: id, Actor: deletedBy}}, nil
+	}, nil)
+}
+
+func (r *eventSourcedRepository) ListTrash(ctx context.Context, pageSize int, pageToken string) ([]*domain.Task, string, error) {
+	return r.listPage(ctx, (*TaskState).trashed, pageSize, pageToken)
+}
+
+func (r *eventSourcedRepository) ListArchivedTasks(ctx context.Context, pageSize int, pageToken string) ([]*domain.Task, string, error) {
+	return r.listPage(ctx, (*TaskState).archived, pageSize, pageToken)
+}
+
+// listPage pages through the tasks that match keep like listPage does
+// through a collection.
+func (r *eventSourcedRepository) listPage(ctx context.Context, keep func(*TaskState) bool, pageSize int, pageToken string) ([]*domain.Task, string, error) {
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	var after string
+	if pageToken != "" {
+		id, err := decodePageToken(pageToken)
+		if err != nil {
+			return nil, "", err
+		}
+		after = id.Hex()
+	}
+
+	var page []*TaskState
+	err := r.view(ctx, func(p *projection) {
+		page = cloneTasks(p.find(func(t *TaskState) bool { return keep(t) && t.ID > after }))
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if len(page) > pageSize {
+		page = page[:pageSize]
+		id, _ := primitive.ObjectIDFromHex(page[pageSize-1].ID)
+		next = encodePageToken(id)
+	}
+	return toDomainTasks(page), next, nil
+}
+
+func (r *eventSourcedRepository) GetDeletedTask(ctx context.Context, id string) (*domain.Task, error) {
+	return r.findTask(ctx, id, (*TaskState).trashed, "deleted task")
+}
+
+func (r *eventSourcedRepository) GetArchivedTask(ctx context.Context, id string) (*domain.Task, error) {
+	return r.findTask(ctx, id, (*TaskState).archived, "archived task")
+}
+
+// findTask returns the task with the given ID if it is in the state in
+// tells, naming it what in the error for a missing one.
+func (r *eventSourcedRepository) findTask(ctx context.Context, id string, in func(*TaskState) bool, what string) (*domain.Task, error) {
+	if err := checkID(id); err != nil {
+		return nil, err
+	}
+
+	var task *domain.Task
+	err := r.view(ctx, func(p *projection) {
+		if t := p.tasks[id]; t != nil && in(t) {
+			task = t.toDomain()
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, fmt.Errorf("%s with ID %s: %w", what, id, ErrNotFound)
+	}
+	return task, nil
+}
+
+func (r *eventSourcedRepository) UndeleteTask(ctx context.Context, id string) (*domain.Task, error) {
+	return r.moveTask(ctx, id, (*TaskState).trashed, "deleted task", domain.EventTaskUndeleted)
+}
+
+func (r *eventSourcedRepository) ArchiveTask(ctx context.Context, id string) (*domain.Task, error) {
+	return r.moveTask(ctx, id, (*TaskState).live, "task", domain.EventTaskArchived)
+}
+
+func (r *eventSourcedRepository) RestoreTask(ctx context.Context, id string) (*domain.Task, error) {
+	return r.moveTask(ctx, id, (*TaskState).archived, "archived task", domain.EventTaskRestored)
+}
+
+// moveTask applies event to the task with the given ID, which must be in
+// the state from tells, and returns the task it leaves. what names the
+// task in the error for a missing one.
+func (r *eventSourcedRepository) moveTask(ctx context.Context, id string, from func(*TaskState) bool, what string, event string) (*domain.Task, error) {
+	if err := checkID(id); err != nil {
+		return nil, err
+	}
+
+	var task *domain.Task
+	decide := func(p *projection) ([]TaskEvent, error) {
+		if t := p.tasks[id]; t == nil || !from(t) {
+			return nil, fmt.Errorf("%s with ID %s: %w", what, id, ErrNotFound)
+		}
+		return []TaskEvent{{Type: event, TaskID: id}}, nil
+	}
+	done := func(p *projection) {
+		task = p.tasks[id].toDomain()
+	}
+	if err := r.commit(ctx, decide, done); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func (r *eventSourcedRepository) PurgeTask(ctx context.Context, id string) (*domain.Task, error) {
+	if err := checkID(id); err != nil {
+		return nil, err
+	}
+
+	var task *domain.Task
+	decide := func(p *projection) ([]TaskEvent, error) {
+		t := p.tasks[id]
+		if t == nil || !t.trashed() {
+			return nil, fmt.Errorf("deleted task with ID %s: %w", id, ErrNotFound)
+		}
+		task = t.toDomain()
+		return []TaskEvent{{Type: domain.EventTaskPurged, TaskID: id}}, nil
+	}
+	if err := r.commit(ctx, decide, nil); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func (f AgeFilter) matches(t *TaskState) bool {
+	if f.Trashed {
+		return t.trashed() && t.DeletedAt <= f.Before
+	}
+	return t.live() && t.Status == f.Status && cmp.Or(t.StatusChangedAt, t.CreatedAt) <= f.Before
+}
+
+// byAge returns the tasks that match f, oldest first.
+func (p *projection) byAge(f AgeFilter) []*TaskState {
+	tasks := p.find(f.matches)
+	age := func(t *TaskState) int64 {
+		if f.Trashed {
+			return t.DeletedAt
+		}
+		return cmp.Or(t.StatusChangedAt, t.CreatedAt)
+	}
+	slices.SortStableFunc(tasks, func(a, b *TaskState) int { return cmp.Compare(age(a), age(b)) })
+	if f.Limit > 0 && len(tasks) > f.Limit {
+		tasks = tasks[:f.Limit]
+	}
+	return tasks
+}
+
+func (r *eventSourcedRepository) FindTasksByAge(ctx context.Context, f AgeFilter) ([]*domain.Task, error) {
+	var tasks []*domain.Task
+	err := r.view(ctx, func(p *projection) {
+		tasks = toDomainTasks(p.byAge(f))
+	})
+	return tasks, err
+}
+
+func (r *eventSourcedRepository) DeleteTasksByAge(ctx context.Context, f AgeFilter) ([]*domain.Task, error) {
+	var removed []*domain.Task
+	decide := func(p *projection) ([]TaskEvent, error) {
+		tasks := p.byAge(f)
+		removed = toDomainTasks(tasks)
+		events := make([]TaskEvent, len(tasks))
+		for i, t := range tasks {
+			events[i] = TaskEvent{Type: domain.EventTaskPurged, TaskID: t.ID}
+		}
+		return events, nil
+	}
+	if err := r.commit(ctx, decide, nil); err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
+func (r *eventSourcedRepository) ArchiveTasksByAge(ctx context.Context, f AgeFilter) ([]*domain.Task, error) {
+	var ids []string
+	decide := func(p *projection) ([]TaskEvent, error) {
+		tasks := p.byAge(f)
+		ids = make([]string, len(tasks))
+		events := make([]TaskEvent, len(tasks))
+		for i, t := range tasks {
+			ids[i] = t.ID
+			events[i] = TaskEvent{Type: domain.EventTaskArchived, TaskID: t.ID}
+		}
+		return events, nil
+	}
+
+	var archived []*domain.Task
+	done := func(p *projection) {
+		for _, id := range ids {
+			archived = append(archived, p.tasks[id].toDomain())
+		}
+	}
+	if err := r.commit(ctx, decide, done); err != nil {
+		return nil, err
+	}
+	return archived, nil
+}
+
+func (r *eventSourcedRepository) GetTasksByOwner(ctx context.Context, owner string) ([]*domain.Task, error) {
+	var tasks []*domain.Task
+	err := r.view(ctx, func(p *projection) {
+		tasks = toDomainTasks(p.find(func(t *TaskState) bool { return t.live() && t.Owner == owner }))
+	})
+	return tasks, err
+}
+
+func (r *eventSourcedRepository) CountTasks(ctx context.Context, owner string) (int64, error) {
+	var n int64
+	err := r.view(ctx, func(p *projection) {
+		for _, t := range p.tasks {
+			if t.live() && t.Owner == owner {
+				n++
+			}
+		}
+	})
+	return n, err
+}
+
+// ReserveTasks holds slots in the shared task_quotas collection when the
+// repository was created WithSharedQuota, and in memory otherwise.
+func (r *eventSourcedRepository) ReserveTasks(ctx context.Context, owner string, n int) (int64, func(), error) {
+	if r.quotas != nil {
+		before, release, err := r.quotas.reserve(ctx, owner, n)
+		if err != nil {
+			return 0, nil, err
+		}
+		count, err := r.CountTasks(ctx, owner)
+		if err != nil {
+			release()
+			return 0, nil, err
+		}
+		return count + before, release, nil
+	}
+
+	r.reservedMu.Lock()
+	r.reserved[owner] += int64(n)
+	before := r.reserved[owner] - int64(n)
+	r.reservedMu.Unlock()
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			r.reservedMu.Lock()
+			defer r.reservedMu.Unlock()
+			if r.reserved[owner] -= int64(n); r.reserved[owner] == 0 {
+				delete(r.reserved, owner)
+			}
+		})
+	}
+
+	count, err := r.CountTasks(ctx, owner)
+	if err != nil {
+		release()
+		return 0, nil, err
+	}
+	return count + before, release, nil
+}
+
+// SearchTasks ranks tasks with the projection's inverted index, which
+// matches whole words without the stemming and stop words of a Mongo text
+// index.
+func (r *eventSourcedRepository) SearchTasks(ctx context.Context, q SearchQuery) ([]*SearchHit, error) {
+	limit := cmp.Or(q.Limit, defaultSearchLimit)
+	var hits []*SearchHit
+	err := r.view(ctx, func(p *projection) {
+		for _, h := range p.index.Search(q.Text) {
+			t := p.tasks[h.ID]
+			if !t.live() || len(q.Statuses) > 0 && !slices.Contains(q.Statuses, t.Status) {
+				continue
+			}
+			if !slices.ContainsFunc(q.Tags, func(tag string) bool {
+				return !search.HasTag(t.Title, tag) && !search.HasTag(t.Description, tag)
+			}) {
+				hits = append(hits, &SearchHit{Task: t.toDomain(), Score: h.Score})
+			}
+			if len(hits) == limit {
+				return
+			}
+		}
+	})
+	return hits, err
+}
+
+func (r *eventSourcedRepository) GetTaskStats(ctx context.Context, q domain.StatsQuery) (*domain.TaskStats, error) {
+	q.OldestOpen = cmp.Or(q.OldestOpen, defaultOldestOpen)
+	var stats *domain.TaskStats
+	err := r.view(ctx, func(p *projection) {
+		stats = domain.ComputeStats(toDomainTasks(p.find((*TaskState).live)), q)
+	})
+	return stats, err
+}
+
+func (r *eventSourcedRepository) FindTaskByICalUID(ctx context.Context, owner string, uid string) (*domain.Task, error) {
+	var task *domain.Task
+	err := r.view(ctx, func(p *projection) {
+		matches := p.find(func(t *TaskState) bool {
+			return t.live() && t.Owner == owner && t.ICalUID == uid
+		})
+		if len(matches) > 0 {
+			task = matches[0].toDomain()
+		}
+	})
+	if err == nil && task == nil {
+		err = fmt.Errorf("task with UID %s: %w", uid, ErrNotFound)
+	}
+	return task, err
+}
+
+// ForEachTask calls fn on a copy of the tasks, so that fn may use the
+// repository.
+func (r *eventSourcedRepository) ForEachTask(ctx context.Context, fn func(*domain.Task) error) error {
+	tasks, err := r.GetAllTasks(ctx)
+	if err != nil {
+		return err
+	}
+	for _, t := range tasks {
+		if err := fn(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decideBatch builds the events of a batch, calling check for every item
+// that has no error in initial. check returns the item's event or its
+// error. An all-or-nothing batch with a failure has no events.
+func decideBatch(initial []error, allOrNothing bool, check func(i int) (TaskEvent, error)) ([]TaskEvent, []error) {
+	errs := slices.Clone(initial)
+	var events []TaskEvent
+	for i := range errs {
+		if errs[i] != nil {
+			continue
+		}
+		e, err := check(i)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		events = append(events, e)
+	}
+
+	if allOrNothing && hasFailures(errs) {
+		for i := range errs {
+			if errs[i] == nil {
+				errs[i] = ErrBatchAborted
+			}
+		}
+		return nil, errs
+	}
+	return events, errs
+}
+
+func (r *eventSourcedRepository) BatchCreateTasks(ctx context.Context, tasks []*domain.Task, allOrNothing bool) ([]error, error) {
+	initial := make([]error, len(tasks))
+	for i, task := range tasks {
+		if task.Id != "" {
+			initial[i] = checkID(task.Id)
+		}
+	}
+
+	var ids []string
+	var errs []error
+	decide := func(p *projection) ([]TaskEvent, error) {
+		ids = make([]string, len(tasks))
+		seen := make(map[string]bool)
+		var events []TaskEvent
+		events, errs = decideBatch(initial, allOrNothing, func(i int) (TaskEvent, error) {
+			id := cmp.Or(tasks[i].Id, primitive.NewObjectID().Hex())
+			// Archived tasks keep their IDs, so they are taken too.
+			if p.tasks[id] != nil || seen[id] {
+				return TaskEvent{}, fmt.Errorf("failed to insert task: %w", ErrAlreadyExists)
+			}
+			seen[id] = true
+			ids[i] = id
+			state := newTaskState(tasks[i])
+			state.ID = id
+			return TaskEvent{Type: domain.EventTaskCreated, TaskID: id, Task: state}, nil
+		})
+		return events, nil
+	}
+	if err := r.commit(ctx, decide, nil); err != nil {
+		return nil, err
+	}
+
+	for i, task := range tasks {
+		if errs[i] == nil {
+			task.Id = ids[i]
+		}
+	}
+	return errs, nil
+}
+
+func (r *eventSourcedRepository) BatchUpdateTaskStatus(ctx context.Context, updates []StatusUpdate, changedBy string, allOrNothing bool) ([]error, error) {
+	initial := make([]error, len(updates))
+	for i, u := range updates {
+		initial[i] = checkID(u.ID)
+	}
+
+	var errs []error
+	decide := func(p *projection) ([]TaskEvent, error) {
+		var events []TaskEvent
+		events, errs = decideBatch(initial, allOrNothing, func(i int) (TaskEvent, error) {
+			if _, err := p.liveTask(updates[i].ID); err != nil {
+				return TaskEvent{}, err
+			}
+			return TaskEvent{Type: domain.EventTaskStatusUpdated, TaskID: updates[i].ID, Status: updates[i].Status, Actor: changedBy}, nil
+		})
+		return events, nil
+	}
+	if err := r.commit(ctx, decide, nil); err != nil {
+		return nil, err
+	}
+	return errs, nil
+}
+
+func (r *eventSourcedRepository) BatchDeleteTasks(ctx context.Context, ids []string, deletedBy string, allOrNothing bool) ([]error, error) {
+	initial := make([]error, len(ids))
+	for i, id := range ids {
+		initial[i] = checkID(id)
+	}
+
+	var errs []error
+	decide := func(p *projection) ([]TaskEvent, error) {
+		deleted := make(map[string]bool)
+		var events []TaskEvent
+		events, errs = decideBatch(initial, allOrNothing, func(i int) (TaskEvent, error) {
+			if _, err := p.liveTask(ids[i]); err != nil || deleted[ids[i]] {
+				return TaskEvent{}, cmp.Or(err, fmt.Errorf("task with ID %s: %w", ids[i], ErrNotFound))
+			}
+			deleted[ids[i]] = true
+			return TaskEvent{Type: domain.EventTaskDeleted, TaskID: ids[i], Actor: deletedBy}, nil
+		})
+		return events, nil
+	}
+	if err := r.commit(ctx, decide, nil); err != nil {
+		return nil, err
+	}
+	return errs, nil
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"grpc-todo/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const historyCollection = "task_history"
+
+type mongoStatusTransition struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	TaskID    primitive.ObjectID `bson:"task_id"`
+	From      string             `bson:"from"`
+	To        string             `bson:"to"`
+	ChangedBy string             `bson:"changed_by"`
+	ChangedAt int64              `bson:"changed_at"`
+}
+
+func (mt mongoStatusTransition) toDomain() *domain.StatusTransition {
+	return &domain.StatusTransition{
+		TaskId:    mt.TaskID.Hex(),
+		From:      mt.From,
+		To:        mt.To,
+		ChangedBy: mt.ChangedBy,
+		ChangedAt: mt.ChangedAt,
+	}
+}
+
+// applyStatus changes mt the way statusUpdate changes the stored task.
+func applyStatus(mt *mongoTask, status string, now int64) {
+	if mt.Status != status {
+		mt.StatusChangedAt = now
+		mt.CompletedAt = 0
+		if status == "DONE" {
+			mt.CompletedAt = now
+		}
+	}
+	mt.Status = status
+}
+
+// forgetHistory removes the task_history entries of tasks deleted for good;
+// the rest of their history went with the task.
+func (r *mongoRepository) forgetHistory(ctx context.Context, ids ...primitive.ObjectID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := r.history.DeleteMany(ctx, bson.M{"task_id": bson.M{"$in": ids}})
+	return err
+}
+
+// GetTaskHistory returns the transitions kept in task_history before they
+// were stored with the task, followed by those stored with it, whether it
+// is live, in the trash or archived.
+func (r *mongoRepository) GetTaskHistory(ctx context.Context, id string) ([]*domain.StatusTransition, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidID, err)
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "changed_at", Value: 1}, {Key: "_id", Value: 1}})
+
+	var docs []mongoStatusTransition
+	cursor, err := r.history.Find(ctx, bson.M{"task_id": objectID}, opts)
+	if err == nil {
+		err = cursor.All(ctx, &docs)
+	}
+	var mt mongoTask
+	if err == nil {
+		mt, err = r.findAnywhere(ctx, objectID, options.FindOne().SetProjection(bson.M{"history": 1}))
+	}
+	if err != nil {
+		logDBError(ctx, "failed to find task history", err, slog.String("task_id", id))
+		return nil, fmt.Errorf("failed to find task history: %v", err)
+	}
+
+	transitions := make([]*domain.StatusTransition, 0, len(docs)+len(mt.History))
+	for _, doc := range docs {
+		transitions = append(transitions, doc.toDomain())
+	}
+	for _, h := range mt.History {
+		transitions = append(transitions, &domain.StatusTransition{
+			TaskId:    id,
+			From:      h.From,
+			To:        h.To,
+			ChangedBy: h.ChangedBy,
+			ChangedAt: h.ChangedAt,
+		})
+	}
+	return transitions, nil
+}
+
+// findAnywhere returns the task with the given ID from the tasks or the
+// archive, or a zero task when it is in neither.
+func (r *mongoRepository) findAnywhere(ctx context.Context, id primitive.ObjectID, opts *options.FindOneOptions) (mongoTask, error) {
+	var mt mongoTask
+	for _, coll := range []*mongo.Collection{r.collection, r.archive} {
+		err := coll.FindOne(ctx, bson.M{"_id": id}, opts).Decode(&mt)
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return mt, err
+		}
+	}
+	return mongoTask{}, nil
+}
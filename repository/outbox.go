@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"grpc-todo/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const outboxCollection = "outbox"
+
+// Option configures the task repository.
+type Option func(*mongoRepository)
+
+// WithOutbox makes every task change also write an event to the outbox
+// collection, in the same transaction when the deployment supports them,
+// for an outbox relay to publish.
+func WithOutbox() Option {
+	return func(r *mongoRepository) {
+		r.outbox = r.collection.Database().Collection(outboxCollection)
+	}
+}
+
+// OutboxRepository reads the outbox written by a repository created
+// WithOutbox.
+type OutboxRepository interface {
+	// ClaimOutbox returns up to limit unpublished messages in the order they
+	// were written, skipping and then hiding for lease the ones another
+	// relay has claimed.
+	ClaimOutbox(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.OutboxMessage, error)
+	// AckOutbox removes published messages.
+	AckOutbox(ctx context.Context, ids []string) error
+}
+
+type mongoOutboxMessage struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	Event       string             `bson:"event"`
+	TaskID      string             `bson:"task_id"`
+	Owner       string             `bson:"owner"`
+	Payload     string             `bson:"payload"`
+	CreatedAt   time.Time          `bson:"created_at"`
+	LockedUntil time.Time          `bson:"locked_until"`
+}
+
+// taskPayload is the JSON form of a task in outbox messages.
+type taskPayload struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+	CreatedAt   int64  `json:"created_at"`
+	Owner       string `json:"owner"`
+	DueAt       int64  `json:"due_at,omitempty"`
+	CompletedAt int64  `json:"completed_at,omitempty"`
+	ArchivedAt  int64  `json:"archived_at,omitempty"`
+	DeletedAt   int64  `json:"deleted_at,omitempty"`
+	DeletedBy   string `json:"deleted_by,omitempty"`
+}
+
+// mutate runs fn in a transaction when its changes are recorded in the
+// outbox, so that the change and its event, and with it the webhook
+// deliveries the relay makes of it, are stored together. Standalone servers
+// have no transactions; there the event is written right after the change
+// and is lost if the server fails in between.
+func (r *mongoRepository) mutate(ctx context.Context, fn func(ctx context.Context) error) error {
+	if r.outbox != nil {
+		return r.atomically(ctx, fn)
+	}
+	return fn(ctx)
+}
+
+// record writes an outbox message for each task. It does nothing for
+// repositories without an outbox.
+func (r *mongoRepository) record(ctx context.Context, event string, tasks ...*domain.Task) error {
+	if r.outbox == nil || len(tasks) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	docs := make([]interface{}, 0, len(tasks))
+	for _, t := range tasks {
+		payload, err := encodeOutboxTask(t)
+		if err != nil {
+			return err
+		}
+		docs = append(docs, mongoOutboxMessage{
+			Event:     event,
+			TaskID:    t.Id,
+			Owner:     t.Owner,
+			Payload:   string(payload),
+			CreatedAt: now,
+		})
+	}
+
+	if _, err := r.outbox.InsertMany(ctx, docs); err != nil {
+		return fmt.Errorf("failed to write outbox: %v", err)
+	}
+	return nil
+}
+
+func encodeOutboxTask(t *domain.Task) ([]byte, error) {
+	return json.Marshal(taskPayload{
+		ID:          t.Id,
+		Title:       t.Title,
+		Description: t.Description,
+		Status:      t.Status,
+		CreatedAt:   t.CreatedAt,
+		Owner:       t.Owner,
+		DueAt:       t.DueAt,
+		CompletedAt: t.CompletedAt,
+		ArchivedAt:  t.ArchivedAt,
+		DeletedAt:   t.DeletedAt,
+		DeletedBy:   t.DeletedBy,
+	})
+}
+
+// DecodeOutboxTask returns the task carried by msg.
+func DecodeOutboxTask(msg *domain.OutboxMessage) (*domain.Task, error) {
+	var p taskPayload
+	if err := json.Unmarshal(msg.Payload, &p); err != nil {
+		return nil, fmt.Errorf("failed to decode outbox message %s: %v", msg.Id, err)
+	}
+	return &domain.Task{
+		Id:          p.ID,
+		Title:       p.Title,
+		Description: p.Description,
+		Status:      p.Status,
+		CreatedAt:   p.CreatedAt,
+		Owner:       p.Owner,
+		DueAt:       p.DueAt,
+		CompletedAt: p.CompletedAt,
+		ArchivedAt:  p.ArchivedAt,
+		DeletedAt:   p.DeletedAt,
+		DeletedBy:   p.DeletedBy,
+	}, nil
+}
+
+// findTasks returns the stored tasks with the given IDs, by ID.
+func (r *mongoRepository) findTasks(ctx context.Context, ids []primitive.ObjectID) (map[primitive.ObjectID]*domain.Task, error) {
+	var docs []mongoTask
+	cursor, err := r.collection.Find(ctx, live(bson.M{"_id": bson.M{"$in": ids}}))
+	if err == nil {
+		err = cursor.All(ctx, &docs)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make(map[primitive.ObjectID]*domain.Task, len(docs))
+	for _, mt := range docs {
+		tasks[mt.ID] = mt.toDomain()
+	}
+	return tasks, nil
+}
+
+type mongoOutboxRepository struct {
+	outbox *mongo.Collection
+}
+
+func NewOutboxRepository(db *mongo.Database) OutboxRepository {
+	return &mongoOutboxRepository{outbox: db.Collection(outboxCollection)}
+}
+
+func (r *mongoOutboxRepository) ClaimOutbox(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.OutboxMessage, error) {
+	filter := bson.M{"locked_until": bson.M{"$lte": now}}
+	update := bson.M{"$set": bson.M{"locked_until": now.Add(lease)}}
+	opts := options.FindOneAndUpdate().SetSort(bson.M{"_id": 1})
+
+	var messages []*domain.OutboxMessage
+	for len(messages) < limit {
+		var mm mongoOutboxMessage
+		err := r.outbox.FindOneAndUpdate(ctx, filter, update, opts).Decode(&mm)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		if err != nil {
+			logDBError(ctx, "failed to claim outbox message", err)
+			return messages, fmt.Errorf("failed to claim outbox message: %v", err)
+		}
+		messages = append(messages, &domain.OutboxMessage{
+			Id:        mm.ID.Hex(),
+			Event:     mm.Event,
+			TaskId:    mm.TaskID,
+			Owner:     mm.Owner,
+			Payload:   []byte(mm.Payload),
+			CreatedAt: mm.CreatedAt,
+		})
+	}
+
+	return messages, nil
+}
+
+func (r *mongoOutboxRepository) AckOutbox(ctx context.Context, hexIDs []string) error {
+	ids := make([]primitive.ObjectID, 0, len(hexIDs))
+	for _, hex := range hexIDs {
+		id, err := primitive.ObjectIDFromHex(hex)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidID, err)
+		}
+		ids = append(ids, id)
+	}
+
+	if _, err := r.outbox.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}}); err != nil {
+		logDBError(ctx, "failed to delete outbox messages", err)
+		return fmt.Errorf("failed to delete outbox messages: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	"grpc-todo/domain"
+	"grpc-todo/search"
+)
+
+// projection is the state of every task after the commits up to seq, the
+// last of which was written at at. index covers the text of every task.
+type projection struct {
+	seq   int64
+	at    time.Time
+	tasks map[string]*TaskState
+	index *search.Index
+}
+
+func newProjection(s *Snapshot) *projection {
+	p := &projection{tasks: make(map[string]*TaskState), index: search.NewIndex()}
+	if s != nil {
+		p.seq, p.at = s.Seq, s.At
+		for _, t := range cloneTasks(s.Tasks) {
+			p.tasks[t.ID] = t
+			p.index.Add(t.ID, t.Title, t.Description)
+		}
+	}
+	return p
+}
+
+// apply folds a commit into the projection. Events were checked before
+// they were written, so events for tasks that are missing or in another
+// state are skipped rather than failing the replay.
+func (p *projection) apply(c *Commit) {
+	now := c.At.Unix()
+	for _, e := range c.Events {
+		t := p.tasks[e.TaskID]
+		if t == nil && e.Type != domain.EventTaskCreated {
+			continue
+		}
+
+		switch e.Type {
+		case domain.EventTaskCreated:
+			if t != nil || e.Task == nil {
+				continue
+			}
+			t = cloneTasks([]*TaskState{e.Task})[0]
+			t.ID = e.TaskID
+			t.StatusChangedAt = now
+			if t.Status == "DONE" {
+				// Imported tasks may say when they were completed.
+				t.CompletedAt = cmp.Or(t.CompletedAt, now)
+				t.StatusChangedAt = t.CompletedAt
+			} else {
+				t.CompletedAt = 0
+			}
+			p.tasks[t.ID] = t
+			p.index.Add(t.ID, t.Title, t.Description)
+		case domain.EventTaskUpdated:
+			t.Title, t.Description = e.Title, e.Description
+			if e.DueAt != nil {
+				t.DueAt = *e.DueAt
+			}
+			p.index.Add(t.ID, t.Title, t.Description)
+		case domain.EventTaskStatusUpdated:
+			if t.Status == e.Status {
+				continue
+			}
+			t.History = append(t.History, StateTransition{From: t.Status, To: e.Status, ChangedBy: e.Actor, ChangedAt: now})
+			t.Status, t.StatusChangedAt, t.CompletedAt = e.Status, now, 0
+			if e.Status == "DONE" {
+				t.CompletedAt = now
+			}
+		case domain.EventTaskDeleted:
+			t.DeletedAt, t.DeletedBy = now, e.Actor
+		case domain.EventTaskUndeleted:
+			t.DeletedAt, t.DeletedBy = 0, ""
+		case domain.EventTaskArchived:
+			t.ArchivedAt = now
+		case domain.EventTaskRestored:
+			// As in the Mongo repository, the status counts as entered at
+			// the restore.
+			t.ArchivedAt, t.StatusChangedAt = 0, now
+		case domain.EventTaskPurged:
+			delete(p.tasks, e.TaskID)
+			p.index.Remove(e.TaskID)
+		}
+	}
+	p.seq, p.at = c.Seq, c.At
+}
+
+func (t *TaskState) live() bool {
+	return t.ArchivedAt == 0 && t.DeletedAt == 0
+}
+
+func (t *TaskState) trashed() bool {
+	return t.DeletedAt != 0
+}
+
+func (t *TaskState) archived() bool {
+	return t.ArchivedAt != 0
+}
+
+func (t *TaskState) toDomain() *domain.Task {
+	return &domain.Task{
+		Id:          t.ID,
+		Title:       t.Title,
+		Description: t.Description,
+		Status:      t.Status,
+		CreatedAt:   t.CreatedAt,
+		Owner:       t.Owner,
+		DueAt:       t.DueAt,
+		ICalUID:     t.ICalUID,
+		CompletedAt: t.CompletedAt,
+		ArchivedAt:  t.ArchivedAt,
+		DeletedAt:   t.DeletedAt,
+		DeletedBy:   t.DeletedBy,
+	}
+}
+
+func newTaskState(task *domain.Task) *TaskState {
+	return &TaskState{
+		Title:       task.Title,
+		Description: task.Description,
+		Status:      task.Status,
+		CreatedAt:   task.CreatedAt,
+		Owner:       task.Owner,
+		DueAt:       task.DueAt,
+		ICalUID:     task.ICalUID,
+		CompletedAt: task.CompletedAt,
+	}
+}
+
+// find returns the tasks that match keep in ID order, which is creation
+// order.
+func (p *projection) find(keep func(*TaskState) bool) []*TaskState {
+	var res []*TaskState
+	for _, t := range p.tasks {
+		if keep(t) {
+			res = append(res, t)
+		}
+	}
+	slices.SortFunc(res, func(a, b *TaskState) int { return strings.Compare(a.ID, b.ID) })
+	return res
+}
+
+func (p *projection) snapshot() []*TaskState {
+	return cloneTasks(p.find(func(*TaskState) bool { return true }))
+}
+
+// cloneTasks deep-copies tasks so that a snapshot does not change with the
+// projection it was taken from.
+func cloneTasks(tasks []*TaskState) []*TaskState {
+	res := make([]*TaskState, len(tasks))
+	for i, t := range tasks {
+		c := *t
+		c.History = slices.Clone(t.History)
+		res[i] = &c
+	}
+	return res
+}
+
+func toDomainTasks(tasks []*TaskState) []*domain.Task {
+	res := make([]*domain.Task, len(tasks))
+	for i, t := range tasks {
+		res[i] = t.toDomain()
+	}
+	return res
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const quotaCollection = "task_quotas"
+
+// reservationLease is how long reserved slots stay held when they are not
+// released, as when the server stops between reserving and storing tasks.
+// Every reservation extends it for all of the owner's reservations, which
+// are forgotten together once none has been made for that long.
+const reservationLease = time.Minute
+
+// taskQuotas keeps reserved task slots per owner in the task_quotas
+// collection, where every process sharing the database sees them.
+type taskQuotas struct {
+	coll *mongo.Collection
+}
+
+func newTaskQuotas(db *mongo.Database) *taskQuotas {
+	return &taskQuotas{coll: db.Collection(quotaCollection)}
+}
+
+// reserve adds n to the owner's reserved slots with a single $inc-like
+// update, so that every reservation sees the ones made before it: either
+// still reserved or, once released, as stored tasks. It returns the slots
+// reserved before this call.
+func (q *taskQuotas) reserve(ctx context.Context, owner string, n int) (int64, func(), error) {
+	now := time.Now().Unix()
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"reserved": bson.M{"$add": bson.A{
+			bson.M{"$cond": bson.A{bson.M{"$gt": bson.A{"$expires_at", now}}, "$reserved", 0}},
+			n,
+		}},
+		"expires_at": now + int64(reservationLease/time.Second),
+	}}}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var doc struct {
+		Reserved int64 `bson:"reserved"`
+	}
+	if err := q.coll.FindOneAndUpdate(ctx, bson.M{"_id": owner}, update, opts).Decode(&doc); err != nil {
+		logDBError(ctx, "failed to reserve tasks", err, slog.String("owner", owner))
+		return 0, nil, fmt.Errorf("failed to reserve tasks: %v", err)
+	}
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() { q.release(context.WithoutCancel(ctx), owner, n) })
+	}
+	return doc.Reserved - int64(n), release, nil
+}
+
+// release gives back n reserved slots. A failure is only logged: the slots
+// are freed anyway when the lease runs out.
+func (q *taskQuotas) release(ctx context.Context, owner string, n int) {
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"reserved": bson.M{"$max": bson.A{0, bson.M{"$subtract": bson.A{"$reserved", n}}}},
+	}}}}
+	if _, err := q.coll.UpdateOne(ctx, bson.M{"_id": owner}, update); err != nil {
+		logDBError(ctx, "failed to release reserved tasks", err, slog.String("owner", owner))
+	}
+}
+
+func (r *mongoRepository) ReserveTasks(ctx context.Context, owner string, n int) (int64, func(), error) {
+	before, release, err := r.quotas.reserve(ctx, owner, n)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	count, err := r.CountTasks(ctx, owner)
+	if err != nil {
+		release()
+		return 0, nil, err
+	}
+	return count + before, release, nil
+}
@@ -1,12 +1,17 @@
 package repository
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"sync"
 	"time"
 
 	"grpc-todo/domain"
+	"grpc-todo/filter"
+	"grpc-todo/logging"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -14,14 +19,95 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-var ErrNotFound = errors.New("not found")
+var (
+	ErrNotFound  = errors.New("not found")
+	ErrInvalidID = errors.New("invalid task ID")
+	// ErrAlreadyExists is returned when a task is created with an ID that is
+	// already taken.
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrBatchAborted is reported for the items of an all-or-nothing batch
+	// that were not applied because another item failed.
+	ErrBatchAborted = errors.New("batch aborted")
+	// ErrInvalidPageToken is returned for a page token that no listing
+	// handed out.
+	ErrInvalidPageToken = errors.New("invalid page token")
+)
 
 type Repository interface {
 	CreateTask(ctx context.Context, task *domain.Task) (*domain.Task, error)
 	GetAllTasks(ctx context.Context) ([]*domain.Task, error)
-	UpdateTaskStatus(ctx context.Context, id string, status string) error
-	DeleteTask(ctx context.Context, id string) error
-	DeleteDoneTasks(ctx context.Context) (int64, error)
+	// FilterTasks returns the live tasks that match a filter over the
+	// fields of filter.Tasks, sorted by order and then by ID.
+	FilterTasks(ctx context.Context, expr filter.Expr, order []filter.OrderKey) ([]*domain.Task, error)
+	GetTask(ctx context.Context, id string) (*domain.Task, error)
+	// UpdateTask leaves the due date unchanged when dueAt is nil.
+	UpdateTask(ctx context.Context, id string, title string, description string, dueAt *int64) (*domain.Task, error)
+	// UpdateTaskStatus changes a task's status and, in the same write,
+	// appends the change and who made it to the task's history.
+	UpdateTaskStatus(ctx context.Context, id string, status string, changedBy string) error
+	// GetTaskHistory returns the status transitions of a task, oldest
+	// first. Purging a task forgets its history.
+	GetTaskHistory(ctx context.Context, id string) ([]*domain.StatusTransition, error)
+	// DeleteTask moves a task to the trash, recording who deleted it. Other
+	// methods treat tasks in the trash as missing, except the trash methods
+	// and FindTasksByAge and DeleteTasksByAge with AgeFilter.Trashed set.
+	DeleteTask(ctx context.Context, id string, deletedBy string) error
+	// ListTrash returns the tasks in the trash a page at a time, like
+	// ListArchivedTasks.
+	ListTrash(ctx context.Context, pageSize int, pageToken string) ([]*domain.Task, string, error)
+	// GetDeletedTask returns a task in the trash.
+	GetDeletedTask(ctx context.Context, id string) (*domain.Task, error)
+	// UndeleteTask takes a task out of the trash and returns it.
+	UndeleteTask(ctx context.Context, id string) (*domain.Task, error)
+	// PurgeTask deletes a task in the trash for good and returns it.
+	PurgeTask(ctx context.Context, id string) (*domain.Task, error)
+	// FindTasksByAge returns the tasks that match f, oldest first.
+	FindTasksByAge(ctx context.Context, f AgeFilter) ([]*domain.Task, error)
+	// DeleteTasksByAge deletes up to f.Limit tasks that match f and returns
+	// them. ArchiveTasksByAge moves them to the archive instead. Each call
+	// is one transaction where the deployment supports them.
+	DeleteTasksByAge(ctx context.Context, f AgeFilter) ([]*domain.Task, error)
+	ArchiveTasksByAge(ctx context.Context, f AgeFilter) ([]*domain.Task, error)
+	// ArchiveTask moves a task to the archive and returns it as archived.
+	ArchiveTask(ctx context.Context, id string) (*domain.Task, error)
+	// ListArchivedTasks returns up to pageSize archived tasks (100 when
+	// zero) that follow pageToken, in ID order, and the token of the next
+	// page, which is empty after the last one.
+	ListArchivedTasks(ctx context.Context, pageSize int, pageToken string) ([]*domain.Task, string, error)
+	// GetArchivedTask returns a task in the archive.
+	GetArchivedTask(ctx context.Context, id string) (*domain.Task, error)
+	// RestoreTask moves an archived task back to the live tasks. It fails
+	// with ErrAlreadyExists when a live task has taken its ID.
+	RestoreTask(ctx context.Context, id string) (*domain.Task, error)
+	// GetTasksByOwner returns the owner's live tasks in ID order.
+	GetTasksByOwner(ctx context.Context, owner string) ([]*domain.Task, error)
+	CountTasks(ctx context.Context, owner string) (int64, error)
+	// ReserveTasks holds n task slots for the owner while new tasks are
+	// stored and returns how many slots were in use before: the owner's
+	// live tasks plus the slots other calls hold. Concurrent calls each see
+	// the slots of the calls before them, so checking the result against a
+	// quota cannot let them all through. Call release once the tasks are
+	// stored, or failed to be.
+	ReserveTasks(ctx context.Context, owner string, n int) (used int64, release func(), err error)
+	// SearchTasks returns the tasks that match q, most relevant first.
+	SearchTasks(ctx context.Context, q SearchQuery) ([]*SearchHit, error)
+	// GetTaskStats summarizes the live tasks over the window of q, listing
+	// up to q.OldestOpen open tasks (10 when zero).
+	GetTaskStats(ctx context.Context, q domain.StatsQuery) (*domain.TaskStats, error)
+	// FindTaskByICalUID returns the owner's task imported from the calendar
+	// entry with the given UID.
+	FindTaskByICalUID(ctx context.Context, owner string, uid string) (*domain.Task, error)
+	// ForEachTask calls fn for every task, reading them from the database as
+	// fn consumes them. It stops at the first error fn returns.
+	ForEachTask(ctx context.Context, fn func(*domain.Task) error) error
+
+	// Batch methods return one error per item, nil for items that were
+	// applied. With allOrNothing set, nothing is written unless every item
+	// succeeds; items that were otherwise fine report ErrBatchAborted.
+	// BatchCreateTasks keeps the IDs of tasks that already have one.
+	BatchCreateTasks(ctx context.Context, tasks []*domain.Task, allOrNothing bool) ([]error, error)
+	BatchUpdateTaskStatus(ctx context.Context, updates []StatusUpdate, changedBy string, allOrNothing bool) ([]error, error)
+	BatchDeleteTasks(ctx context.Context, ids []string, deletedBy string, allOrNothing bool) ([]error, error)
 }
 
 type mongoTask struct {
@@ -30,17 +116,106 @@ type mongoTask struct {
 	Description string             `bson:"description"`
 	Status      string             `bson:"status"`
 	CreatedAt   int64              `bson:"created_at"`
+	Owner       string             `bson:"owner"`
+	DueAt       int64              `bson:"due_at,omitempty"`
+	ICalUID     string             `bson:"ical_uid,omitempty"`
+	CompletedAt int64              `bson:"completed_at,omitempty"`
+	// StatusChangedAt is when the task entered its current status. Tasks
+	// stored before it was tracked have none until the first age query
+	// gives them the time of that query.
+	StatusChangedAt int64 `bson:"status_changed_at,omitempty"`
+	// DeletedAt is set while the task is in the trash.
+	DeletedAt int64  `bson:"deleted_at,omitempty"`
+	DeletedBy string `bson:"deleted_by,omitempty"`
+	// History is the status transitions of the task, written by the
+	// update that changes the status. Older ones are in task_history.
+	History []StateTransition `bson:"history,omitempty"`
+}
+
+// filterKeys are the document keys of the fields of filter.Tasks.
+var filterKeys = map[string]string{
+	"title":       "title",
+	"description": "description",
+	"owner":       "owner",
+	"status":      "status",
+	"created":     "created_at",
+	"due":         "due_at",
+	"completed":   "completed_at",
+}
+
+func (mt mongoTask) toDomain() *domain.Task {
+	return &domain.Task{
+		Id:          mt.ID.Hex(),
+		Title:       mt.Title,
+		Description: mt.Description,
+		Status:      mt.Status,
+		CreatedAt:   mt.CreatedAt,
+		Owner:       mt.Owner,
+		DueAt:       mt.DueAt,
+		ICalUID:     mt.ICalUID,
+		CompletedAt: mt.CompletedAt,
+		DeletedAt:   mt.DeletedAt,
+		DeletedBy:   mt.DeletedBy,
+	}
+}
+
+func newMongoTask(task *domain.Task) mongoTask {
+	mt := mongoTask{
+		Title:           task.Title,
+		Description:     task.Description,
+		Status:          task.Status,
+		CreatedAt:       task.CreatedAt,
+		Owner:           task.Owner,
+		DueAt:           task.DueAt,
+		ICalUID:         task.ICalUID,
+		StatusChangedAt: time.Now().Unix(),
+	}
+	if mt.Status == "DONE" {
+		// Imported tasks may say when they were completed.
+		mt.CompletedAt = cmp.Or(task.CompletedAt, mt.StatusChangedAt)
+		mt.StatusChangedAt = mt.CompletedAt
+	}
+	return mt
 }
 
 type mongoRepository struct {
 	collection *mongo.Collection
+	archive    *mongo.Collection
+	history    *mongo.Collection
+	quotas     *taskQuotas
+	// outbox is nil unless the repository was created WithOutbox.
+	outbox *mongo.Collection
+
+	txMu        sync.Mutex
+	txChecked   bool
+	txSupported bool
+
+	backfillMu sync.Mutex
+	backfilled bool
+
+	searchMu    sync.Mutex
+	textIndexed bool
 }
 
-func NewRepository(db *mongo.Database) Repository {
+func NewRepository(db *mongo.Database, opts ...Option) Repository {
 	collection := db.Collection("tasks")
-	return &mongoRepository{
+	r := &mongoRepository{
 		collection: collection,
+		archive:    db.Collection(archiveCollection),
+		history:    db.Collection(historyCollection),
+		quotas:     newTaskQuotas(db),
+	}
+	for _, opt := range opts {
+		opt(r)
 	}
+	return r
+}
+
+// logDBError reports a database failure through the request-scoped logger so
+// that it carries the request ID of the call that triggered it.
+func logDBError(ctx context.Context, msg string, err error, attrs ...slog.Attr) {
+	attrs = append(attrs, slog.String("error", err.Error()))
+	logging.FromContext(ctx).LogAttrs(ctx, slog.LevelError, msg, attrs...)
 }
 
 func ConnectToMongoDB(uri string) (*mongo.Client, error) {
@@ -61,30 +236,37 @@ func ConnectToMongoDB(uri string) (*mongo.Client, error) {
 }
 
 func (r *mongoRepository) CreateTask(ctx context.Context, task *domain.Task) (*domain.Task, error) {
-	doc := mongoTask{
-		Title:       task.Title,
-		Description: task.Description,
-		Status:      task.Status,
-		CreatedAt:   task.CreatedAt,
-	}
+	doc := newMongoTask(task)
+	doc.ID = primitive.NewObjectID()
 
-	result, err := r.collection.InsertOne(ctx, doc)
+	err := r.mutate(ctx, func(ctx context.Context) error {
+		if _, err := r.collection.InsertOne(ctx, doc); err != nil {
+			return err
+		}
+		task.Id = doc.ID.Hex()
+		return r.record(ctx, domain.EventTaskCreated, task)
+	})
 	if err != nil {
+		logDBError(ctx, "failed to insert task", err)
 		return nil, fmt.Errorf("failed to insert task: %v", err)
 	}
 
-	insertedID, ok := result.InsertedID.(primitive.ObjectID)
-	if !ok {
-		return nil, fmt.Errorf("failed to get inserted ID")
-	}
-
-	task.Id = insertedID.Hex()
 	return task, nil
 }
 
 func (r *mongoRepository) GetAllTasks(ctx context.Context) ([]*domain.Task, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{})
+	return r.queryTasks(ctx, live(bson.M{}))
+}
+
+func (r *mongoRepository) FilterTasks(ctx context.Context, expr filter.Expr, order []filter.OrderKey) ([]*domain.Task, error) {
+	sort := append(filter.MongoSort(order, filterKeys), bson.E{Key: "_id", Value: 1})
+	return r.queryTasks(ctx, live(filter.Mongo(expr, filterKeys)), options.Find().SetSort(sort))
+}
+
+func (r *mongoRepository) queryTasks(ctx context.Context, query bson.M, opts ...*options.FindOptions) ([]*domain.Task, error) {
+	cursor, err := r.collection.Find(ctx, query, opts...)
 	if err != nil {
+		logDBError(ctx, "failed to find tasks", err)
 		return nil, fmt.Errorf("failed to find tasks: %v", err)
 	}
 	defer cursor.Close(ctx)
@@ -93,71 +275,191 @@ func (r *mongoRepository) GetAllTasks(ctx context.Context) ([]*domain.Task, erro
 	for cursor.Next(ctx) {
 		var mt mongoTask
 		if err := cursor.Decode(&mt); err != nil {
+			logDBError(ctx, "failed to decode task", err)
 			return nil, fmt.Errorf("failed to decode task: %v", err)
 		}
 
-		tasks = append(tasks, &domain.Task{
-			Id:          mt.ID.Hex(),
-			Title:       mt.Title,
-			Description: mt.Description,
-			Status:      mt.Status,
-			CreatedAt:   mt.CreatedAt,
-		})
+		tasks = append(tasks, mt.toDomain())
 	}
 
 	if err := cursor.Err(); err != nil {
+		logDBError(ctx, "cursor error", err)
 		return nil, fmt.Errorf("cursor error: %v", err)
 	}
 
 	return tasks, nil
 }
 
-func (r *mongoRepository) UpdateTaskStatus(ctx context.Context, id string, status string) error {
+func (r *mongoRepository) GetTask(ctx context.Context, id string) (*domain.Task, error) {
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return fmt.Errorf("invalid task ID: %v", err)
+		return nil, fmt.Errorf("%w: %v", ErrInvalidID, err)
+	}
+
+	var mt mongoTask
+	err = r.collection.FindOne(ctx, live(bson.M{"_id": objectID})).Decode(&mt)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("task with ID %s: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		logDBError(ctx, "failed to find task", err, slog.String("task_id", id))
+		return nil, fmt.Errorf("failed to find task: %v", err)
 	}
 
-	filter := bson.M{"_id": objectID}
-	update := bson.M{"$set": bson.M{"status": status}}
+	return mt.toDomain(), nil
+}
 
-	result, err := r.collection.UpdateOne(ctx, filter, update)
+func (r *mongoRepository) UpdateTask(ctx context.Context, id string, title string, description string, dueAt *int64) (*domain.Task, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return fmt.Errorf("failed to update task: %v", err)
+		return nil, fmt.Errorf("%w: %v", ErrInvalidID, err)
 	}
 
-	if result.MatchedCount == 0 {
-		return fmt.Errorf("task with ID %s not found", id)
+	filter := live(bson.M{"_id": objectID})
+	set := bson.M{"title": title, "description": description}
+	if dueAt != nil {
+		set["due_at"] = *dueAt
+	}
+	update := bson.M{"$set": set}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var task *domain.Task
+	err = r.mutate(ctx, func(ctx context.Context) error {
+		var mt mongoTask
+		if err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&mt); err != nil {
+			return err
+		}
+		task = mt.toDomain()
+		return r.record(ctx, domain.EventTaskUpdated, task)
+	})
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("task with ID %s: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		logDBError(ctx, "failed to update task", err, slog.String("task_id", id))
+		return nil, fmt.Errorf("failed to update task: %v", err)
+	}
+
+	return task, nil
+}
+
+func (r *mongoRepository) UpdateTaskStatus(ctx context.Context, id string, status string, changedBy string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidID, err)
+	}
+
+	filter := live(bson.M{"_id": objectID})
+	now := time.Now().Unix()
+	update := statusUpdate(status, changedBy, now)
+
+	err = r.mutate(ctx, func(ctx context.Context) error {
+		var mt mongoTask
+		if err := r.collection.FindOneAndUpdate(ctx, filter, update).Decode(&mt); err != nil {
+			return err
+		}
+		applyStatus(&mt, status, now)
+		return r.record(ctx, domain.EventTaskStatusUpdated, mt.toDomain())
+	})
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return fmt.Errorf("task with ID %s: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		logDBError(ctx, "failed to update task", err, slog.String("task_id", id))
+		return fmt.Errorf("failed to update task: %v", err)
 	}
 
 	return nil
 }
 
-func (r *mongoRepository) DeleteTask(ctx context.Context, id string) error {
+func (r *mongoRepository) DeleteTask(ctx context.Context, id string, deletedBy string) error {
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return fmt.Errorf("invalid task ID: %v", err)
+		return fmt.Errorf("%w: %v", ErrInvalidID, err)
 	}
 
-	filter := bson.M{"_id": objectID}
+	filter := live(bson.M{"_id": objectID})
+	update := trashUpdate(deletedBy, time.Now().Unix())
 
-	result, err := r.collection.DeleteOne(ctx, filter)
+	err = r.mutate(ctx, func(ctx context.Context) error {
+		var mt mongoTask
+		if err := r.collection.FindOneAndUpdate(ctx, filter, update).Decode(&mt); err != nil {
+			return err
+		}
+		return r.record(ctx, domain.EventTaskDeleted, mt.toDomain())
+	})
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return fmt.Errorf("task with ID %s: %w", id, ErrNotFound)
+	}
 	if err != nil {
+		logDBError(ctx, "failed to delete task", err, slog.String("task_id", id))
 		return fmt.Errorf("failed to delete task: %v", err)
 	}
 
-	if result.DeletedCount == 0 {
-		return fmt.Errorf("task with ID %s not found", id)
+	return nil
+}
+
+// ownedBy matches the tasks of owner.
+func ownedBy(owner string) bson.M {
+	if owner == "" {
+		// Tasks created before owners were recorded have no owner field.
+		return bson.M{"owner": bson.M{"$in": bson.A{"", nil}}}
+	}
+	return bson.M{"owner": owner}
+}
+
+func (r *mongoRepository) GetTasksByOwner(ctx context.Context, owner string) ([]*domain.Task, error) {
+	return r.queryTasks(ctx, live(ownedBy(owner)), options.Find().SetSort(bson.M{"_id": 1}))
+}
+
+func (r *mongoRepository) CountTasks(ctx context.Context, owner string) (int64, error) {
+	count, err := r.collection.CountDocuments(ctx, live(ownedBy(owner)))
+	if err != nil {
+		logDBError(ctx, "failed to count tasks", err, slog.String("owner", owner))
+		return 0, fmt.Errorf("failed to count tasks: %v", err)
+	}
+	return count, nil
+}
+
+func (r *mongoRepository) ForEachTask(ctx context.Context, fn func(*domain.Task) error) error {
+	opts := options.Find().SetBatchSize(100).SetSort(bson.M{"_id": 1})
+	cursor, err := r.collection.Find(ctx, live(bson.M{}), opts)
+	if err != nil {
+		logDBError(ctx, "failed to find tasks", err)
+		return fmt.Errorf("failed to find tasks: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var mt mongoTask
+		if err := cursor.Decode(&mt); err != nil {
+			logDBError(ctx, "failed to decode task", err)
+			return fmt.Errorf("failed to decode task: %v", err)
+		}
+
+		err := fn(mt.toDomain())
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		logDBError(ctx, "cursor error", err)
+		return fmt.Errorf("cursor error: %v", err)
 	}
 
 	return nil
 }
 
-func (r *mongoRepository) DeleteDoneTasks(ctx context.Context) (int64, error) {
-	filter := bson.M{"status": "DONE"}
-	result, err := r.collection.DeleteMany(ctx, filter)
+func (r *mongoRepository) FindTaskByICalUID(ctx context.Context, owner string, uid string) (*domain.Task, error) {
+	var mt mongoTask
+	err := r.collection.FindOne(ctx, live(bson.M{"owner": owner, "ical_uid": uid})).Decode(&mt)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("task with UID %s: %w", uid, ErrNotFound)
+	}
 	if err != nil {
-		return 0, fmt.Errorf("error deleting DONE tasks: %v", err)
+		logDBError(ctx, "failed to find task", err, slog.String("ical_uid", uid))
+		return nil, fmt.Errorf("failed to find task: %v", err)
 	}
-	return result.DeletedCount, nil
+	return mt.toDomain(), nil
 }
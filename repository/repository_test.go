@@ -2,11 +2,15 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"maps"
 	"os"
+	"slices"
 	"testing"
 	"time"
 
 	"grpc-todo/domain"
+	"grpc-todo/filter"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -126,7 +130,7 @@ func TestRepository_UpdateTaskStatus(t *testing.T) {
 		t.Fatalf("CreateTask failed: %v", err)
 	}
 
-	err = repo.UpdateTaskStatus(context.Background(), createdTask.Id, "DONE")
+	err = repo.UpdateTaskStatus(context.Background(), createdTask.Id, "DONE", "alice")
 	if err != nil {
 		t.Fatalf("UpdateTaskStatus failed: %v", err)
 	}
@@ -161,7 +165,7 @@ func TestRepository_DeleteTask(t *testing.T) {
 		t.Fatalf("CreateTask failed: %v", err)
 	}
 
-	err = repo.DeleteTask(context.Background(), createdTask.Id)
+	err = repo.DeleteTask(context.Background(), createdTask.Id, "alice")
 	if err != nil {
 		t.Fatalf("DeleteTask failed: %v", err)
 	}
@@ -176,51 +180,1164 @@ func TestRepository_DeleteTask(t *testing.T) {
 	}
 }
 
-func TestRepository_DeleteDoneTasks(t *testing.T) {
+func TestRepository_Trash(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
 
+	ctx := context.Background()
 	repo := NewRepository(db)
 
-	_, err := repo.CreateTask(context.Background(), &domain.Task{
+	kept, err := repo.CreateTask(ctx, &domain.Task{Title: "Kept", Status: "TODO", Owner: "alice"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	deleted, err := repo.CreateTask(ctx, &domain.Task{Title: "Deleted", Status: "TODO", Owner: "alice"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	if err := repo.DeleteTask(ctx, deleted.Id, "bob"); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+
+	if _, err := repo.GetTask(ctx, deleted.Id); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected a deleted task to be hidden, got %v", err)
+	}
+	if err := repo.UpdateTaskStatus(ctx, deleted.Id, "DONE", "alice"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected a deleted task not to be updated, got %v", err)
+	}
+	if n, _ := repo.CountTasks(ctx, "alice"); n != 1 {
+		t.Errorf("Expected deleted tasks not to be counted, got %d", n)
+	}
+	if owned, err := repo.GetTasksByOwner(ctx, "alice"); err != nil || len(owned) != 1 || owned[0].Id != kept.Id {
+		t.Errorf("Expected only the kept task to be listed for alice, got %v (%v)", owned, err)
+	}
+	if owned, err := repo.GetTasksByOwner(ctx, "bob"); err != nil || len(owned) != 0 {
+		t.Errorf("Expected no tasks for bob, got %v (%v)", owned, err)
+	}
+
+	trash, next, err := repo.ListTrash(ctx, 10, "")
+	if err != nil {
+		t.Fatalf("ListTrash failed: %v", err)
+	}
+	if len(trash) != 1 || trash[0].Id != deleted.Id || trash[0].DeletedBy != "bob" || trash[0].DeletedAt == 0 || next != "" {
+		t.Fatalf("Expected the deleted task in the trash, got %+v", trash)
+	}
+	if task, err := repo.GetDeletedTask(ctx, deleted.Id); err != nil || task.DeletedBy != "bob" {
+		t.Errorf("Expected GetDeletedTask to return the task, got %+v, %v", task, err)
+	}
+
+	undeleted, err := repo.UndeleteTask(ctx, deleted.Id)
+	if err != nil {
+		t.Fatalf("UndeleteTask failed: %v", err)
+	}
+	if undeleted.DeletedAt != 0 || undeleted.DeletedBy != "" {
+		t.Errorf("Expected the task to leave the trash, got %+v", undeleted)
+	}
+	if _, err := repo.UndeleteTask(ctx, kept.Id); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a task not in the trash, got %v", err)
+	}
+	if _, err := repo.PurgeTask(ctx, kept.Id); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected PurgeTask to refuse a task not in the trash, got %v", err)
+	}
+
+	if err := repo.DeleteTask(ctx, deleted.Id, "bob"); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+	purged, err := repo.DeleteTasksByAge(ctx, AgeFilter{Trashed: true, Before: time.Now().Add(time.Minute).Unix()})
+	if err != nil {
+		t.Fatalf("DeleteTasksByAge failed: %v", err)
+	}
+	if len(purged) != 1 || purged[0].Id != deleted.Id {
+		t.Errorf("Expected the trash to be emptied, got %v", purged)
+	}
+	if n, _ := db.Collection("tasks").CountDocuments(ctx, bson.M{}); n != 1 {
+		t.Errorf("Expected only the kept task to be stored, got %d", n)
+	}
+}
+
+func TestRepository_DeleteTasksByAge(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewRepository(db)
+
+	done, err := repo.CreateTask(ctx, &domain.Task{
 		Title:       "Done Task",
 		Description: "This is done",
 		Status:      "DONE",
 		CreatedAt:   time.Now().Unix(),
+		CompletedAt: time.Now().Add(-48 * time.Hour).Unix(),
 	})
 	if err != nil {
 		t.Fatalf("CreateTask failed: %v", err)
 	}
 
-	_, err = repo.CreateTask(context.Background(), &domain.Task{
-		Title:       "Todo Task",
-		Description: "This is todo",
-		Status:      "TODO",
-		CreatedAt:   time.Now().Unix(),
+	fresh, err := repo.CreateTask(ctx, &domain.Task{
+		Title:     "Todo Task",
+		Status:    "TODO",
+		CreatedAt: time.Now().Unix(),
 	})
 	if err != nil {
 		t.Fatalf("CreateTask failed: %v", err)
 	}
+	if err := repo.UpdateTaskStatus(ctx, fresh.Id, "DONE", "alice"); err != nil {
+		t.Fatalf("UpdateTaskStatus failed: %v", err)
+	}
+	got, err := repo.GetTask(ctx, fresh.Id)
+	if err != nil || got.CompletedAt == 0 {
+		t.Fatalf("Expected completed_at to be set, got %+v (%v)", got, err)
+	}
 
-	deletedCount, err := repo.DeleteDoneTasks(context.Background())
+	filter := AgeFilter{Status: "DONE", Before: time.Now().Add(-24 * time.Hour).Unix()}
+	old, err := repo.FindTasksByAge(ctx, filter)
 	if err != nil {
-		t.Fatalf("DeleteDoneTasks failed: %v", err)
+		t.Fatalf("FindTasksByAge failed: %v", err)
+	}
+	if len(old) != 1 || old[0].Id != done.Id {
+		t.Errorf("Expected only the task completed two days ago, got %v", old)
 	}
 
-	if deletedCount != 1 {
-		t.Errorf("Expected to delete 1 DONE task, got %d", deletedCount)
+	deleted, err := repo.DeleteTasksByAge(ctx, filter)
+	if err != nil {
+		t.Fatalf("DeleteTasksByAge failed: %v", err)
+	}
+	if len(deleted) != 1 || deleted[0].Title != "Done Task" {
+		t.Errorf("Expected to delete the old DONE task, got %v", deleted)
 	}
 
-	tasks, err := repo.GetAllTasks(context.Background())
+	tasks, err := repo.GetAllTasks(ctx)
 	if err != nil {
 		t.Fatalf("GetAllTasks failed: %v", err)
 	}
+	if len(tasks) != 1 || tasks[0].Id != fresh.Id {
+		t.Errorf("Expected the recently completed task to remain, got %v", tasks)
+	}
 
-	if len(tasks) != 1 {
-		t.Errorf("Expected 1 remaining task, got %d", len(tasks))
+	// Reopening clears the completion time.
+	if err := repo.UpdateTaskStatus(ctx, fresh.Id, "TODO", "alice"); err != nil {
+		t.Fatalf("UpdateTaskStatus failed: %v", err)
+	}
+	if got, _ := repo.GetTask(ctx, fresh.Id); got.CompletedAt != 0 {
+		t.Errorf("Expected completed_at to be cleared, got %d", got.CompletedAt)
+	}
+}
+
+func TestRepository_AgeOfLegacyTasks(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewRepository(db)
+
+	// A task stored before status_changed_at was tracked, created long ago
+	// and just moved to DONE by a server that did not track it either.
+	legacy := bson.M{"_id": primitive.NewObjectID(), "title": "Legacy", "status": "DONE", "created_at": time.Now().Add(-90 * 24 * time.Hour).Unix()}
+	if _, err := db.Collection("tasks").InsertOne(ctx, legacy); err != nil {
+		t.Fatalf("InsertOne failed: %v", err)
+	}
+
+	filter := AgeFilter{Status: "DONE", Before: time.Now().Add(-24 * time.Hour).Unix()}
+	old, err := repo.FindTasksByAge(ctx, filter)
+	if err != nil {
+		t.Fatalf("FindTasksByAge failed: %v", err)
+	}
+	if len(old) != 0 {
+		t.Errorf("Expected the legacy task to count as entering DONE now, got %v", old)
+	}
+	if deleted, err := repo.DeleteTasksByAge(ctx, filter); err != nil || len(deleted) != 0 {
+		t.Errorf("Expected nothing to be deleted, got %v (%v)", deleted, err)
+	}
+
+	var mt mongoTask
+	if err := db.Collection("tasks").FindOne(ctx, bson.M{"_id": legacy["_id"]}).Decode(&mt); err != nil {
+		t.Fatalf("FindOne failed: %v", err)
+	}
+	if since := time.Now().Unix() - mt.StatusChangedAt; since < 0 || since > 60 {
+		t.Errorf("Expected status_changed_at to be backfilled with the current time, got %d", mt.StatusChangedAt)
+	}
+
+	later := AgeFilter{Status: "DONE", Before: time.Now().Add(time.Hour).Unix()}
+	if found, err := repo.FindTasksByAge(ctx, later); err != nil || len(found) != 1 {
+		t.Errorf("Expected the legacy task once its backfilled time has passed, got %v (%v)", found, err)
+	}
+}
+
+func TestRepository_ArchiveTasksByAge(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewRepository(db)
+
+	task, err := repo.CreateTask(ctx, &domain.Task{Title: "Paused", Status: "PAUSED", CreatedAt: time.Now().Unix()})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+
+	archived, err := repo.ArchiveTasksByAge(ctx, AgeFilter{Status: "PAUSED", Before: time.Now().Add(time.Minute).Unix()})
+	if err != nil {
+		t.Fatalf("ArchiveTasksByAge failed: %v", err)
+	}
+	if len(archived) != 1 || archived[0].Id != task.Id {
+		t.Fatalf("Expected the paused task to be archived, got %v", archived)
+	}
+	if _, err := repo.GetTask(ctx, task.Id); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected the task to leave the tasks collection, got %v", err)
+	}
+	if n, _ := db.Collection("tasks_archive").CountDocuments(ctx, bson.M{"title": "Paused"}); n != 1 {
+		t.Errorf("Expected 1 archived task, got %d", n)
+	}
+}
+
+func TestRepository_ArchiveTask(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewRepository(db)
+
+	var ids []string
+	for _, title := range []string{"First", "Second", "Third"} {
+		task, err := repo.CreateTask(ctx, &domain.Task{Title: title, Status: "DONE", CreatedAt: time.Now().Unix()})
+		if err != nil {
+			t.Fatalf("CreateTask failed: %v", err)
+		}
+		archived, err := repo.ArchiveTask(ctx, task.Id)
+		if err != nil {
+			t.Fatalf("ArchiveTask failed: %v", err)
+		}
+		if archived.ArchivedAt == 0 || archived.CompletedAt == 0 {
+			t.Errorf("Expected archived_at and completed_at to be set, got %+v", archived)
+		}
+		ids = append(ids, task.Id)
+	}
+	if _, err := repo.ArchiveTask(ctx, ids[0]); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for an archived task, got %v", err)
+	}
+
+	page, next, err := repo.ListArchivedTasks(ctx, 2, "")
+	if err != nil {
+		t.Fatalf("ListArchivedTasks failed: %v", err)
+	}
+	if len(page) != 2 || page[0].Id != ids[0] || next == "" {
+		t.Fatalf("Expected a first page of 2 tasks and a token, got %d tasks and %q", len(page), next)
+	}
+	page, next, err = repo.ListArchivedTasks(ctx, 2, next)
+	if err != nil {
+		t.Fatalf("ListArchivedTasks failed: %v", err)
+	}
+	if len(page) != 1 || page[0].Id != ids[2] || next != "" {
+		t.Fatalf("Expected a last page with the third task, got %d tasks and %q", len(page), next)
+	}
+	if _, _, err := repo.ListArchivedTasks(ctx, 2, "not-a-token"); !errors.Is(err, ErrInvalidPageToken) {
+		t.Errorf("Expected ErrInvalidPageToken, got %v", err)
+	}
+
+	restored, err := repo.RestoreTask(ctx, ids[1])
+	if err != nil {
+		t.Fatalf("RestoreTask failed: %v", err)
+	}
+	if restored.Title != "Second" || restored.ArchivedAt != 0 {
+		t.Errorf("Expected the task back without archived_at, got %+v", restored)
+	}
+	if _, err := repo.GetTask(ctx, ids[1]); err != nil {
+		t.Errorf("Expected the restored task to be live, got %v", err)
+	}
+	if _, err := repo.RestoreTask(ctx, ids[1]); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a live task, got %v", err)
+	}
+}
+
+func TestRepository_BatchCreateTasks(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	keptID := primitive.NewObjectID().Hex()
+	tasks := []*domain.Task{
+		{Title: "New", Status: "TODO"},
+		{Id: keptID, Title: "Kept", Status: "DONE", CreatedAt: 42},
+		{Id: keptID, Title: "Duplicate", Status: "TODO"},
+		{Id: "bad", Title: "Bad", Status: "TODO"},
+	}
+
+	errs, err := repo.BatchCreateTasks(ctx, tasks, false)
+	if err != nil {
+		t.Fatalf("BatchCreateTasks failed: %v", err)
+	}
+
+	if errs[0] != nil || errs[1] != nil {
+		t.Errorf("Expected the first two tasks to be created, got %v", errs)
+	}
+	if !errors.Is(errs[2], ErrAlreadyExists) {
+		t.Errorf("Expected ErrAlreadyExists, got %v", errs[2])
+	}
+	if !errors.Is(errs[3], ErrInvalidID) {
+		t.Errorf("Expected ErrInvalidID, got %v", errs[3])
 	}
 
-	if tasks[0].Status == "DONE" {
-		t.Errorf("Expected no DONE tasks remaining")
+	kept, err := repo.GetTask(ctx, keptID)
+	if err != nil {
+		t.Fatalf("GetTask failed: %v", err)
+	}
+	if kept.Title != "Kept" || kept.CreatedAt != 42 {
+		t.Errorf("Expected the preserved task, got %+v", kept)
+	}
+}
+
+func TestRepository_BatchDeleteTasks(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	task, err := repo.CreateTask(ctx, &domain.Task{Title: "Task", Status: "TODO"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	ids := []string{task.Id, primitive.NewObjectID().Hex()}
+
+	errs, err := repo.BatchDeleteTasks(ctx, ids, "alice", true)
+	if err != nil {
+		t.Fatalf("BatchDeleteTasks failed: %v", err)
+	}
+	if !errors.Is(errs[0], ErrBatchAborted) || !errors.Is(errs[1], ErrNotFound) {
+		t.Errorf("Expected ErrBatchAborted and ErrNotFound, got %v", errs)
+	}
+
+	errs, err = repo.BatchDeleteTasks(ctx, ids, "alice", false)
+	if err != nil {
+		t.Fatalf("BatchDeleteTasks failed: %v", err)
+	}
+	if errs[0] != nil || !errors.Is(errs[1], ErrNotFound) {
+		t.Errorf("Expected the existing task to be deleted, got %v", errs)
+	}
+}
+
+func TestRepository_ForEachTask(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	for _, title := range []string{"Task 1", "Task 2", "Task 3"} {
+		if _, err := repo.CreateTask(ctx, &domain.Task{Title: title, Status: "TODO"}); err != nil {
+			t.Fatalf("CreateTask failed: %v", err)
+		}
+	}
+
+	stop := errors.New("stop")
+	var titles []string
+	err := repo.ForEachTask(ctx, func(task *domain.Task) error {
+		titles = append(titles, task.Title)
+		if len(titles) == 2 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("Expected the callback error, got %v", err)
+	}
+	if len(titles) != 2 || titles[0] != "Task 1" {
+		t.Errorf("Expected the first two tasks in order, got %v", titles)
+	}
+}
+
+func TestRepository_Outbox(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewRepository(db, WithOutbox())
+	outbox := NewOutboxRepository(db)
+
+	task, err := repo.CreateTask(ctx, &domain.Task{Title: "Outbox", Status: "TODO", Owner: "alice"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	if err := repo.UpdateTaskStatus(ctx, task.Id, "DONE", "alice"); err != nil {
+		t.Fatalf("UpdateTaskStatus failed: %v", err)
+	}
+	if err := repo.DeleteTask(ctx, task.Id, "alice"); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+	if err := repo.DeleteTask(ctx, task.Id, "alice"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got %v", err)
+	}
+
+	now := time.Now()
+	messages, err := outbox.ClaimOutbox(ctx, now, time.Minute, 10)
+	if err != nil {
+		t.Fatalf("ClaimOutbox failed: %v", err)
+	}
+	want := []string{domain.EventTaskCreated, domain.EventTaskStatusUpdated, domain.EventTaskDeleted}
+	if len(messages) != len(want) {
+		t.Fatalf("Expected %d messages, got %d", len(want), len(messages))
+	}
+	for i, msg := range messages {
+		if msg.Event != want[i] || msg.TaskId != task.Id || msg.Owner != "alice" {
+			t.Errorf("Expected %s for task %s, got %+v", want[i], task.Id, msg)
+		}
+	}
+	if got, err := DecodeOutboxTask(messages[1]); err != nil || got.Title != "Outbox" || got.Status != "DONE" {
+		t.Errorf("Expected the task after the change, got %+v (%v)", got, err)
+	}
+
+	if again, _ := outbox.ClaimOutbox(ctx, now, time.Minute, 10); len(again) != 0 {
+		t.Errorf("Expected claimed messages to be hidden, got %d", len(again))
+	}
+
+	if err := outbox.AckOutbox(ctx, []string{messages[0].Id, messages[1].Id, messages[2].Id}); err != nil {
+		t.Fatalf("AckOutbox failed: %v", err)
+	}
+	if left, _ := outbox.ClaimOutbox(ctx, now.Add(time.Hour), time.Minute, 10); len(left) != 0 {
+		t.Errorf("Expected an empty outbox, got %d messages", len(left))
+	}
+
+	// Batches write one message per applied item.
+	tasks := []*domain.Task{{Title: "A", Status: "TODO", Owner: "alice"}, {Title: "B", Status: "TODO", Owner: "alice"}}
+	if _, err := repo.BatchCreateTasks(ctx, tasks, false); err != nil {
+		t.Fatalf("BatchCreateTasks failed: %v", err)
+	}
+	if _, err := repo.BatchDeleteTasks(ctx, []string{tasks[0].Id, task.Id}, "alice", false); err != nil {
+		t.Fatalf("BatchDeleteTasks failed: %v", err)
+	}
+	messages, err = outbox.ClaimOutbox(ctx, now.Add(time.Hour), time.Minute, 10)
+	if err != nil {
+		t.Fatalf("ClaimOutbox failed: %v", err)
+	}
+	want = []string{domain.EventTaskCreated, domain.EventTaskCreated, domain.EventTaskDeleted}
+	if len(messages) != len(want) {
+		t.Fatalf("Expected %d messages, got %d", len(want), len(messages))
+	}
+	for i, msg := range messages {
+		if msg.Event != want[i] {
+			t.Errorf("Expected %s, got %s", want[i], msg.Event)
+		}
+	}
+	if messages[2].TaskId != tasks[0].Id {
+		t.Errorf("Expected the deletion of %s, got %s", tasks[0].Id, messages[2].TaskId)
+	}
+}
+
+func TestEventSourcedRepository_Outbox(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	log, err := NewFileEventLog(dir)
+	if err != nil {
+		t.Fatalf("NewFileEventLog failed: %v", err)
+	}
+	repo, err := NewEventSourcedRepository(ctx, log, WithSnapshotEvery(2))
+	if err != nil {
+		t.Fatalf("NewEventSourcedRepository failed: %v", err)
+	}
+	outbox, err := NewEventOutboxRepository(log)
+	if err != nil {
+		t.Fatalf("NewEventOutboxRepository failed: %v", err)
+	}
+
+	task, err := repo.CreateTask(ctx, &domain.Task{Title: "Outbox", Status: "TODO", Owner: "alice"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	if err := repo.UpdateTaskStatus(ctx, task.Id, "DONE", "alice"); err != nil {
+		t.Fatalf("UpdateTaskStatus failed: %v", err)
+	}
+	if err := repo.DeleteTask(ctx, task.Id, "alice"); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+	if _, err := repo.PurgeTask(ctx, task.Id); err != nil {
+		t.Fatalf("PurgeTask failed: %v", err)
+	}
+
+	now := time.Now()
+	messages, err := outbox.ClaimOutbox(ctx, now, time.Minute, 10)
+	if err != nil {
+		t.Fatalf("ClaimOutbox failed: %v", err)
+	}
+	want := []string{domain.EventTaskCreated, domain.EventTaskStatusUpdated, domain.EventTaskDeleted, domain.EventTaskPurged}
+	if len(messages) != len(want) {
+		t.Fatalf("Expected %d messages, got %d", len(want), len(messages))
+	}
+	for i, msg := range messages {
+		if msg.Event != want[i] || msg.TaskId != task.Id || msg.Owner != "alice" {
+			t.Errorf("Expected %s for task %s, got %+v", want[i], task.Id, msg)
+		}
+	}
+	if got, err := DecodeOutboxTask(messages[1]); err != nil || got.Title != "Outbox" || got.Status != "DONE" {
+		t.Errorf("Expected the task after the change, got %+v (%v)", got, err)
+	}
+	if again, _ := outbox.ClaimOutbox(ctx, now, time.Minute, 10); len(again) != 0 {
+		t.Errorf("Expected the claimed log to be hidden, got %d messages", len(again))
+	}
+
+	// Only the first two are published; the rest come back once the lease
+	// runs out, also to a relay that starts over from the stored position.
+	if err := outbox.AckOutbox(ctx, []string{messages[0].Id, messages[1].Id}); err != nil {
+		t.Fatalf("AckOutbox failed: %v", err)
+	}
+	reopened, err := NewFileEventLog(dir)
+	if err != nil {
+		t.Fatalf("NewFileEventLog failed: %v", err)
+	}
+	outbox, err = NewEventOutboxRepository(reopened)
+	if err != nil {
+		t.Fatalf("NewEventOutboxRepository failed: %v", err)
+	}
+	left, err := outbox.ClaimOutbox(ctx, now.Add(time.Hour), time.Minute, 10)
+	if err != nil {
+		t.Fatalf("ClaimOutbox failed: %v", err)
+	}
+	if len(left) != 2 || left[0].Id != messages[2].Id || left[1].Id != messages[3].Id {
+		t.Fatalf("Expected the unpublished messages again, got %+v", left)
+	}
+	if got, err := DecodeOutboxTask(left[1]); err != nil || got.Id != task.Id || got.DeletedBy != "alice" {
+		t.Errorf("Expected the purged task as it was, got %+v (%v)", got, err)
+	}
+
+	if err := outbox.AckOutbox(ctx, []string{left[0].Id, left[1].Id}); err != nil {
+		t.Fatalf("AckOutbox failed: %v", err)
+	}
+	// Acknowledging everything ends the lease at once.
+	next, err := repo.CreateTask(ctx, &domain.Task{Title: "Next", Status: "TODO", Owner: "alice"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	rest, err := outbox.ClaimOutbox(ctx, now.Add(time.Hour), time.Minute, 10)
+	if err != nil {
+		t.Fatalf("ClaimOutbox failed: %v", err)
+	}
+	if len(rest) != 1 || rest[0].TaskId != next.Id {
+		t.Errorf("Expected only the new task, got %+v", rest)
+	}
+}
+
+func TestRepository_AuditLog(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	auditLog, err := NewAuditRepository(ctx, db, time.Hour)
+	if err != nil {
+		t.Fatalf("NewAuditRepository failed: %v", err)
+	}
+	// Opening the log again with another TTL updates the index.
+	if _, err := NewAuditRepository(ctx, db, 2*time.Hour); err != nil {
+		t.Fatalf("NewAuditRepository with a new TTL failed: %v", err)
+	}
+
+	start := time.Now().Truncate(time.Second)
+	entries := []*domain.AuditEntry{
+		{TaskId: "a", Actor: "alice", Method: "CreateTask", Timestamp: start,
+			Changes: []domain.FieldChange{{Field: "title", After: "A"}}},
+		{TaskId: "a", Actor: "bob", Method: "UpdateTaskStatus", Timestamp: start.Add(time.Second),
+			Changes: []domain.FieldChange{{Field: "status", Before: "TODO", After: "DONE"}}},
+		{TaskId: "b", Actor: "alice", Method: "CreateTask", Timestamp: start.Add(2 * time.Second)},
+	}
+	if err := auditLog.AppendAudit(ctx, entries...); err != nil {
+		t.Fatalf("AppendAudit failed: %v", err)
+	}
+
+	page, next, err := auditLog.ListAuditEntries(ctx, AuditFilter{TaskID: "a"}, 1, "")
+	if err != nil {
+		t.Fatalf("ListAuditEntries failed: %v", err)
+	}
+	if len(page) != 1 || page[0].Method != "CreateTask" || next == "" {
+		t.Fatalf("Expected the first entry of task a and a next page, got %v (%q)", page, next)
+	}
+	page, next, err = auditLog.ListAuditEntries(ctx, AuditFilter{TaskID: "a"}, 1, next)
+	if err != nil {
+		t.Fatalf("ListAuditEntries failed: %v", err)
+	}
+	if len(page) != 1 || page[0].Actor != "bob" || next != "" {
+		t.Fatalf("Expected the last entry of task a, got %v (%q)", page, next)
+	}
+	if c := page[0].Changes; len(c) != 1 || c[0].Before != "TODO" || c[0].After != "DONE" {
+		t.Errorf("Expected the status change to be kept, got %v", c)
+	}
+
+	byActor, _, err := auditLog.ListAuditEntries(ctx, AuditFilter{Actor: "alice", Since: start.Add(time.Second)}, 10, "")
+	if err != nil {
+		t.Fatalf("ListAuditEntries failed: %v", err)
+	}
+	if len(byActor) != 1 || byActor[0].TaskId != "b" {
+		t.Errorf("Expected alice's entry for task b, got %v", byActor)
+	}
+
+	if _, _, err := auditLog.ListAuditEntries(ctx, AuditFilter{}, 10, "bad token"); !errors.Is(err, ErrInvalidPageToken) {
+		t.Errorf("Expected ErrInvalidPageToken, got %v", err)
+	}
+}
+
+func TestRepository_TaskHistory(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewRepository(db)
+
+	task, err := repo.CreateTask(ctx, &domain.Task{Title: "History", Status: "TODO", Owner: "alice"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	// Transitions from before history was kept with the task come first.
+	oid, _ := primitive.ObjectIDFromHex(task.Id)
+	legacy := mongoStatusTransition{TaskID: oid, From: "PAUSED", To: "TODO", ChangedBy: "carol", ChangedAt: 1}
+	if _, err := db.Collection(historyCollection).InsertOne(ctx, legacy); err != nil {
+		t.Fatalf("InsertOne failed: %v", err)
+	}
+	if err := repo.UpdateTaskStatus(ctx, task.Id, "IN_PROGRESS", "alice"); err != nil {
+		t.Fatalf("UpdateTaskStatus failed: %v", err)
+	}
+	// Setting the status a task already has is not a transition.
+	if err := repo.UpdateTaskStatus(ctx, task.Id, "IN_PROGRESS", "alice"); err != nil {
+		t.Fatalf("UpdateTaskStatus failed: %v", err)
+	}
+	errs, err := repo.BatchUpdateTaskStatus(ctx, []StatusUpdate{{ID: task.Id, Status: "DONE"}}, "bob", false)
+	if err != nil || errs[0] != nil {
+		t.Fatalf("BatchUpdateTaskStatus failed: %v %v", err, errs)
+	}
+
+	history, err := repo.GetTaskHistory(ctx, task.Id)
+	if err != nil {
+		t.Fatalf("GetTaskHistory failed: %v", err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("Expected 3 transitions, got %d", len(history))
+	}
+	if h := history[0]; h.From != "PAUSED" || h.ChangedBy != "carol" {
+		t.Errorf("Expected the legacy transition first, got %+v", h)
+	}
+	if h := history[1]; h.From != "TODO" || h.To != "IN_PROGRESS" || h.ChangedBy != "alice" || h.TaskId != task.Id {
+		t.Errorf("Expected TODO to IN_PROGRESS by alice, got %+v", h)
+	}
+	if h := history[2]; h.From != "IN_PROGRESS" || h.To != "DONE" || h.ChangedBy != "bob" {
+		t.Errorf("Expected IN_PROGRESS to DONE by bob, got %+v", h)
+	}
+
+	// The history is part of the task, so it survives the archive.
+	if _, err := repo.ArchiveTask(ctx, task.Id); err != nil {
+		t.Fatalf("ArchiveTask failed: %v", err)
+	}
+	if history, _ := repo.GetTaskHistory(ctx, task.Id); len(history) != 3 {
+		t.Errorf("Expected the archived task to keep its history, got %d transitions", len(history))
+	}
+	if _, err := repo.RestoreTask(ctx, task.Id); err != nil {
+		t.Fatalf("RestoreTask failed: %v", err)
+	}
+
+	if err := repo.DeleteTask(ctx, task.Id, "alice"); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+	if _, err := repo.PurgeTask(ctx, task.Id); err != nil {
+		t.Fatalf("PurgeTask failed: %v", err)
+	}
+	if history, _ := repo.GetTaskHistory(ctx, task.Id); len(history) != 0 {
+		t.Errorf("Expected purging to forget the history, got %d transitions", len(history))
+	}
+}
+
+func TestRepository_SearchTasks(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	testSearchTasks(t, NewRepository(db))
+}
+
+// testSearchTasks checks a repository's search against tasks it creates.
+func testSearchTasks(t *testing.T, repo Repository) {
+	t.Helper()
+	ctx := context.Background()
+
+	deploy, err := repo.CreateTask(ctx, &domain.Task{Title: "Deploy website", Description: "Ship the landing page +website", Status: "TODO"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	report, err := repo.CreateTask(ctx, &domain.Task{Title: "Write report", Description: "Summarize the website deploy", Status: "DONE"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	if _, err := repo.CreateTask(ctx, &domain.Task{Title: "Buy milk", Status: "TODO"}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+
+	hits, err := repo.SearchTasks(ctx, SearchQuery{Text: "deploy"})
+	if err != nil {
+		t.Fatalf("SearchTasks failed: %v", err)
+	}
+	if len(hits) != 2 || hits[0].Task.Id != deploy.Id || hits[1].Task.Id != report.Id {
+		t.Fatalf("Expected the title match before the description match, got %+v", hits)
+	}
+	if hits[0].Score <= hits[1].Score {
+		t.Errorf("Expected descending scores, got %v and %v", hits[0].Score, hits[1].Score)
+	}
+
+	hits, err = repo.SearchTasks(ctx, SearchQuery{Text: "deploy", Statuses: []string{"DONE"}})
+	if err != nil {
+		t.Fatalf("SearchTasks failed: %v", err)
+	}
+	if len(hits) != 1 || hits[0].Task.Id != report.Id {
+		t.Errorf("Expected only the DONE task, got %+v", hits)
+	}
+
+	hits, err = repo.SearchTasks(ctx, SearchQuery{Text: "website", Tags: []string{"+Website"}})
+	if err != nil {
+		t.Fatalf("SearchTasks failed: %v", err)
+	}
+	if len(hits) != 1 || hits[0].Task.Id != deploy.Id {
+		t.Errorf("Expected only the tagged task, got %+v", hits)
+	}
+
+	hits, err = repo.SearchTasks(ctx, SearchQuery{Text: "deploy", Limit: 1})
+	if err != nil {
+		t.Fatalf("SearchTasks failed: %v", err)
+	}
+	if len(hits) != 1 {
+		t.Errorf("Expected 1 hit with a limit of 1, got %d", len(hits))
+	}
+
+	// Deleted tasks are not found.
+	if err := repo.DeleteTask(ctx, deploy.Id, "alice"); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+	hits, err = repo.SearchTasks(ctx, SearchQuery{Text: "deploy"})
+	if err != nil {
+		t.Fatalf("SearchTasks failed: %v", err)
+	}
+	if len(hits) != 1 || hits[0].Task.Id != report.Id {
+		t.Errorf("Expected the deleted task to be left out, got %+v", hits)
+	}
+}
+
+func TestEventSourcedRepository_SearchTasks(t *testing.T) {
+	log, err := NewFileEventLog(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileEventLog failed: %v", err)
+	}
+	repo, err := NewEventSourcedRepository(context.Background(), log)
+	if err != nil {
+		t.Fatalf("NewEventSourcedRepository failed: %v", err)
+	}
+	testSearchTasks(t, repo)
+}
+
+func TestRepository_ReserveTasks(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	testReserveTasks(t, NewRepository(db))
+}
+
+func TestEventSourcedRepository_ReserveTasks(t *testing.T) {
+	log, err := NewFileEventLog(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileEventLog failed: %v", err)
+	}
+	repo, err := NewEventSourcedRepository(context.Background(), log)
+	if err != nil {
+		t.Fatalf("NewEventSourcedRepository failed: %v", err)
+	}
+	testReserveTasks(t, repo)
+}
+
+func TestEventSourcedRepository_SharedQuota(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	var replicas []Repository
+	for range 2 {
+		repo, err := NewEventSourcedRepository(ctx, NewMongoEventLog(db), WithSharedQuota(db))
+		if err != nil {
+			t.Fatalf("NewEventSourcedRepository failed: %v", err)
+		}
+		replicas = append(replicas, repo)
+	}
+	testReserveTasks(t, replicas[0])
+
+	// A reservation made by one replica counts in the other.
+	_, release, err := replicas[0].ReserveTasks(ctx, "carol", 3)
+	if err != nil {
+		t.Fatalf("ReserveTasks failed: %v", err)
+	}
+	defer release()
+	used, releaseOther, err := replicas[1].ReserveTasks(ctx, "carol", 1)
+	if err != nil {
+		t.Fatalf("ReserveTasks failed: %v", err)
+	}
+	releaseOther()
+	if used != 3 {
+		t.Errorf("Expected the other replica's reservation to be counted, got %d", used)
+	}
+}
+
+func testReserveTasks(t *testing.T, repo Repository) {
+	t.Helper()
+	ctx := context.Background()
+
+	if _, err := repo.CreateTask(ctx, &domain.Task{Title: "Stored", Status: "TODO", Owner: "alice"}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+
+	used, release, err := repo.ReserveTasks(ctx, "alice", 2)
+	if err != nil {
+		t.Fatalf("ReserveTasks failed: %v", err)
+	}
+	if used != 1 {
+		t.Errorf("Expected 1 slot in use before the first reservation, got %d", used)
+	}
+
+	used, releaseNext, err := repo.ReserveTasks(ctx, "alice", 1)
+	if err != nil {
+		t.Fatalf("ReserveTasks failed: %v", err)
+	}
+	if used != 3 {
+		t.Errorf("Expected the first reservation to be counted, got %d", used)
+	}
+	bobUsed, bobRelease, err := repo.ReserveTasks(ctx, "bob", 1)
+	if err != nil {
+		t.Fatalf("ReserveTasks failed: %v", err)
+	}
+	bobRelease()
+	if bobUsed != 0 {
+		t.Errorf("Expected another owner to be unaffected, got %d", bobUsed)
+	}
+
+	release()
+	release()
+	releaseNext()
+	used, release, err = repo.ReserveTasks(ctx, "alice", 1)
+	if err != nil {
+		t.Fatalf("ReserveTasks failed: %v", err)
+	}
+	release()
+	if used != 1 {
+		t.Errorf("Expected released slots to be free again, got %d in use", used)
+	}
+}
+
+func TestRepository_SavedViews(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	views, err := NewViewRepository(ctx, db)
+	if err != nil {
+		t.Fatalf("NewViewRepository failed: %v", err)
+	}
+
+	mine, err := views.CreateView(ctx, &domain.SavedView{Owner: "alice", Name: "Mine", Filter: "owner=alice", FilterVersion: 1})
+	if err != nil {
+		t.Fatalf("CreateView failed: %v", err)
+	}
+	if _, err := views.CreateView(ctx, &domain.SavedView{Owner: "alice", Name: "Mine", FilterVersion: 1}); !errors.Is(err, ErrAlreadyExists) {
+		t.Errorf("Expected ErrAlreadyExists for a duplicate name, got %v", err)
+	}
+	if _, err := views.CreateView(ctx, &domain.SavedView{Owner: "bob", Name: "Shared", Shared: true, FilterVersion: 1}); err != nil {
+		t.Fatalf("CreateView failed: %v", err)
+	}
+	if _, err := views.CreateView(ctx, &domain.SavedView{Owner: "bob", Name: "Private", FilterVersion: 1}); err != nil {
+		t.Fatalf("CreateView failed: %v", err)
+	}
+
+	list, err := views.ListViews(ctx, "alice")
+	if err != nil {
+		t.Fatalf("ListViews failed: %v", err)
+	}
+	if len(list) != 2 || list[0].Name != "Mine" || list[1].Name != "Shared" {
+		t.Errorf("Expected alice's view and bob's shared one, got %+v", list)
+	}
+
+	if _, err := views.UpdateView(ctx, &domain.SavedView{Id: mine.Id, Owner: "bob", Name: "Stolen"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for another owner's view, got %v", err)
+	}
+	updated, err := views.UpdateView(ctx, &domain.SavedView{Id: mine.Id, Owner: "alice", Name: "Renamed", OrderBy: "due", FilterVersion: 1})
+	if err != nil {
+		t.Fatalf("UpdateView failed: %v", err)
+	}
+	if updated.Name != "Renamed" || updated.OrderBy != "due" || updated.Filter != "" {
+		t.Errorf("Expected the view to be replaced, got %+v", updated)
+	}
+
+	if err := views.DeleteView(ctx, "alice", mine.Id); err != nil {
+		t.Fatalf("DeleteView failed: %v", err)
+	}
+	if _, err := views.GetView(ctx, mine.Id); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after deletion, got %v", err)
+	}
+}
+
+func TestRepository_FilterTasks(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	testFilterTasks(t, NewRepository(db))
+}
+
+func TestEventSourcedRepository_FilterTasks(t *testing.T) {
+	log, err := NewFileEventLog(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileEventLog failed: %v", err)
+	}
+	repo, err := NewEventSourcedRepository(context.Background(), log)
+	if err != nil {
+		t.Fatalf("NewEventSourcedRepository failed: %v", err)
+	}
+	testFilterTasks(t, repo)
+}
+
+// testFilterTasks checks that a repository applies filters as filter.Match
+// does, including to tasks with no due date.
+func testFilterTasks(t *testing.T, repo Repository) {
+	t.Helper()
+	ctx := context.Background()
+	jan1 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+
+	website, err := repo.CreateTask(ctx, &domain.Task{Title: "Deploy website", Status: "IN_PROGRESS", CreatedAt: jan1 + 60})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	api, err := repo.CreateTask(ctx, &domain.Task{Title: "Deploy API", Status: "IN_PROGRESS", CreatedAt: jan1 - 60, DueAt: jan1})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	report, err := repo.CreateTask(ctx, &domain.Task{Title: "Write report", Status: "TODO", CreatedAt: jan1 + 60})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	if err := repo.DeleteTask(ctx, report.Id, "alice"); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+
+	for _, tc := range []struct {
+		filter string
+		want   []string
+	}{
+		{`status:IN_PROGRESS AND created>2026-01-01 AND title~"DEPLOY"`, []string{website.Id}},
+		{"due<2026-02-01", []string{api.Id}},
+		{"NOT due<2026-02-01", []string{website.Id}},
+		{"status=TODO", nil},
+	} {
+		expr, err := filter.Parse(tc.filter, filter.Tasks)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", tc.filter, err)
+		}
+		tasks, err := repo.FilterTasks(ctx, expr, nil)
+		if err != nil {
+			t.Fatalf("FilterTasks(%q) failed: %v", tc.filter, err)
+		}
+		var got []string
+		for _, task := range tasks {
+			got = append(got, task.Id)
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("%q: expected %v, got %v", tc.filter, tc.want, got)
+		}
+	}
+	order, err := filter.ParseOrderBy("due desc", filter.Tasks)
+	if err != nil {
+		t.Fatalf("ParseOrderBy failed: %v", err)
+	}
+	tasks, err := repo.FilterTasks(ctx, nil, order)
+	if err != nil {
+		t.Fatalf("FilterTasks failed: %v", err)
+	}
+	if len(tasks) != 2 || tasks[0].Id != api.Id || tasks[1].Id != website.Id {
+		t.Errorf("Expected the task with a due date first, got %+v", tasks)
+	}
+}
+
+func TestRepository_GetTaskStats(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	testTaskStats(t, NewRepository(db))
+}
+
+func TestEventSourcedRepository_GetTaskStats(t *testing.T) {
+	log, err := NewFileEventLog(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileEventLog failed: %v", err)
+	}
+	repo, err := NewEventSourcedRepository(context.Background(), log)
+	if err != nil {
+		t.Fatalf("NewEventSourcedRepository failed: %v", err)
+	}
+	testTaskStats(t, repo)
+}
+
+// testTaskStats checks a repository's statistics against those
+// domain.ComputeStats gives for the same tasks.
+func testTaskStats(t *testing.T, repo Repository) {
+	t.Helper()
+	ctx := context.Background()
+	day := int64(24 * 60 * 60)
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC).Unix()
+
+	var tasks []*domain.Task
+	for _, task := range []*domain.Task{
+		{Title: "Old", Status: "TODO", CreatedAt: start - day},
+		{Title: "Older", Status: "TODO", CreatedAt: start - 2*day},
+		{Title: "Paused", Status: "PAUSED", CreatedAt: start + 10},
+		{Title: "Quick", Status: "DONE", CreatedAt: start + 20, CompletedAt: start + 120},
+		{Title: "Slow", Status: "DONE", CreatedAt: start + 30, CompletedAt: start + day + 400},
+		{Title: "Slower", Status: "DONE", CreatedAt: start - day, CompletedAt: start + day + 500},
+		{Title: "Late", Status: "DONE", CreatedAt: start, CompletedAt: start + 3*day},
+	} {
+		created, err := repo.CreateTask(ctx, task)
+		if err != nil {
+			t.Fatalf("CreateTask failed: %v", err)
+		}
+		tasks = append(tasks, created)
+	}
+	deleted, err := repo.CreateTask(ctx, &domain.Task{Title: "Deleted", Status: "TODO", CreatedAt: start})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	if err := repo.DeleteTask(ctx, deleted.Id, "alice"); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+
+	q := domain.StatsQuery{Start: start, End: start + 2*day + 1, Bucket: day, OldestOpen: 2}
+	stats, err := repo.GetTaskStats(ctx, q)
+	if err != nil {
+		t.Fatalf("GetTaskStats failed: %v", err)
+	}
+	want := domain.ComputeStats(tasks, q)
+	if want.MedianTimeToDone != day+370 {
+		t.Fatalf("Expected the median of Quick, Slow and Slower to be Slow's, got %d", want.MedianTimeToDone)
+	}
+
+	if !maps.Equal(stats.StatusCounts, want.StatusCounts) {
+		t.Errorf("Expected status counts %v, got %v", want.StatusCounts, stats.StatusCounts)
+	}
+	if !slices.Equal(stats.Buckets, want.Buckets) || len(stats.Buckets) != 3 {
+		t.Errorf("Expected buckets %+v, got %+v", want.Buckets, stats.Buckets)
+	}
+	if stats.MedianTimeToDone != want.MedianTimeToDone {
+		t.Errorf("Expected a median time to done of %d, got %d", want.MedianTimeToDone, stats.MedianTimeToDone)
+	}
+	if len(stats.OldestOpen) != 2 || stats.OldestOpen[0].Title != "Older" || stats.OldestOpen[1].Title != "Old" {
+		t.Errorf("Expected Older and Old, got %+v", stats.OldestOpen)
+	}
+}
+
+func TestEventSourcedRepository(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	log, err := NewFileEventLog(dir)
+	if err != nil {
+		t.Fatalf("NewFileEventLog failed: %v", err)
+	}
+	repo, err := NewEventSourcedRepository(ctx, log, WithSnapshotEvery(3))
+	if err != nil {
+		t.Fatalf("NewEventSourcedRepository failed: %v", err)
+	}
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo.(*eventSourcedRepository).now = func() time.Time { return clock }
+
+	a, err := repo.CreateTask(ctx, &domain.Task{Title: "A", Status: "TODO", Owner: "alice"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	b, err := repo.CreateTask(ctx, &domain.Task{Title: "B", Status: "TODO", Owner: "alice"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	beforeChanges := clock
+
+	clock = clock.Add(time.Hour)
+	if err := repo.UpdateTaskStatus(ctx, a.Id, "DONE", "bob"); err != nil {
+		t.Fatalf("UpdateTaskStatus failed: %v", err)
+	}
+	if err := repo.DeleteTask(ctx, b.Id, "bob"); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+	if err := repo.DeleteTask(ctx, b.Id, "bob"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a deleted task, got %v", err)
+	}
+	if deleted, err := repo.GetDeletedTask(ctx, b.Id); err != nil || deleted.DeletedBy != "bob" {
+		t.Errorf("Expected the task in the trash, got %+v, %v", deleted, err)
+	}
+	if _, err := repo.GetArchivedTask(ctx, b.Id); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a task that is not archived, got %v", err)
+	}
+	if _, err := repo.GetTask(ctx, "bad"); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("Expected ErrInvalidID, got %v", err)
+	}
+
+	errs, err := repo.BatchCreateTasks(ctx, []*domain.Task{{Title: "C"}, {Id: a.Id, Title: "Dup"}}, true)
+	if err != nil {
+		t.Fatalf("BatchCreateTasks failed: %v", err)
+	}
+	if !errors.Is(errs[0], ErrBatchAborted) || !errors.Is(errs[1], ErrAlreadyExists) {
+		t.Errorf("Expected the batch to be aborted by the duplicate, got %v", errs)
+	}
+
+	// A new repository on the same log sees the same tasks, from the
+	// snapshot and the commits after it.
+	reopened, err := NewFileEventLog(dir)
+	if err != nil {
+		t.Fatalf("NewFileEventLog failed: %v", err)
+	}
+	repo2, err := NewEventSourcedRepository(ctx, reopened)
+	if err != nil {
+		t.Fatalf("NewEventSourcedRepository failed: %v", err)
+	}
+	if s, _ := reopened.LatestSnapshot(ctx, time.Time{}); s == nil || s.Seq != 3 {
+		t.Errorf("Expected a snapshot at commit 3, got %+v", s)
+	}
+
+	tasks, err := repo2.GetAllTasks(ctx)
+	if err != nil {
+		t.Fatalf("GetAllTasks failed: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].Id != a.Id || tasks[0].Status != "DONE" || tasks[0].CompletedAt != clock.Unix() {
+		t.Fatalf("Expected only task A, completed, got %+v", tasks)
+	}
+	if owned, err := repo2.GetTasksByOwner(ctx, "alice"); err != nil || len(owned) != 1 || owned[0].Id != a.Id {
+		t.Errorf("Expected only task A to be listed for alice, got %v (%v)", owned, err)
+	}
+	trash, _, err := repo2.ListTrash(ctx, 10, "")
+	if err != nil {
+		t.Fatalf("ListTrash failed: %v", err)
+	}
+	if len(trash) != 1 || trash[0].Id != b.Id || trash[0].DeletedBy != "bob" {
+		t.Errorf("Expected task B in the trash, got %+v", trash)
+	}
+	history, err := repo2.GetTaskHistory(ctx, a.Id)
+	if err != nil {
+		t.Fatalf("GetTaskHistory failed: %v", err)
+	}
+	if len(history) != 1 || history[0].From != "TODO" || history[0].To != "DONE" || history[0].ChangedBy != "bob" {
+		t.Errorf("Expected TODO to DONE by bob, got %+v", history)
+	}
+
+	asOf, err := repo2.TasksAsOf(ctx, beforeChanges)
+	if err != nil {
+		t.Fatalf("TasksAsOf failed: %v", err)
+	}
+	if len(asOf) != 2 || asOf[0].Status != "TODO" || asOf[1].Id != b.Id {
+		t.Errorf("Expected both tasks to do before the changes, got %+v", asOf)
+	}
+
+	// Writes through one repository reach the other at its next call.
+	if _, err := repo.ArchiveTask(ctx, a.Id); err != nil {
+		t.Fatalf("ArchiveTask failed: %v", err)
+	}
+	if _, err := repo2.GetTask(ctx, a.Id); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected the archived task to be hidden, got %v", err)
+	}
+	if archived, err := repo2.GetArchivedTask(ctx, a.Id); err != nil || archived.ArchivedAt == 0 {
+		t.Errorf("Expected the task in the archive, got %+v, %v", archived, err)
+	}
+	if _, err := repo2.RestoreTask(ctx, a.Id); err != nil {
+		t.Fatalf("RestoreTask failed: %v", err)
 	}
 }
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"grpc-todo/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AgeFilter selects the tasks that entered Status at or before Before, a
+// Unix time. With Trashed set it selects the tasks deleted at or before
+// Before instead, of any status. Limit caps how many are returned; zero
+// means no cap.
+type AgeFilter struct {
+	Status  string
+	Trashed bool
+	Before  int64
+	Limit   int
+}
+
+func (f AgeFilter) filter() bson.M {
+	if f.Trashed {
+		return bson.M{"deleted_at": bson.M{"$lte": f.Before}}
+	}
+	return live(bson.M{
+		"status":            f.Status,
+		"status_changed_at": bson.M{"$lte": f.Before},
+	})
+}
+
+// backfillStatusChangedAt sets status_changed_at to now on the tasks stored
+// before it was tracked. When they entered their status is unknown, and
+// counting from creation would make an old task that was just moved look
+// as if it had been in its status all along, so they count as entering it
+// now. It runs before the first age query of the repository.
+func (r *mongoRepository) backfillStatusChangedAt(ctx context.Context) error {
+	r.backfillMu.Lock()
+	defer r.backfillMu.Unlock()
+
+	if r.backfilled {
+		return nil
+	}
+	filter := bson.M{"status_changed_at": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"status_changed_at": time.Now().Unix()}}
+	if _, err := r.collection.UpdateMany(ctx, filter, update); err != nil {
+		return err
+	}
+	r.backfilled = true
+	return nil
+}
+
+// statusUpdate sets the status of a task and, when the status changes, when
+// it changed. completed_at follows the task in and out of DONE. Setting the
+// status a task already has leaves both times alone.
+func statusUpdate(status string, changedBy string, now int64) mongo.Pipeline {
+	changed := bson.M{"$ne": bson.A{"$status", status}}
+	var completedAt interface{} = "$$REMOVE"
+	if status == "DONE" {
+		completedAt = bson.M{"$cond": bson.A{changed, now, "$completed_at"}}
+	}
+	transition := bson.M{
+		"from":       "$status",
+		"to":         status,
+		"changed_by": bson.M{"$literal": changedBy},
+		"changed_at": now,
+	}
+	return mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"status":            status,
+		"status_changed_at": bson.M{"$cond": bson.A{changed, now, "$status_changed_at"}},
+		"completed_at":      completedAt,
+		"history": bson.M{"$cond": bson.A{
+			changed,
+			bson.M{"$concatArrays": bson.A{bson.M{"$ifNull": bson.A{"$history", bson.A{}}}, bson.A{transition}}},
+			"$history",
+		}},
+	}}}}
+}
+
+func (r *mongoRepository) findByAge(ctx context.Context, f AgeFilter) ([]mongoTask, error) {
+	sort := bson.D{{Key: "status_changed_at", Value: 1}, {Key: "created_at", Value: 1}}
+	if f.Trashed {
+		sort = bson.D{{Key: "deleted_at", Value: 1}}
+	}
+	opts := options.Find().SetSort(sort)
+	if f.Limit > 0 {
+		opts.SetLimit(int64(f.Limit))
+	}
+
+	var docs []mongoTask
+	cursor, err := r.collection.Find(ctx, f.filter(), opts)
+	if err == nil {
+		err = cursor.All(ctx, &docs)
+	}
+	return docs, err
+}
+
+func (r *mongoRepository) FindTasksByAge(ctx context.Context, f AgeFilter) ([]*domain.Task, error) {
+	err := r.backfillStatusChangedAt(ctx)
+	var docs []mongoTask
+	if err == nil {
+		docs, err = r.findByAge(ctx, f)
+	}
+	if err != nil {
+		logDBError(ctx, "failed to find tasks by age", err, slog.String("status", f.Status))
+		return nil, fmt.Errorf("failed to find tasks by age: %v", err)
+	}
+
+	tasks := make([]*domain.Task, len(docs))
+	for i, mt := range docs {
+		tasks[i] = mt.toDomain()
+	}
+	return tasks, nil
+}
+
+func (r *mongoRepository) DeleteTasksByAge(ctx context.Context, f AgeFilter) ([]*domain.Task, error) {
+	tasks, err := r.removeByAge(ctx, f, false)
+	if err != nil {
+		logDBError(ctx, "failed to delete tasks by age", err, slog.String("status", f.Status))
+		return nil, fmt.Errorf("failed to delete tasks by age: %v", err)
+	}
+	return tasks, nil
+}
+
+func (r *mongoRepository) ArchiveTasksByAge(ctx context.Context, f AgeFilter) ([]*domain.Task, error) {
+	tasks, err := r.removeByAge(ctx, f, true)
+	if err != nil {
+		logDBError(ctx, "failed to archive tasks by age", err, slog.String("status", f.Status))
+		return nil, fmt.Errorf("failed to archive tasks by age: %v", err)
+	}
+	return tasks, nil
+}
+
+// removeByAge deletes, and with archive set archives, the tasks matching f.
+// Each task is removed with the filter applied again, so one that changed
+// since it was found is kept. Archiving always runs in a transaction where
+// the deployment supports them, so that no task is lost between the two
+// collections.
+func (r *mongoRepository) removeByAge(ctx context.Context, f AgeFilter, archive bool) ([]*domain.Task, error) {
+	if err := r.backfillStatusChangedAt(ctx); err != nil {
+		return nil, err
+	}
+
+	run, event := r.mutate, domain.EventTaskDeleted
+	switch {
+	case archive:
+		run, event = r.atomically, domain.EventTaskArchived
+	case f.Trashed:
+		// Their deletion was recorded when they went to the trash.
+		event = domain.EventTaskPurged
+	}
+
+	var removed []*domain.Task
+	err := run(ctx, func(ctx context.Context) error {
+		removed = nil
+		var deleted []primitive.ObjectID
+		candidates, err := r.findByAge(ctx, f)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().Unix()
+		for _, c := range candidates {
+			filter := f.filter()
+			filter["_id"] = c.ID
+
+			var task *domain.Task
+			if archive {
+				task, err = r.archiveOne(ctx, filter, now)
+			} else {
+				var mt mongoTask
+				err = r.collection.FindOneAndDelete(ctx, filter).Decode(&mt)
+				task = mt.toDomain()
+			}
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			removed = append(removed, task)
+			if !archive {
+				deleted = append(deleted, c.ID)
+			}
+		}
+
+		if err := r.forgetHistory(ctx, deleted...); err != nil {
+			return err
+		}
+		return r.record(ctx, event, removed...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+
+	"grpc-todo/domain"
+	"grpc-todo/search"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultSearchLimit = 20
+	textIndexName      = "task_text"
+)
+
+// SearchQuery selects live tasks whose title or description contains any
+// word of Text, narrowed down to Statuses, if set, and to tasks that have
+// every todo.txt +project or @context token in Tags.
+type SearchQuery struct {
+	Text     string
+	Statuses []string
+	Tags     []string
+	// Limit caps the number of results; zero means 20.
+	Limit int
+}
+
+// SearchHit is a task that matched a search and its relevance. Scores only
+// compare within one search.
+type SearchHit struct {
+	Task  *domain.Task
+	Score float64
+}
+
+// ensureTextIndex creates the text index SearchTasks needs the first time
+// it is called. Title words weigh more than description words.
+func (r *mongoRepository) ensureTextIndex(ctx context.Context) error {
+	r.searchMu.Lock()
+	defer r.searchMu.Unlock()
+
+	if r.textIndexed {
+		return nil
+	}
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "title", Value: "text"}, {Key: "description", Value: "text"}},
+		Options: options.Index().SetName(textIndexName).SetWeights(bson.M{
+			"title":       search.TitleWeight,
+			"description": search.DescriptionWeight,
+		}),
+	})
+	if err != nil {
+		return err
+	}
+	r.textIndexed = true
+	return nil
+}
+
+func (r *mongoRepository) SearchTasks(ctx context.Context, q SearchQuery) ([]*SearchHit, error) {
+	if err := r.ensureTextIndex(ctx); err != nil {
+		logDBError(ctx, "failed to create text index", err)
+		return nil, fmt.Errorf("failed to create text index: %v", err)
+	}
+
+	filter := live(bson.M{"$text": bson.M{"$search": q.Text}})
+	if len(q.Statuses) > 0 {
+		filter["status"] = bson.M{"$in": q.Statuses}
+	}
+	var tags bson.A
+	for _, tag := range q.Tags {
+		re := primitive.Regex{Pattern: search.TagPattern(tag), Options: "i"}
+		tags = append(tags, bson.M{"$or": bson.A{bson.M{"title": re}, bson.M{"description": re}}})
+	}
+	if len(tags) > 0 {
+		filter["$and"] = tags
+	}
+
+	score := bson.M{"$meta": "textScore"}
+	opts := options.Find().
+		SetProjection(bson.M{"score": score}).
+		SetSort(bson.D{{Key: "score", Value: score}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(cmp.Or(q.Limit, defaultSearchLimit)))
+
+	var docs []struct {
+		mongoTask `bson:",inline"`
+		Score     float64 `bson:"score"`
+	}
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err == nil {
+		err = cursor.All(ctx, &docs)
+	}
+	if err != nil {
+		logDBError(ctx, "failed to search tasks", err, slog.String("query", q.Text))
+		return nil, fmt.Errorf("failed to search tasks: %v", err)
+	}
+
+	hits := make([]*SearchHit, len(docs))
+	for i, doc := range docs {
+		hits[i] = &SearchHit{Task: doc.toDomain(), Score: doc.Score}
+	}
+	return hits, nil
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+
+	"grpc-todo/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const defaultOldestOpen = 10
+
+type countByKey struct {
+	Key   any   `bson:"_id"`
+	Count int64 `bson:"count"`
+}
+
+// GetTaskStats computes the statistics in one aggregation over the live
+// tasks and, when tasks were completed in the window, finds the median time
+// to done with a second one that skips to the middle of them.
+func (r *mongoRepository) GetTaskStats(ctx context.Context, q domain.StatsQuery) (*domain.TaskStats, error) {
+	q.OldestOpen = cmp.Or(q.OldestOpen, defaultOldestOpen)
+	inWindow := bson.M{"$gte": q.Start, "$lt": q.End}
+	completed := live(bson.M{"status": "DONE", "completed_at": inWindow})
+	// countBuckets groups by the offset from the window start of the bucket
+	// the field falls in.
+	countBuckets := func(field string) bson.M {
+		offset := bson.M{"$subtract": bson.A{"$" + field, q.Start}}
+		return bson.M{"$group": bson.M{
+			"_id":   bson.M{"$subtract": bson.A{offset, bson.M{"$mod": bson.A{offset, q.Bucket}}}},
+			"count": bson.M{"$sum": 1},
+		}}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: live(bson.M{})}},
+		{{Key: "$facet", Value: bson.M{
+			"statuses": bson.A{bson.M{"$group": bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}}},
+			"created":  bson.A{bson.M{"$match": bson.M{"created_at": inWindow}}, countBuckets("created_at")},
+			"completed": bson.A{
+				bson.M{"$match": bson.M{"status": "DONE", "completed_at": inWindow}},
+				countBuckets("completed_at"),
+			},
+			"oldest": bson.A{
+				bson.M{"$match": bson.M{"status": bson.M{"$ne": "DONE"}}},
+				bson.M{"$sort": bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}},
+				bson.M{"$limit": q.OldestOpen},
+			},
+		}}},
+	}
+	var facets []struct {
+		Statuses  []countByKey `bson:"statuses"`
+		Created   []countByKey `bson:"created"`
+		Completed []countByKey `bson:"completed"`
+		Oldest    []mongoTask  `bson:"oldest"`
+	}
+	if err := r.aggregate(ctx, pipeline, &facets); err != nil {
+		logDBError(ctx, "failed to aggregate task stats", err)
+		return nil, fmt.Errorf("failed to aggregate task stats: %v", err)
+	}
+
+	stats := &domain.TaskStats{StatusCounts: make(map[string]int64), Buckets: q.EmptyBuckets()}
+	if len(facets) == 0 {
+		return stats, nil
+	}
+	f := facets[0]
+	for _, c := range f.Statuses {
+		status, _ := c.Key.(string)
+		stats.StatusCounts[status] = c.Count
+	}
+	for _, c := range f.Created {
+		if i, ok := bucketIndex(q, c); ok {
+			stats.Buckets[i].Created += c.Count
+		}
+	}
+	var done int64
+	for _, c := range f.Completed {
+		if i, ok := bucketIndex(q, c); ok {
+			stats.Buckets[i].Completed += c.Count
+			done += c.Count
+		}
+	}
+	for _, mt := range f.Oldest {
+		stats.OldestOpen = append(stats.OldestOpen, mt.toDomain())
+	}
+
+	if done == 0 {
+		return stats, nil
+	}
+	pipeline = mongo.Pipeline{
+		{{Key: "$match", Value: completed}},
+		{{Key: "$project", Value: bson.M{"d": bson.M{"$subtract": bson.A{"$completed_at", "$created_at"}}}}},
+		{{Key: "$sort", Value: bson.M{"d": 1}}},
+		{{Key: "$skip", Value: (done - 1) / 2}},
+		{{Key: "$limit", Value: 2 - done%2}},
+	}
+	var middle []struct {
+		D int64 `bson:"d"`
+	}
+	if err := r.aggregate(ctx, pipeline, &middle); err != nil {
+		logDBError(ctx, "failed to aggregate time to done", err)
+		return nil, fmt.Errorf("failed to aggregate time to done: %v", err)
+	}
+	durations := make([]int64, len(middle))
+	for i, m := range middle {
+		durations[i] = m.D
+	}
+	stats.MedianTimeToDone = domain.Median(durations)
+	return stats, nil
+}
+
+// bucketIndex returns the bucket of a count grouped by bucket offset.
+func bucketIndex(q domain.StatsQuery, c countByKey) (int, bool) {
+	offset, ok := c.Key.(int64)
+	if !ok {
+		return 0, false
+	}
+	return q.BucketOf(q.Start + offset)
+}
+
+func (r *mongoRepository) aggregate(ctx context.Context, pipeline mongo.Pipeline, results any) error {
+	cursor, err := r.collection.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return err
+	}
+	return cursor.All(ctx, results)
+}
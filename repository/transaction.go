@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// SupportsTransactions reports whether client is connected to a replica set
+// or a sharded cluster. Standalone servers reject multi-document
+// transactions.
+func SupportsTransactions(ctx context.Context, client *mongo.Client) (bool, error) {
+	var hello bson.M
+	err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
+	if err != nil {
+		return false, err
+	}
+
+	_, replicaSet := hello["setName"]
+	return replicaSet || hello["msg"] == "isdbgrid", nil
+}
+
+// supportsTransactions is SupportsTransactions for the repository's client.
+// A successful answer is cached for the lifetime of the repository.
+func (r *mongoRepository) supportsTransactions(ctx context.Context) bool {
+	r.txMu.Lock()
+	defer r.txMu.Unlock()
+
+	if r.txChecked {
+		return r.txSupported
+	}
+
+	supported, err := SupportsTransactions(ctx, r.collection.Database().Client())
+	if err != nil {
+		logDBError(ctx, "failed to detect transaction support", err)
+		return false
+	}
+	r.txSupported, r.txChecked = supported, true
+	return r.txSupported
+}
+
+// inTransaction runs fn in a multi-document transaction. fn must use the
+// context it is given so that its operations join the session.
+func (r *mongoRepository) inTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := r.collection.Database().Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	})
+	return err
+}
+
+// atomically runs fn in a transaction where the deployment supports them and
+// directly elsewhere.
+func (r *mongoRepository) atomically(ctx context.Context, fn func(ctx context.Context) error) error {
+	if r.supportsTransactions(ctx) {
+		return r.inTransaction(ctx, fn)
+	}
+	return fn(ctx)
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"grpc-todo/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// live restricts filter to tasks that are not in the trash.
+func live(filter bson.M) bson.M {
+	filter["deleted_at"] = bson.M{"$exists": false}
+	return filter
+}
+
+func trashed(filter bson.M) bson.M {
+	filter["deleted_at"] = bson.M{"$exists": true}
+	return filter
+}
+
+// trashUpdate moves a task to the trash.
+func trashUpdate(deletedBy string, now int64) bson.M {
+	return bson.M{"$set": bson.M{"deleted_at": now, "deleted_by": deletedBy}}
+}
+
+func (r *mongoRepository) ListTrash(ctx context.Context, pageSize int, pageToken string) ([]*domain.Task, string, error) {
+	tasks, next, err := listPage(ctx, r.collection, trashed(bson.M{}), pageSize, pageToken)
+	if err != nil && !errors.Is(err, ErrInvalidPageToken) {
+		logDBError(ctx, "failed to find deleted tasks", err)
+		return nil, "", fmt.Errorf("failed to find deleted tasks: %v", err)
+	}
+	return tasks, next, err
+}
+
+func (r *mongoRepository) GetDeletedTask(ctx context.Context, id string) (*domain.Task, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidID, err)
+	}
+
+	var mt mongoTask
+	err = r.collection.FindOne(ctx, trashed(bson.M{"_id": objectID})).Decode(&mt)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("deleted task with ID %s: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		logDBError(ctx, "failed to find deleted task", err, slog.String("task_id", id))
+		return nil, fmt.Errorf("failed to find deleted task: %v", err)
+	}
+
+	return mt.toDomain(), nil
+}
+
+func (r *mongoRepository) UndeleteTask(ctx context.Context, id string) (*domain.Task, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidID, err)
+	}
+
+	filter := trashed(bson.M{"_id": objectID})
+	update := bson.M{"$unset": bson.M{"deleted_at": "", "deleted_by": ""}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var task *domain.Task
+	err = r.mutate(ctx, func(ctx context.Context) error {
+		var mt mongoTask
+		if err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&mt); err != nil {
+			return err
+		}
+		task = mt.toDomain()
+		return r.record(ctx, domain.EventTaskUndeleted, task)
+	})
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("deleted task with ID %s: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		logDBError(ctx, "failed to undelete task", err, slog.String("task_id", id))
+		return nil, fmt.Errorf("failed to undelete task: %v", err)
+	}
+
+	return task, nil
+}
+
+func (r *mongoRepository) PurgeTask(ctx context.Context, id string) (*domain.Task, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidID, err)
+	}
+
+	var task *domain.Task
+	err = r.mutate(ctx, func(ctx context.Context) error {
+		var mt mongoTask
+		if err := r.collection.FindOneAndDelete(ctx, trashed(bson.M{"_id": objectID})).Decode(&mt); err != nil {
+			return err
+		}
+		task = mt.toDomain()
+		if err := r.forgetHistory(ctx, mt.ID); err != nil {
+			return err
+		}
+		return r.record(ctx, domain.EventTaskPurged, task)
+	})
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("deleted task with ID %s: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		logDBError(ctx, "failed to purge task", err, slog.String("task_id", id))
+		return nil, fmt.Errorf("failed to purge task: %v", err)
+	}
+
+	return task, nil
+}
@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"grpc-todo/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const viewsCollection = "saved_views"
+
+// ViewRepository stores saved views. Names are unique per owner; creating
+// or renaming a view to a name its owner already uses fails with
+// ErrAlreadyExists.
+type ViewRepository interface {
+	CreateView(ctx context.Context, view *domain.SavedView) (*domain.SavedView, error)
+	GetView(ctx context.Context, id string) (*domain.SavedView, error)
+	// ListViews returns the owner's views and the views others share, by
+	// name.
+	ListViews(ctx context.Context, owner string) ([]*domain.SavedView, error)
+	// UpdateView replaces the name, filter, order and shared flag of the
+	// owner's view and returns it.
+	UpdateView(ctx context.Context, view *domain.SavedView) (*domain.SavedView, error)
+	DeleteView(ctx context.Context, owner string, id string) error
+}
+
+type mongoView struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty"`
+	Owner         string             `bson:"owner"`
+	Name          string             `bson:"name"`
+	Filter        string             `bson:"filter"`
+	OrderBy       string             `bson:"order_by"`
+	FilterVersion int                `bson:"filter_version"`
+	Shared        bool               `bson:"shared"`
+	CreatedAt     int64              `bson:"created_at"`
+	UpdatedAt     int64              `bson:"updated_at"`
+}
+
+func (mv mongoView) toDomain() *domain.SavedView {
+	return &domain.SavedView{
+		Id:            mv.ID.Hex(),
+		Owner:         mv.Owner,
+		Name:          mv.Name,
+		Filter:        mv.Filter,
+		OrderBy:       mv.OrderBy,
+		FilterVersion: mv.FilterVersion,
+		Shared:        mv.Shared,
+		CreatedAt:     mv.CreatedAt,
+		UpdatedAt:     mv.UpdatedAt,
+	}
+}
+
+type mongoViewRepository struct {
+	views *mongo.Collection
+}
+
+// NewViewRepository returns the saved views and makes sure their names are
+// unique per owner.
+func NewViewRepository(ctx context.Context, db *mongo.Database) (ViewRepository, error) {
+	r := &mongoViewRepository{views: db.Collection(viewsCollection)}
+	if _, err := r.views.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "owner", Value: 1}, {Key: "name", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "shared", Value: 1}, {Key: "name", Value: 1}}},
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create saved view index: %v", err)
+	}
+	return r, nil
+}
+
+func (r *mongoViewRepository) CreateView(ctx context.Context, view *domain.SavedView) (*domain.SavedView, error) {
+	doc := mongoView{
+		Owner:         view.Owner,
+		Name:          view.Name,
+		Filter:        view.Filter,
+		OrderBy:       view.OrderBy,
+		FilterVersion: view.FilterVersion,
+		Shared:        view.Shared,
+		CreatedAt:     view.CreatedAt,
+		UpdatedAt:     view.UpdatedAt,
+	}
+
+	result, err := r.views.InsertOne(ctx, doc)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, fmt.Errorf("saved view named %q: %w", view.Name, ErrAlreadyExists)
+	}
+	if err != nil {
+		logDBError(ctx, "failed to insert saved view", err)
+		return nil, fmt.Errorf("failed to insert saved view: %v", err)
+	}
+
+	insertedID, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, fmt.Errorf("failed to get inserted ID")
+	}
+
+	view.Id = insertedID.Hex()
+	return view, nil
+}
+
+func (r *mongoViewRepository) GetView(ctx context.Context, id string) (*domain.SavedView, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidID, err)
+	}
+
+	var mv mongoView
+	err = r.views.FindOne(ctx, bson.M{"_id": objectID}).Decode(&mv)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("saved view with ID %s: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		logDBError(ctx, "failed to find saved view", err, slog.String("view_id", id))
+		return nil, fmt.Errorf("failed to find saved view: %v", err)
+	}
+
+	return mv.toDomain(), nil
+}
+
+func (r *mongoViewRepository) ListViews(ctx context.Context, owner string) ([]*domain.SavedView, error) {
+	filter := bson.M{"$or": bson.A{bson.M{"owner": owner}, bson.M{"shared": true}}}
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.views.Find(ctx, filter, opts)
+	if err != nil {
+		logDBError(ctx, "failed to find saved views", err)
+		return nil, fmt.Errorf("failed to find saved views: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var views []*domain.SavedView
+	for cursor.Next(ctx) {
+		var mv mongoView
+		if err := cursor.Decode(&mv); err != nil {
+			logDBError(ctx, "failed to decode saved view", err)
+			return nil, fmt.Errorf("failed to decode saved view: %v", err)
+		}
+		views = append(views, mv.toDomain())
+	}
+
+	if err := cursor.Err(); err != nil {
+		logDBError(ctx, "cursor error", err)
+		return nil, fmt.Errorf("cursor error: %v", err)
+	}
+
+	return views, nil
+}
+
+func (r *mongoViewRepository) UpdateView(ctx context.Context, view *domain.SavedView) (*domain.SavedView, error) {
+	objectID, err := primitive.ObjectIDFromHex(view.Id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidID, err)
+	}
+
+	update := bson.M{"$set": bson.M{
+		"name":           view.Name,
+		"filter":         view.Filter,
+		"order_by":       view.OrderBy,
+		"filter_version": view.FilterVersion,
+		"shared":         view.Shared,
+		"updated_at":     view.UpdatedAt,
+	}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var mv mongoView
+	err = r.views.FindOneAndUpdate(ctx, bson.M{"_id": objectID, "owner": view.Owner}, update, opts).Decode(&mv)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("saved view with ID %s: %w", view.Id, ErrNotFound)
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, fmt.Errorf("saved view named %q: %w", view.Name, ErrAlreadyExists)
+	}
+	if err != nil {
+		logDBError(ctx, "failed to update saved view", err, slog.String("view_id", view.Id))
+		return nil, fmt.Errorf("failed to update saved view: %v", err)
+	}
+
+	return mv.toDomain(), nil
+}
+
+func (r *mongoViewRepository) DeleteView(ctx context.Context, owner string, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidID, err)
+	}
+
+	result, err := r.views.DeleteOne(ctx, bson.M{"_id": objectID, "owner": owner})
+	if err != nil {
+		logDBError(ctx, "failed to delete saved view", err, slog.String("view_id", id))
+		return fmt.Errorf("failed to delete saved view: %v", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("saved view with ID %s: %w", id, ErrNotFound)
+	}
+	return nil
+}
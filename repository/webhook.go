@@ -0,0 +1,323 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"grpc-todo/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WebhookRepository stores webhook subscriptions and the outbox of
+// deliveries waiting to be posted to them.
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error)
+	ListWebhooks(ctx context.Context, owner string) ([]*domain.Webhook, error)
+	GetWebhook(ctx context.Context, id string) (*domain.Webhook, error)
+	// DeleteWebhook removes the owner's webhook and drops its pending
+	// deliveries.
+	DeleteWebhook(ctx context.Context, owner string, id string) error
+	// MatchWebhooks returns the owner's webhooks subscribed to event.
+	MatchWebhooks(ctx context.Context, owner string, event string) ([]*domain.Webhook, error)
+
+	// EnqueueDeliveries stores deliveries. Those with an Id already set are
+	// stored under it, and skipped if a delivery with that Id is queued.
+	EnqueueDeliveries(ctx context.Context, deliveries []*domain.WebhookDelivery) error
+	// ClaimDeliveries returns up to limit live deliveries that are due at now
+	// and hides them from other callers for lease, so that several servers
+	// can share one outbox.
+	ClaimDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.WebhookDelivery, error)
+	// CompleteDelivery removes a delivered entry from the outbox.
+	CompleteDelivery(ctx context.Context, id string) error
+	// FailDelivery records a failed attempt: the attempt count, last error,
+	// next attempt time and dead flag of delivery.
+	FailDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error
+}
+
+type mongoWebhook struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	Owner     string             `bson:"owner"`
+	URL       string             `bson:"url"`
+	Secret    string             `bson:"secret"`
+	Events    []string           `bson:"events"`
+	CreatedAt int64              `bson:"created_at"`
+}
+
+func (mw mongoWebhook) toDomain() *domain.Webhook {
+	return &domain.Webhook{
+		Id:        mw.ID.Hex(),
+		Owner:     mw.Owner,
+		URL:       mw.URL,
+		Secret:    mw.Secret,
+		Events:    mw.Events,
+		CreatedAt: mw.CreatedAt,
+	}
+}
+
+type mongoDelivery struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty"`
+	WebhookID     primitive.ObjectID `bson:"webhook_id"`
+	Event         string             `bson:"event"`
+	Data          string             `bson:"data"`
+	OccurredAt    int64              `bson:"occurred_at"`
+	Attempts      int                `bson:"attempts"`
+	NextAttemptAt time.Time          `bson:"next_attempt_at"`
+	LastError     string             `bson:"last_error,omitempty"`
+	Dead          bool               `bson:"dead"`
+}
+
+func (md mongoDelivery) toDomain() *domain.WebhookDelivery {
+	return &domain.WebhookDelivery{
+		Id:            md.ID.Hex(),
+		WebhookId:     md.WebhookID.Hex(),
+		Event:         md.Event,
+		Data:          []byte(md.Data),
+		OccurredAt:    md.OccurredAt,
+		Attempts:      md.Attempts,
+		NextAttemptAt: md.NextAttemptAt,
+		LastError:     md.LastError,
+		Dead:          md.Dead,
+	}
+}
+
+type mongoWebhookRepository struct {
+	webhooks   *mongo.Collection
+	deliveries *mongo.Collection
+}
+
+func NewWebhookRepository(db *mongo.Database) WebhookRepository {
+	return &mongoWebhookRepository{
+		webhooks:   db.Collection("webhooks"),
+		deliveries: db.Collection("webhook_deliveries"),
+	}
+}
+
+func (r *mongoWebhookRepository) CreateWebhook(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error) {
+	events := webhook.Events
+	if events == nil {
+		events = []string{}
+	}
+	doc := mongoWebhook{
+		Owner:     webhook.Owner,
+		URL:       webhook.URL,
+		Secret:    webhook.Secret,
+		Events:    events,
+		CreatedAt: webhook.CreatedAt,
+	}
+
+	result, err := r.webhooks.InsertOne(ctx, doc)
+	if err != nil {
+		logDBError(ctx, "failed to insert webhook", err)
+		return nil, fmt.Errorf("failed to insert webhook: %v", err)
+	}
+
+	insertedID, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, fmt.Errorf("failed to get inserted ID")
+	}
+
+	webhook.Id = insertedID.Hex()
+	return webhook, nil
+}
+
+func (r *mongoWebhookRepository) ListWebhooks(ctx context.Context, owner string) ([]*domain.Webhook, error) {
+	return r.findWebhooks(ctx, bson.M{"owner": owner})
+}
+
+func (r *mongoWebhookRepository) MatchWebhooks(ctx context.Context, owner string, event string) ([]*domain.Webhook, error) {
+	return r.findWebhooks(ctx, bson.M{
+		"owner": owner,
+		"$or":   bson.A{bson.M{"events": bson.M{"$size": 0}}, bson.M{"events": event}},
+	})
+}
+
+func (r *mongoWebhookRepository) findWebhooks(ctx context.Context, filter bson.M) ([]*domain.Webhook, error) {
+	cursor, err := r.webhooks.Find(ctx, filter, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		logDBError(ctx, "failed to find webhooks", err)
+		return nil, fmt.Errorf("failed to find webhooks: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var webhooks []*domain.Webhook
+	for cursor.Next(ctx) {
+		var mw mongoWebhook
+		if err := cursor.Decode(&mw); err != nil {
+			logDBError(ctx, "failed to decode webhook", err)
+			return nil, fmt.Errorf("failed to decode webhook: %v", err)
+		}
+		webhooks = append(webhooks, mw.toDomain())
+	}
+
+	if err := cursor.Err(); err != nil {
+		logDBError(ctx, "cursor error", err)
+		return nil, fmt.Errorf("cursor error: %v", err)
+	}
+
+	return webhooks, nil
+}
+
+func (r *mongoWebhookRepository) GetWebhook(ctx context.Context, id string) (*domain.Webhook, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidID, err)
+	}
+
+	var mw mongoWebhook
+	err = r.webhooks.FindOne(ctx, bson.M{"_id": objectID}).Decode(&mw)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("webhook with ID %s: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		logDBError(ctx, "failed to find webhook", err, slog.String("webhook_id", id))
+		return nil, fmt.Errorf("failed to find webhook: %v", err)
+	}
+
+	return mw.toDomain(), nil
+}
+
+func (r *mongoWebhookRepository) DeleteWebhook(ctx context.Context, owner string, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidID, err)
+	}
+
+	result, err := r.webhooks.DeleteOne(ctx, bson.M{"_id": objectID, "owner": owner})
+	if err != nil {
+		logDBError(ctx, "failed to delete webhook", err, slog.String("webhook_id", id))
+		return fmt.Errorf("failed to delete webhook: %v", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("webhook with ID %s: %w", id, ErrNotFound)
+	}
+
+	// Deliveries left behind are dropped by the dispatcher when it finds
+	// their webhook gone, so a failure here is not worth failing the call.
+	if _, err := r.deliveries.DeleteMany(ctx, bson.M{"webhook_id": objectID, "dead": false}); err != nil {
+		logDBError(ctx, "failed to delete webhook deliveries", err, slog.String("webhook_id", id))
+	}
+
+	return nil
+}
+
+func (r *mongoWebhookRepository) EnqueueDeliveries(ctx context.Context, deliveries []*domain.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, 0, len(deliveries))
+	for _, d := range deliveries {
+		webhookID, err := primitive.ObjectIDFromHex(d.WebhookId)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidID, err)
+		}
+		doc := mongoDelivery{
+			WebhookID:     webhookID,
+			Event:         d.Event,
+			Data:          string(d.Data),
+			OccurredAt:    d.OccurredAt,
+			NextAttemptAt: d.NextAttemptAt,
+		}
+		if d.Id != "" {
+			if doc.ID, err = primitive.ObjectIDFromHex(d.Id); err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidID, err)
+			}
+		}
+		docs = append(docs, doc)
+	}
+
+	result, err := r.deliveries.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	if err != nil && !onlyDuplicateKeys(err) {
+		logDBError(ctx, "failed to insert webhook deliveries", err)
+		return fmt.Errorf("failed to insert webhook deliveries: %v", err)
+	}
+	if err == nil {
+		for i, id := range result.InsertedIDs {
+			if oid, ok := id.(primitive.ObjectID); ok {
+				deliveries[i].Id = oid.Hex()
+			}
+		}
+	}
+
+	return nil
+}
+
+// onlyDuplicateKeys reports whether every write in a failed insert was
+// refused because its ID is already stored.
+func onlyDuplicateKeys(err error) bool {
+	var bwe mongo.BulkWriteException
+	if !errors.As(err, &bwe) || bwe.WriteConcernError != nil || len(bwe.WriteErrors) == 0 {
+		return false
+	}
+	for _, we := range bwe.WriteErrors {
+		if we.Code != 11000 {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *mongoWebhookRepository) ClaimDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.WebhookDelivery, error) {
+	filter := bson.M{"dead": false, "next_attempt_at": bson.M{"$lte": now}}
+	update := bson.M{"$set": bson.M{"next_attempt_at": now.Add(lease)}}
+	opts := options.FindOneAndUpdate().SetSort(bson.M{"next_attempt_at": 1})
+
+	var deliveries []*domain.WebhookDelivery
+	for len(deliveries) < limit {
+		var md mongoDelivery
+		err := r.deliveries.FindOneAndUpdate(ctx, filter, update, opts).Decode(&md)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		if err != nil {
+			logDBError(ctx, "failed to claim webhook delivery", err)
+			return deliveries, fmt.Errorf("failed to claim webhook delivery: %v", err)
+		}
+		deliveries = append(deliveries, md.toDomain())
+	}
+
+	return deliveries, nil
+}
+
+func (r *mongoWebhookRepository) CompleteDelivery(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidID, err)
+	}
+
+	if _, err := r.deliveries.DeleteOne(ctx, bson.M{"_id": objectID}); err != nil {
+		logDBError(ctx, "failed to delete webhook delivery", err, slog.String("delivery_id", id))
+		return fmt.Errorf("failed to delete webhook delivery: %v", err)
+	}
+	return nil
+}
+
+func (r *mongoWebhookRepository) FailDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	objectID, err := primitive.ObjectIDFromHex(delivery.Id)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidID, err)
+	}
+
+	update := bson.M{"$set": bson.M{
+		"attempts":        delivery.Attempts,
+		"next_attempt_at": delivery.NextAttemptAt,
+		"last_error":      delivery.LastError,
+		"dead":            delivery.Dead,
+	}}
+	result, err := r.deliveries.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	if err != nil {
+		logDBError(ctx, "failed to update webhook delivery", err, slog.String("delivery_id", delivery.Id))
+		return fmt.Errorf("failed to update webhook delivery: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("webhook delivery with ID %s: %w", delivery.Id, ErrNotFound)
+	}
+	return nil
+}
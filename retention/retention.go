@@ -0,0 +1,212 @@
+// Package retention removes tasks that have stayed in a status for longer
+// than a policy allows, either by deleting them or by moving them to the
+// archive, and empties the trash.
+package retention
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"grpc-todo/domain"
+	"grpc-todo/repository"
+)
+
+type Action string
+
+const (
+	Delete  Action = "delete"
+	Archive Action = "archive"
+	// Purge deletes tasks in the trash for good. It is the only action of
+	// the Trash policy.
+	Purge Action = "purge"
+)
+
+// Trash is the status of the policy that empties the trash.
+const Trash = "TRASH"
+
+var statuses = []string{"TODO", "IN_PROGRESS", "PAUSED", "DONE", Trash}
+
+// Policy removes tasks that have been in Status for longer than After, or,
+// for the Trash policy, in the trash for longer than After.
+type Policy struct {
+	Status string
+	Action Action
+	After  time.Duration
+}
+
+// String returns the policy in the form ParsePolicies reads.
+func (p Policy) String() string {
+	after := p.After.String()
+	if p.After%(24*time.Hour) == 0 {
+		after = strconv.FormatInt(int64(p.After/(24*time.Hour)), 10) + "d"
+	}
+	return p.Status + ":" + string(p.Action) + ":" + after
+}
+
+// ParsePolicies reads a comma-separated list of status:action:age policies,
+// e.g. "DONE:delete:30d,PAUSED:archive:90d,TRASH:purge:30d". Ages are Go
+// durations or a number of days followed by "d". A status may have one
+// policy.
+func ParsePolicies(raw string) ([]Policy, error) {
+	var policies []Policy
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid retention policy %q, expected status:action:age", entry)
+		}
+
+		status := strings.ToUpper(strings.ReplaceAll(parts[0], "-", "_"))
+		if !slices.Contains(statuses, status) {
+			return nil, fmt.Errorf("invalid retention policy %q: unknown status %q", entry, parts[0])
+		}
+		if seen[status] {
+			return nil, fmt.Errorf("invalid retention policy %q: %s already has a policy", entry, status)
+		}
+		seen[status] = true
+
+		action := Action(strings.ToLower(parts[1]))
+		switch {
+		case status == Trash && action != Purge:
+			return nil, fmt.Errorf("invalid retention policy %q: the trash can only be purged", entry)
+		case status != Trash && action != Delete && action != Archive:
+			return nil, fmt.Errorf("invalid retention policy %q: action must be delete or archive", entry)
+		}
+
+		after, err := ParseAge(parts[2])
+		if err != nil || after <= 0 {
+			return nil, fmt.Errorf("invalid retention policy %q: invalid age %q", entry, parts[2])
+		}
+
+		policies = append(policies, Policy{Status: status, Action: action, After: after})
+	}
+	return policies, nil
+}
+
+// ParseAge reads a Go duration or a number of days followed by "d".
+func ParseAge(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		return time.Duration(n) * 24 * time.Hour, err
+	}
+	return time.ParseDuration(s)
+}
+
+// Result lists the tasks one policy removed, or in a dry run would have
+// removed.
+type Result struct {
+	Policy Policy
+	Tasks  []*domain.Task
+}
+
+type Engine struct {
+	repo     repository.Repository
+	policies []Policy
+
+	dryRun    bool
+	batchSize int
+	notify    func(context.Context, Policy, *domain.Task)
+	now       func() time.Time
+}
+
+type Option func(*Engine)
+
+// WithDryRun makes Run report what it would remove without removing it.
+func WithDryRun(dryRun bool) Option {
+	return func(e *Engine) {
+		e.dryRun = dryRun
+	}
+}
+
+// WithBatchSize sets how many tasks are removed per transaction.
+func WithBatchSize(n int) Option {
+	return func(e *Engine) {
+		if n > 0 {
+			e.batchSize = n
+		}
+	}
+}
+
+// WithNotify calls fn for every task Run removes.
+func WithNotify(fn func(context.Context, Policy, *domain.Task)) Option {
+	return func(e *Engine) {
+		e.notify = fn
+	}
+}
+
+func NewEngine(repo repository.Repository, policies []Policy, opts ...Option) *Engine {
+	e := &Engine{
+		repo:      repo,
+		policies:  policies,
+		batchSize: 100,
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+func (e *Engine) Policies() []Policy {
+	return e.policies
+}
+
+func (e *Engine) DryRun() bool {
+	return e.dryRun
+}
+
+// Run applies every policy once and returns what each one removed. Tasks
+// are removed in batches; when a batch fails, Run returns what it removed
+// before.
+func (e *Engine) Run(ctx context.Context) ([]Result, error) {
+	results := make([]Result, 0, len(e.policies))
+	for _, p := range e.policies {
+		filter := repository.AgeFilter{Status: p.Status, Before: e.now().Add(-p.After).Unix()}
+		if p.Status == Trash {
+			filter = repository.AgeFilter{Trashed: true, Before: filter.Before}
+		}
+
+		if e.dryRun {
+			tasks, err := e.repo.FindTasksByAge(ctx, filter)
+			if err != nil {
+				return results, err
+			}
+			results = append(results, Result{Policy: p, Tasks: tasks})
+			continue
+		}
+
+		remove := e.repo.DeleteTasksByAge
+		if p.Action == Archive {
+			remove = e.repo.ArchiveTasksByAge
+		}
+		filter.Limit = e.batchSize
+
+		res := Result{Policy: p}
+		for {
+			tasks, err := remove(ctx, filter)
+			for _, t := range tasks {
+				if e.notify != nil {
+					e.notify(ctx, p, t)
+				}
+			}
+			res.Tasks = append(res.Tasks, tasks...)
+			if err != nil {
+				return append(results, res), err
+			}
+			if len(tasks) < e.batchSize {
+				break
+			}
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
@@ -0,0 +1,178 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"grpc-todo/domain"
+	"grpc-todo/repository"
+)
+
+// ageRepository implements the age queries over tasks that entered their
+// status at CreatedAt, or the trash at DeletedAt. Other Repository methods
+// are not used by the engine.
+type ageRepository struct {
+	repository.Repository
+	tasks    []*domain.Task
+	archived []*domain.Task
+	calls    int
+	failAt   int
+}
+
+func (r *ageRepository) FindTasksByAge(ctx context.Context, f repository.AgeFilter) ([]*domain.Task, error) {
+	var res []*domain.Task
+	for _, t := range r.tasks {
+		match := t.DeletedAt == 0 && t.Status == f.Status && t.CreatedAt <= f.Before
+		if f.Trashed {
+			match = t.DeletedAt != 0 && t.DeletedAt <= f.Before
+		}
+		if match && (f.Limit == 0 || len(res) < f.Limit) {
+			res = append(res, t)
+		}
+	}
+	return res, nil
+}
+
+func (r *ageRepository) DeleteTasksByAge(ctx context.Context, f repository.AgeFilter) ([]*domain.Task, error) {
+	r.calls++
+	if r.calls == r.failAt {
+		return nil, errors.New("transaction aborted")
+	}
+	removed, _ := r.FindTasksByAge(ctx, f)
+	var kept []*domain.Task
+	for _, t := range r.tasks {
+		if !containsTask(removed, t) {
+			kept = append(kept, t)
+		}
+	}
+	r.tasks = kept
+	return removed, nil
+}
+
+func (r *ageRepository) ArchiveTasksByAge(ctx context.Context, f repository.AgeFilter) ([]*domain.Task, error) {
+	removed, err := r.DeleteTasksByAge(ctx, f)
+	r.archived = append(r.archived, removed...)
+	return removed, err
+}
+
+func containsTask(tasks []*domain.Task, t *domain.Task) bool {
+	for _, v := range tasks {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
+
+func newAgeRepository(now time.Time) *ageRepository {
+	days := func(n int) int64 { return now.Add(-time.Duration(n) * 24 * time.Hour).Unix() }
+	r := &ageRepository{}
+	for i := 0; i < 5; i++ {
+		r.tasks = append(r.tasks, &domain.Task{Id: fmt.Sprint("done-old-", i), Status: "DONE", CreatedAt: days(40)})
+	}
+	r.tasks = append(r.tasks,
+		&domain.Task{Id: "done-new", Status: "DONE", CreatedAt: days(2)},
+		&domain.Task{Id: "paused-old", Status: "PAUSED", CreatedAt: days(100)},
+		&domain.Task{Id: "paused-new", Status: "PAUSED", CreatedAt: days(10)},
+		&domain.Task{Id: "todo-old", Status: "TODO", CreatedAt: days(400)},
+		&domain.Task{Id: "trashed-old", Status: "DONE", CreatedAt: days(400), DeletedAt: days(40)},
+		&domain.Task{Id: "trashed-new", Status: "DONE", CreatedAt: days(400), DeletedAt: days(1)},
+	)
+	return r
+}
+
+func TestParsePolicies(t *testing.T) {
+	policies, err := ParsePolicies("done:delete:30d, PAUSED:archive:2160h,in-progress:archive:1d,trash:purge:36h")
+	if err != nil {
+		t.Fatalf("ParsePolicies failed: %v", err)
+	}
+	want := []string{"DONE:delete:30d", "PAUSED:archive:90d", "IN_PROGRESS:archive:1d", "TRASH:purge:36h0m0s"}
+	if len(policies) != len(want) {
+		t.Fatalf("Expected %d policies, got %v", len(want), policies)
+	}
+	for i, p := range policies {
+		if p.String() != want[i] {
+			t.Errorf("Expected %s, got %s", want[i], p)
+		}
+	}
+
+	for _, raw := range []string{"DONE:delete", "LATER:delete:1d", "DONE:shred:1d", "DONE:delete:soon", "DONE:delete:0d", "DONE:delete:1d,DONE:archive:2d", "TRASH:delete:1d", "DONE:purge:1d"} {
+		if _, err := ParsePolicies(raw); err == nil {
+			t.Errorf("Expected an error for %q", raw)
+		}
+	}
+}
+
+func TestEngineRun(t *testing.T) {
+	now := time.Now()
+	repo := newAgeRepository(now)
+	policies, _ := ParsePolicies("DONE:delete:30d,PAUSED:archive:90d,TRASH:purge:30d")
+
+	var notified int
+	e := NewEngine(repo, policies, WithBatchSize(2), WithNotify(func(context.Context, Policy, *domain.Task) { notified++ }))
+	e.now = func() time.Time { return now }
+
+	results, err := e.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(results) != 3 || len(results[0].Tasks) != 5 || len(results[1].Tasks) != 1 || len(results[2].Tasks) != 1 {
+		t.Fatalf("Expected 5 deleted, 1 archived and 1 purged task, got %+v", results)
+	}
+	if repo.calls != 5 {
+		t.Errorf("Expected 3 delete batches, 1 archive batch and 1 purge batch, got %d calls", repo.calls)
+	}
+	if len(repo.archived) != 1 || repo.archived[0].Id != "paused-old" {
+		t.Errorf("Expected paused-old to be archived, got %v", repo.archived)
+	}
+	if notified != 7 {
+		t.Errorf("Expected 7 notifications, got %d", notified)
+	}
+
+	var left []string
+	for _, task := range repo.tasks {
+		left = append(left, task.Id)
+	}
+	if fmt.Sprint(left) != "[done-new paused-new todo-old trashed-new]" {
+		t.Errorf("Unexpected remaining tasks %v", left)
+	}
+}
+
+func TestEngineDryRun(t *testing.T) {
+	now := time.Now()
+	repo := newAgeRepository(now)
+	policies, _ := ParsePolicies("DONE:delete:30d")
+	e := NewEngine(repo, policies, WithDryRun(true))
+	e.now = func() time.Time { return now }
+
+	results, err := e.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(results) != 1 || len(results[0].Tasks) != 5 {
+		t.Errorf("Expected 5 tasks to be reported, got %+v", results)
+	}
+	if repo.calls != 0 || len(repo.tasks) != 11 {
+		t.Errorf("Expected nothing to be removed, got %d calls and %d tasks", repo.calls, len(repo.tasks))
+	}
+}
+
+func TestEngineStopsOnError(t *testing.T) {
+	now := time.Now()
+	repo := newAgeRepository(now)
+	repo.failAt = 2
+	policies, _ := ParsePolicies("DONE:delete:30d,PAUSED:archive:90d")
+	e := NewEngine(repo, policies, WithBatchSize(2))
+	e.now = func() time.Time { return now }
+
+	results, err := e.Run(context.Background())
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	if len(results) != 1 || len(results[0].Tasks) != 2 {
+		t.Errorf("Expected the first batch to be reported, got %+v", results)
+	}
+}
@@ -0,0 +1,231 @@
+// Package search tokenizes, indexes and highlights task text. Its Index is
+// the full-text search of repositories that have no search engine of their
+// own; the Mongo repository uses a text index instead.
+package search
+
+import (
+	"cmp"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Field weights, the same as the Mongo text index's.
+const (
+	TitleWeight       = 3
+	DescriptionWeight = 1
+)
+
+// BM25 parameters.
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+// Range is a half-open range of a text in runes.
+type Range struct {
+	Start int
+	End   int
+}
+
+type token struct {
+	term string
+	Range
+}
+
+func tokens(text string) []token {
+	var res []token
+	start := -1
+	var term []rune
+	i := 0
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			term = append(term, unicode.ToLower(r))
+		} else if start >= 0 {
+			res = append(res, token{string(term), Range{start, i}})
+			start, term = -1, term[:0]
+		}
+		i++
+	}
+	if start >= 0 {
+		res = append(res, token{string(term), Range{start, i}})
+	}
+	return res
+}
+
+// Terms splits text into lower-case words.
+func Terms(text string) []string {
+	var terms []string
+	for _, t := range tokens(text) {
+		terms = append(terms, t.term)
+	}
+	return terms
+}
+
+// Hit is a document that matched a search and its relevance.
+type Hit struct {
+	ID    string
+	Score float64
+}
+
+type document struct {
+	// terms counts the weighted occurrences of each term and length all of
+	// them.
+	terms  map[string]int
+	length int
+}
+
+// Index is an in-memory inverted index of task titles and descriptions. It
+// ranks matches with BM25 over both fields, a title word counting as much
+// as TitleWeight description words. It is not safe for concurrent use.
+type Index struct {
+	docs     map[string]*document
+	postings map[string]map[string]bool
+	length   int
+}
+
+func NewIndex() *Index {
+	return &Index{
+		docs:     make(map[string]*document),
+		postings: make(map[string]map[string]bool),
+	}
+}
+
+// Add indexes a task, replacing what was indexed for it before.
+func (ix *Index) Add(id, title, description string) {
+	ix.Remove(id)
+
+	doc := &document{terms: make(map[string]int)}
+	for _, f := range []struct {
+		text   string
+		weight int
+	}{{title, TitleWeight}, {description, DescriptionWeight}} {
+		for _, term := range Terms(f.text) {
+			doc.terms[term] += f.weight
+			doc.length += f.weight
+		}
+	}
+
+	ix.docs[id] = doc
+	ix.length += doc.length
+	for term := range doc.terms {
+		if ix.postings[term] == nil {
+			ix.postings[term] = make(map[string]bool)
+		}
+		ix.postings[term][id] = true
+	}
+}
+
+func (ix *Index) Remove(id string) {
+	doc := ix.docs[id]
+	if doc == nil {
+		return
+	}
+	for term := range doc.terms {
+		delete(ix.postings[term], id)
+		if len(ix.postings[term]) == 0 {
+			delete(ix.postings, term)
+		}
+	}
+	ix.length -= doc.length
+	delete(ix.docs, id)
+}
+
+// Search returns the documents that contain any word of the query, most
+// relevant first.
+func (ix *Index) Search(query string) []Hit {
+	if len(ix.docs) == 0 {
+		return nil
+	}
+	n := float64(len(ix.docs))
+	avg := float64(ix.length) / n
+
+	scores := make(map[string]float64)
+	for _, term := range slices.Compact(slices.Sorted(slices.Values(Terms(query)))) {
+		ids := ix.postings[term]
+		idf := math.Log(1 + (n-float64(len(ids))+0.5)/(float64(len(ids))+0.5))
+		for id := range ids {
+			doc := ix.docs[id]
+			tf := float64(doc.terms[term])
+			scores[id] += idf * tf * (k1 + 1) / (tf + k1*(1-b+b*float64(doc.length)/avg))
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, Hit{ID: id, Score: score})
+	}
+	slices.SortFunc(hits, func(a, b Hit) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), strings.Compare(a.ID, b.ID))
+	})
+	return hits
+}
+
+// Snippet returns at most width runes of text around the first word that
+// matches a query term, with "…" where it was cut, and the ranges of the
+// matching words in it. A word matches a term it starts with, so that
+// "deploying" is highlighted for "deploy" as the Mongo text index's
+// stemming would match it. ok is false when no word matches.
+func Snippet(text string, terms []string, width int) (snippet string, highlights []Range, ok bool) {
+	var matches []Range
+	for _, t := range tokens(text) {
+		for _, term := range terms {
+			if term != "" && strings.HasPrefix(t.term, term) {
+				matches = append(matches, t.Range)
+				break
+			}
+		}
+	}
+	if len(matches) == 0 {
+		return "", nil, false
+	}
+
+	runes := []rune(text)
+	start, end := 0, len(runes)
+	if len(runes) > width {
+		// Start a little before the first match, or early enough to fill
+		// the width, on a word boundary.
+		start = max(min(matches[0].Start-width/4, len(runes)-width), 0)
+		for start > 0 && start < matches[0].Start && !unicode.IsSpace(runes[start-1]) {
+			start++
+		}
+		end = min(start+width, len(runes))
+	}
+
+	prefix, suffix := "", ""
+	if start > 0 {
+		prefix = "…"
+	}
+	if end < len(runes) {
+		suffix = "…"
+	}
+	offset := len([]rune(prefix)) - start
+	for _, m := range matches {
+		if m.Start >= start && m.End <= end {
+			highlights = append(highlights, Range{m.Start + offset, m.End + offset})
+		}
+	}
+	return prefix + string(runes[start:end]) + suffix, highlights, true
+}
+
+// HasTag reports whether text contains tag, a todo.txt +project or
+// @context token, ignoring case.
+func HasTag(text, tag string) bool {
+	for _, field := range strings.Fields(text) {
+		if strings.EqualFold(field, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// TagPattern is a regular expression that, matched without regard to case,
+// accepts the texts HasTag does.
+func TagPattern(tag string) string {
+	return `(^|\s)` + regexp.QuoteMeta(tag) + `(\s|$)`
+}
@@ -0,0 +1,66 @@
+package search
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTerms(t *testing.T) {
+	got := Terms("Deploy the API, v2 — Überprüfung!")
+	want := []string{"deploy", "the", "api", "v2", "überprüfung"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestIndexSearch(t *testing.T) {
+	ix := NewIndex()
+	ix.Add("1", "Deploy website", "Push the new build to production")
+	ix.Add("2", "Write report", "Include the deploy numbers")
+	ix.Add("3", "Buy milk", "")
+
+	hits := ix.Search("deploy")
+	if len(hits) != 2 || hits[0].ID != "1" || hits[1].ID != "2" {
+		t.Fatalf("Expected the title match to rank first, got %v", hits)
+	}
+	if hits[0].Score <= hits[1].Score {
+		t.Errorf("Expected a higher score for the title match, got %v", hits)
+	}
+
+	ix.Add("1", "Fix website", "")
+	if hits := ix.Search("deploy"); len(hits) != 1 || hits[0].ID != "2" {
+		t.Errorf("Expected re-indexing to drop the old title, got %v", hits)
+	}
+	ix.Remove("2")
+	if hits := ix.Search("deploy report"); len(hits) != 0 {
+		t.Errorf("Expected no hits after removal, got %v", hits)
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	text := "First we gather the requirements, then we talk to the team, and only after all of that we start deploying the service and tell everyone about it."
+	snippet, highlights, ok := Snippet(text, []string{"deploy"}, 40)
+	if !ok {
+		t.Fatal("Expected a match")
+	}
+	runes := []rune(snippet)
+	if runes[0] != '…' || runes[len(runes)-1] != '…' {
+		t.Errorf("Expected a cut snippet, got %q", snippet)
+	}
+	if len(highlights) != 1 || string(runes[highlights[0].Start:highlights[0].End]) != "deploying" {
+		t.Errorf("Expected deploying to be highlighted in %q, got %v", snippet, highlights)
+	}
+
+	if _, _, ok := Snippet("Buy milk", []string{"deploy"}, 40); ok {
+		t.Error("Expected no match")
+	}
+}
+
+func TestHasTag(t *testing.T) {
+	if !HasTag("Call mom +Family @phone", "+family") {
+		t.Error("Expected +family to match regardless of case")
+	}
+	if HasTag("Call mom +familyties", "+family") {
+		t.Error("Expected only whole tokens to match")
+	}
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+
+	"grpc-todo/domain"
+	"grpc-todo/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const maxPageSize = 1000
+
+// pageSize returns the page size a listing uses for a requested size: the
+// repository default when zero, and at most maxPageSize.
+func pageSize(method string, requested int32) (int, error) {
+	if requested < 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "%s failed: page_size must not be negative", method)
+	}
+	return int(min(requested, maxPageSize)), nil
+}
+
+// unarchived returns an archived task as it was before it was archived.
+func unarchived(task *domain.Task) *domain.Task {
+	t := *task
+	t.ArchivedAt = 0
+	return &t
+}
+
+func (s *ToDoServer) ArchiveTask(ctx context.Context, req *proto.ArchiveTaskRequest) (*proto.ArchiveTaskResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	task, err := s.repo.ArchiveTask(ctx, req.Id)
+	if err != nil {
+		return nil, toStatusError("ArchiveTask", err)
+	}
+	s.audit(ctx, "ArchiveTask", unarchived(task), task)
+
+	return &proto.ArchiveTaskResponse{Task: toProtoTask(task)}, nil
+}
+
+func (s *ToDoServer) ListArchivedTasks(ctx context.Context, req *proto.ListArchivedTasksRequest) (*proto.ListArchivedTasksResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	size, err := pageSize("ListArchivedTasks", req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, next, err := s.repo.ListArchivedTasks(ctx, size, req.PageToken)
+	if err != nil {
+		return nil, toStatusError("ListArchivedTasks", err)
+	}
+
+	res := &proto.ListArchivedTasksResponse{NextPageToken: next}
+	for _, t := range tasks {
+		res.Tasks = append(res.Tasks, toProtoTask(t))
+	}
+	return res, nil
+}
+
+// RestoreTask counts the restored task against its owner's quota, since
+// archived tasks do not use any.
+func (s *ToDoServer) RestoreTask(ctx context.Context, req *proto.RestoreTaskRequest) (*proto.RestoreTaskResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	archived, err := s.repo.GetArchivedTask(ctx, req.Id)
+	if err != nil {
+		return nil, toStatusError("RestoreTask", err)
+	}
+	release, err := s.checkTaskQuota(ctx, archived.Owner)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	task, err := s.repo.RestoreTask(ctx, req.Id)
+	if err != nil {
+		return nil, toStatusError("RestoreTask", err)
+	}
+	s.audit(ctx, "RestoreTask", archived, task)
+
+	return &proto.RestoreTaskResponse{Task: toProtoTask(task)}, nil
+}
@@ -0,0 +1,177 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"grpc-todo/auth"
+	"grpc-todo/domain"
+	"grpc-todo/logging"
+	"grpc-todo/proto"
+	"grpc-todo/repository"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// retentionActor is the principal retention policies act as.
+const retentionActor = "system:retention"
+
+// WithAudit records task changes in auditLog and enables ListAuditEntries.
+func WithAudit(auditLog repository.AuditRepository) Option {
+	return func(s *ToDoServer) {
+		s.auditLog = auditLog
+	}
+}
+
+func formatTime(unix int64) string {
+	if unix == 0 {
+		return ""
+	}
+	return strconv.FormatInt(unix, 10)
+}
+
+// auditFields are the task fields audit entries compare, in the order their
+// changes are listed.
+var auditFields = []struct {
+	name  string
+	value func(*domain.Task) string
+}{
+	{"title", func(t *domain.Task) string { return t.Title }},
+	{"description", func(t *domain.Task) string { return t.Description }},
+	{"status", func(t *domain.Task) string { return t.Status }},
+	{"owner", func(t *domain.Task) string { return t.Owner }},
+	{"due_at", func(t *domain.Task) string { return formatTime(t.DueAt) }},
+	{"completed_at", func(t *domain.Task) string { return formatTime(t.CompletedAt) }},
+	{"archived_at", func(t *domain.Task) string { return formatTime(t.ArchivedAt) }},
+	{"deleted_at", func(t *domain.Task) string { return formatTime(t.DeletedAt) }},
+	{"deleted_by", func(t *domain.Task) string { return t.DeletedBy }},
+}
+
+// diffTask lists the fields that differ between before and after, either of
+// which may be nil.
+func diffTask(before, after *domain.Task) []domain.FieldChange {
+	var changes []domain.FieldChange
+	for _, f := range auditFields {
+		var b, a string
+		if before != nil {
+			b = f.value(before)
+		}
+		if after != nil {
+			a = f.value(after)
+		}
+		if a != b {
+			changes = append(changes, domain.FieldChange{Field: f.name, Before: b, After: a})
+		}
+	}
+	return changes
+}
+
+// audit records that the caller changed a task from before to after, which
+// are nil for creations and deletions. It logs failures instead of returning
+// them, since the change has already been stored.
+func (s *ToDoServer) audit(ctx context.Context, method string, before, after *domain.Task) {
+	s.auditBatch(ctx, method, []taskChange{{before, after}})
+}
+
+// taskChange is a task as it was before a call changed it and as it was
+// after, either of which is nil when the task was created or removed.
+type taskChange struct {
+	before, after *domain.Task
+}
+
+// auditBatch records the changes a batch or import call made, one entry per
+// task, in a single write.
+func (s *ToDoServer) auditBatch(ctx context.Context, method string, changes []taskChange) {
+	if s.auditLog == nil {
+		return
+	}
+
+	var entries []*domain.AuditEntry
+	for _, c := range changes {
+		task := cmp.Or(c.after, c.before)
+		if task == nil {
+			continue
+		}
+		entries = append(entries, &domain.AuditEntry{
+			TaskId:    task.Id,
+			Actor:     auth.FromContext(ctx),
+			Method:    method,
+			RequestId: logging.RequestIDFromContext(ctx),
+			Changes:   diffTask(c.before, c.after),
+			Timestamp: time.Now(),
+		})
+	}
+	if len(entries) == 0 {
+		return
+	}
+	if err := s.auditLog.AppendAudit(ctx, entries...); err != nil {
+		attrs := []any{slog.String("method", method)}
+		if len(entries) == 1 {
+			attrs = append(attrs, slog.String("task_id", entries[0].TaskId))
+		} else {
+			attrs = append(attrs, slog.Int("entries", len(entries)))
+		}
+		attrs = append(attrs, slog.String("error", err.Error()))
+		logging.FromContext(ctx).Error("failed to write audit entries", attrs...)
+	}
+}
+
+func toProtoAuditEntry(e *domain.AuditEntry) *proto.AuditEntry {
+	pe := &proto.AuditEntry{
+		Id:        e.Id,
+		TaskId:    e.TaskId,
+		Actor:     e.Actor,
+		Method:    e.Method,
+		RequestId: e.RequestId,
+		Timestamp: e.Timestamp.Unix(),
+	}
+	for _, c := range e.Changes {
+		pe.Changes = append(pe.Changes, &proto.FieldChange{Field: c.Field, Before: c.Before, After: c.After})
+	}
+	return pe
+}
+
+func (s *ToDoServer) ListAuditEntries(ctx context.Context, req *proto.ListAuditEntriesRequest) (*proto.ListAuditEntriesResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	if s.auditLog == nil {
+		return nil, status.Error(codes.FailedPrecondition, "the audit log is not enabled on this server")
+	}
+
+	size, err := pageSize("ListAuditEntries", req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.EndTime != 0 && req.StartTime > req.EndTime {
+		return nil, status.Error(codes.InvalidArgument, "ListAuditEntries failed: start_time is after end_time")
+	}
+
+	f := repository.AuditFilter{TaskID: req.TaskId, Actor: req.Actor}
+	if req.StartTime != 0 {
+		f.Since = time.Unix(req.StartTime, 0)
+	}
+	if req.EndTime != 0 {
+		// Entries carry milliseconds; the whole end second is included.
+		f.Until = time.Unix(req.EndTime+1, 0)
+	}
+
+	entries, next, err := s.auditLog.ListAuditEntries(ctx, f, size, req.PageToken)
+	if err != nil {
+		return nil, toStatusError("ListAuditEntries", err)
+	}
+
+	res := &proto.ListAuditEntriesResponse{NextPageToken: next}
+	for _, e := range entries {
+		res.Entries = append(res.Entries, toProtoAuditEntry(e))
+	}
+	return res, nil
+}
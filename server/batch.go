@@ -0,0 +1,156 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"grpc-todo/auth"
+	"grpc-todo/domain"
+	"grpc-todo/proto"
+	"grpc-todo/repository"
+
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *ToDoServer) BatchCreateTasks(ctx context.Context, req *proto.BatchCreateTasksRequest) (*proto.BatchCreateTasksResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	owner := auth.FromContext(ctx)
+	allowed, release, err := s.reserveTasks(ctx, owner, len(req.Requests))
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	if req.AllOrNothing && allowed < len(req.Requests) {
+		return nil, s.quotaError(owner, s.maxTasksPerTenant-int64(allowed))
+	}
+
+	now := time.Now().Unix()
+	tasks := make([]*domain.Task, allowed)
+	for i, r := range req.Requests[:allowed] {
+		tasks[i] = &domain.Task{
+			Title:       r.Title,
+			Description: r.Description,
+			Status:      "TODO",
+			CreatedAt:   now,
+			Owner:       owner,
+			DueAt:       r.DueAt,
+		}
+	}
+
+	errs, err := s.repo.BatchCreateTasks(ctx, tasks, req.AllOrNothing)
+	if err != nil {
+		return nil, toStatusError("BatchCreateTasks", err)
+	}
+
+	results := make([]*proto.BatchItemResult, len(req.Requests))
+	var changes []taskChange
+	for i := range req.Requests {
+		if i >= allowed {
+			results[i] = &proto.BatchItemResult{
+				Status: &spb.Status{Code: int32(codes.ResourceExhausted), Message: "task quota exceeded"},
+			}
+			continue
+		}
+		results[i] = batchItemResult("BatchCreateTasks", tasks[i].Id, errs[i])
+		if errs[i] == nil {
+			results[i].Task = toProtoTask(tasks[i])
+			changes = append(changes, taskChange{after: tasks[i]})
+		}
+	}
+	s.auditBatch(ctx, "BatchCreateTasks", changes)
+
+	return &proto.BatchCreateTasksResponse{Results: results}, nil
+}
+
+func (s *ToDoServer) BatchUpdateTaskStatus(ctx context.Context, req *proto.BatchUpdateTaskStatusRequest) (*proto.BatchUpdateTaskStatusResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	updates := make([]repository.StatusUpdate, len(req.Requests))
+	ids := make([]string, len(req.Requests))
+	for i, r := range req.Requests {
+		updates[i] = repository.StatusUpdate{ID: r.Id, Status: protoStatusToString(r.Status)}
+		ids[i] = r.Id
+	}
+
+	var before []*domain.Task
+	if s.auditLog != nil {
+		before = s.lookupTasks(ctx, ids)
+	}
+
+	errs, err := s.repo.BatchUpdateTaskStatus(ctx, updates, auth.FromContext(ctx), req.AllOrNothing)
+	if err != nil {
+		return nil, toStatusError("BatchUpdateTaskStatus", err)
+	}
+
+	results := make([]*proto.BatchItemResult, len(updates))
+	var changes []taskChange
+	for i, u := range updates {
+		results[i] = batchItemResult("BatchUpdateTaskStatus", u.ID, errs[i])
+		if errs[i] == nil && before != nil {
+			changes = append(changes, taskChange{before[i], s.lookupTask(ctx, u.ID)})
+		}
+	}
+	s.auditBatch(ctx, "BatchUpdateTaskStatus", changes)
+
+	return &proto.BatchUpdateTaskStatusResponse{Results: results}, nil
+}
+
+func (s *ToDoServer) BatchDeleteTasks(ctx context.Context, req *proto.BatchDeleteTasksRequest) (*proto.BatchDeleteTasksResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	// Entries carry the tasks as they were, so read them before they are
+	// gone.
+	var before []*domain.Task
+	if s.auditLog != nil {
+		before = s.lookupTasks(ctx, req.Ids)
+	}
+
+	deletedBy := auth.FromContext(ctx)
+	errs, err := s.repo.BatchDeleteTasks(ctx, req.Ids, deletedBy, req.AllOrNothing)
+	if err != nil {
+		return nil, toStatusError("BatchDeleteTasks", err)
+	}
+
+	results := make([]*proto.BatchItemResult, len(req.Ids))
+	var changes []taskChange
+	for i, id := range req.Ids {
+		results[i] = batchItemResult("BatchDeleteTasks", id, errs[i])
+		if errs[i] == nil && before != nil && before[i] != nil {
+			changes = append(changes, taskChange{before[i], trashedTask(before[i], deletedBy)})
+		}
+	}
+	s.auditBatch(ctx, "BatchDeleteTasks", changes)
+
+	return &proto.BatchDeleteTasksResponse{Results: results}, nil
+}
+
+// batchItemResult reports the outcome of one item with the same status code
+// the single-item RPC would have returned for it.
+func batchItemResult(method, id string, err error) *proto.BatchItemResult {
+	return &proto.BatchItemResult{Id: id, Status: itemStatus(method, err)}
+}
+
+func itemStatus(method string, err error) *spb.Status {
+	if err == nil {
+		return &spb.Status{Code: int32(codes.OK)}
+	}
+	if _, ok := status.FromError(err); ok {
+		return status.Convert(err).Proto()
+	}
+	return status.Convert(toStatusError(method, err)).Proto()
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+
+	"grpc-todo/auth"
+	"grpc-todo/domain"
+	"grpc-todo/ical"
+	"grpc-todo/proto"
+	"grpc-todo/repository"
+	"grpc-todo/validation"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *ToDoServer) GetCalendarFeed(ctx context.Context, _ *proto.GetCalendarFeedRequest) (*proto.GetCalendarFeedResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	if s.calendarSigner == nil {
+		return nil, status.Error(codes.FailedPrecondition, "calendar feeds are not enabled on this server")
+	}
+
+	url := s.calendarBaseURL + s.calendarSigner.FeedPath(auth.FromContext(ctx))
+	return &proto.GetCalendarFeedResponse{Url: url}, nil
+}
+
+// ImportCalendar creates or updates one task per VTODO. A VTODO that was
+// exported from here, or imported before, updates its task, so importing the
+// same file twice does not duplicate anything.
+func (s *ToDoServer) ImportCalendar(ctx context.Context, req *proto.ImportCalendarRequest) (*proto.ImportCalendarResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	todos, err := ical.Decode(bytes.NewReader(req.Content))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "ImportCalendar failed: %v", err)
+	}
+
+	owner := auth.FromContext(ctx)
+	res := &proto.ImportCalendarResponse{}
+	problem := func(todo ical.Todo, field, message string) {
+		res.Problems = append(res.Problems, &proto.DocumentProblem{Line: int64(todo.Line), Field: field, Message: message})
+	}
+
+	for _, todo := range todos {
+		if todo.Err != nil {
+			problem(todo, "", todo.Err.Error())
+			continue
+		}
+		if todo.UID == "" {
+			problem(todo, "UID", "must not be empty")
+			continue
+		}
+		t := todo.Task
+		err := validation.Validate(&proto.CreateTaskRequest{Title: t.Title, Description: t.Description})
+		if verr, ok := err.(*validation.Error); ok {
+			for _, v := range verr.Violations {
+				problem(todo, v.Field, v.Description)
+			}
+			continue
+		}
+
+		existing, err := s.findCalendarTask(ctx, owner, todo)
+		if err != nil {
+			return nil, toStatusError("ImportCalendar", err)
+		}
+
+		if existing != nil {
+			if err := s.updateCalendarTask(ctx, existing, t); err != nil {
+				problem(todo, "", itemStatus("ImportCalendar", err).GetMessage())
+				continue
+			}
+			res.Updated++
+			continue
+		}
+
+		if err := s.createCalendarTask(ctx, owner, todo); err != nil {
+			problem(todo, "", itemStatus("ImportCalendar", err).GetMessage())
+			continue
+		}
+		res.Created++
+	}
+
+	return res, nil
+}
+
+// createCalendarTask stores a VTODO that refers to no task as a new task
+// of the owner's.
+func (s *ToDoServer) createCalendarTask(ctx context.Context, owner string, todo ical.Todo) error {
+	release, err := s.checkTaskQuota(ctx, owner)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	t := todo.Task
+	task := &domain.Task{
+		Title:       t.Title,
+		Description: t.Description,
+		Status:      t.Status,
+		CreatedAt:   t.CreatedAt,
+		DueAt:       t.DueAt,
+		Owner:       owner,
+		ICalUID:     todo.UID,
+	}
+	if task.CreatedAt == 0 {
+		task.CreatedAt = time.Now().Unix()
+	}
+	created, err := s.repo.CreateTask(ctx, task)
+	if err != nil {
+		return err
+	}
+	s.audit(ctx, "ImportCalendar", nil, created)
+	return nil
+}
+
+// findCalendarTask returns the owner's task a VTODO refers to, or nil.
+func (s *ToDoServer) findCalendarTask(ctx context.Context, owner string, todo ical.Todo) (*domain.Task, error) {
+	if todo.Task.Id != "" {
+		task, err := s.repo.GetTask(ctx, todo.Task.Id)
+		if err == nil && task.Owner == owner {
+			return task, nil
+		}
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+	}
+
+	task, err := s.repo.FindTaskByICalUID(ctx, owner, todo.UID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	return task, err
+}
+
+func (s *ToDoServer) updateCalendarTask(ctx context.Context, existing, t *domain.Task) error {
+	dueAt := t.DueAt
+	updated, err := s.repo.UpdateTask(ctx, existing.Id, t.Title, t.Description, &dueAt)
+	if err != nil {
+		return err
+	}
+	if existing.Status != t.Status {
+		if err := s.repo.UpdateTaskStatus(ctx, existing.Id, t.Status, auth.FromContext(ctx)); err != nil {
+			s.audit(ctx, "ImportCalendar", existing, updated)
+			return err
+		}
+		if s.auditLog != nil {
+			updated = s.lookupTask(ctx, existing.Id)
+		}
+	}
+	s.audit(ctx, "ImportCalendar", existing, updated)
+	return nil
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"time"
+
+	"grpc-todo/auth"
+	"grpc-todo/domain"
+	"grpc-todo/export"
+	"grpc-todo/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var documentFormats = map[proto.DocumentFormat]export.Format{
+	proto.DocumentFormat_DOCUMENT_FORMAT_CSV:      export.CSV,
+	proto.DocumentFormat_DOCUMENT_FORMAT_JSONL:    export.JSONL,
+	proto.DocumentFormat_DOCUMENT_FORMAT_MARKDOWN: export.Markdown,
+	proto.DocumentFormat_DOCUMENT_FORMAT_TODO_TXT: export.TodoTxt,
+}
+
+// ExportDocument renders every task as a single file. It suits spreadsheets
+// and checklists; ExportTasks streams large data sets instead.
+func (s *ToDoServer) ExportDocument(ctx context.Context, req *proto.ExportDocumentRequest) (*proto.ExportDocumentResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	format := documentFormats[req.Format]
+
+	var tasks []*domain.Task
+	err := s.repo.ForEachTask(ctx, func(t *domain.Task) error {
+		tasks = append(tasks, exportedTask(t, req.PreserveIds))
+		return nil
+	})
+	if err != nil {
+		return nil, toStatusError("ExportDocument", err)
+	}
+
+	var buf bytes.Buffer
+	if err := export.Write(&buf, format, tasks); err != nil {
+		return nil, toStatusError("ExportDocument", err)
+	}
+
+	return &proto.ExportDocumentResponse{Content: buf.Bytes(), ContentType: format.ContentType()}, nil
+}
+
+// ImportDocument stores the valid tasks of a file and reports the rest by
+// line. With dry_run set it only reports.
+func (s *ToDoServer) ImportDocument(ctx context.Context, req *proto.ImportDocumentRequest) (*proto.ImportDocumentResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	rep, err := export.Read(bytes.NewReader(req.Content), documentFormats[req.Format])
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "ImportDocument failed: %v", err)
+	}
+
+	res := &proto.ImportDocumentResponse{}
+	for _, p := range rep.Problems {
+		res.Problems = append(res.Problems, &proto.DocumentProblem{Line: int64(p.Line), Field: p.Field, Message: p.Message})
+	}
+
+	owner := auth.FromContext(ctx)
+	now := time.Now().Unix()
+	for _, t := range rep.Tasks {
+		t.Owner = owner
+		if !req.PreserveIds {
+			t.Id = ""
+			t.CreatedAt = 0
+			t.CompletedAt = 0
+		}
+		if t.CreatedAt == 0 {
+			t.CreatedAt = now
+		}
+		res.Tasks = append(res.Tasks, toProtoTask(t))
+	}
+
+	if req.DryRun {
+		return res, nil
+	}
+
+	for start := 0; start < len(rep.Tasks); start += importChunkSize {
+		chunk := rep.Tasks[start:min(start+importChunkSize, len(rep.Tasks))]
+		errs, err := s.createImported(ctx, "ImportDocument", owner, chunk)
+		if err != nil {
+			return nil, err
+		}
+		for i, err := range errs {
+			if err != nil {
+				res.Problems = append(res.Problems, &proto.DocumentProblem{
+					Line:    int64(rep.Lines[start+i]),
+					Message: itemStatus("ImportDocument", err).GetMessage(),
+				})
+				continue
+			}
+			res.Tasks[start+i].Id = chunk[i].Id
+			res.Imported++
+		}
+	}
+
+	sort.SliceStable(res.Problems, func(i, j int) bool {
+		return res.Problems[i].Line < res.Problems[j].Line
+	})
+	return res, nil
+}
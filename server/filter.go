@@ -0,0 +1,42 @@
+package server
+
+import (
+	"grpc-todo/filter"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// parseFilter parses a task filter. Errors are InvalidArgument with a
+// BadRequest detail naming the field and the offending token.
+func parseFilter(method, field, raw string) (filter.Expr, error) {
+	expr, err := filter.Parse(raw, filter.Tasks)
+	if err != nil {
+		return nil, invalidField(method, field, err)
+	}
+	return expr, nil
+}
+
+// parseOrderBy parses a task order like parseFilter.
+func parseOrderBy(method, field, raw string) ([]filter.OrderKey, error) {
+	order, err := filter.ParseOrderBy(raw, filter.Tasks)
+	if err != nil {
+		return nil, invalidField(method, field, err)
+	}
+	return order, nil
+}
+
+func invalidField(method, field string, err error) error {
+	st := status.Newf(codes.InvalidArgument, "%s failed: invalid %s: %v", method, field, err)
+	withDetails, detailErr := st.WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{
+			Field:       field,
+			Description: err.Error(),
+		}},
+	})
+	if detailErr != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
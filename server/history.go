@@ -0,0 +1,44 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"grpc-todo/domain"
+	"grpc-todo/proto"
+)
+
+func (s *ToDoServer) GetTaskHistory(ctx context.Context, req *proto.GetTaskHistoryRequest) (*proto.GetTaskHistoryResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	task, err := s.repo.GetTask(ctx, req.Id)
+	if err != nil {
+		return nil, toStatusError("GetTaskHistory", err)
+	}
+	transitions, err := s.repo.GetTaskHistory(ctx, req.Id)
+	if err != nil {
+		return nil, toStatusError("GetTaskHistory", err)
+	}
+
+	res := &proto.GetTaskHistoryResponse{CycleTimeSeconds: domain.CycleTime(task, transitions)}
+	for _, t := range transitions {
+		res.Transitions = append(res.Transitions, &proto.StatusTransition{
+			From:      stringToProtoStatus(t.From),
+			To:        stringToProtoStatus(t.To),
+			ChangedBy: t.ChangedBy,
+			ChangedAt: t.ChangedAt,
+		})
+	}
+
+	durations := domain.TimeInStatus(task, transitions, time.Now().Unix())
+	for _, status := range []proto.Status{proto.Status_TODO, proto.Status_IN_PROGRESS, proto.Status_PAUSED, proto.Status_DONE} {
+		if seconds, ok := durations[protoStatusToString(status)]; ok {
+			res.Durations = append(res.Durations, &proto.StatusDuration{Status: status, Seconds: seconds})
+		}
+	}
+	return res, nil
+}
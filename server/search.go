@@ -0,0 +1,64 @@
+package server
+
+import (
+	"context"
+
+	"grpc-todo/proto"
+	"grpc-todo/repository"
+	"grpc-todo/search"
+)
+
+// snippetWidth is the most runes of a field a search snippet shows.
+const snippetWidth = 160
+
+func (s *ToDoServer) SearchTasks(ctx context.Context, req *proto.SearchTasksRequest) (*proto.SearchTasksResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	size, err := pageSize("SearchTasks", req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	q := repository.SearchQuery{Text: req.Query, Tags: req.Tags, Limit: size}
+	for _, st := range req.Statuses {
+		q.Statuses = append(q.Statuses, protoStatusToString(st))
+	}
+
+	hits, err := s.repo.SearchTasks(ctx, q)
+	if err != nil {
+		return nil, toStatusError("SearchTasks", err)
+	}
+
+	terms := search.Terms(req.Query)
+	res := &proto.SearchTasksResponse{}
+	for _, h := range hits {
+		result := &proto.SearchResult{Task: toProtoTask(h.Task), Score: h.Score}
+		for _, f := range []struct{ name, text string }{
+			{"title", h.Task.Title},
+			{"description", h.Task.Description},
+		} {
+			if snippet := toProtoSnippet(f.name, f.text, terms); snippet != nil {
+				result.Snippets = append(result.Snippets, snippet)
+			}
+		}
+		res.Results = append(res.Results, result)
+	}
+	return res, nil
+}
+
+// toProtoSnippet returns the snippet of a field, or nil when no word of it
+// matches the terms.
+func toProtoSnippet(field, text string, terms []string) *proto.Snippet {
+	snippet, highlights, ok := search.Snippet(text, terms, snippetWidth)
+	if !ok {
+		return nil
+	}
+	res := &proto.Snippet{Field: field, Text: snippet}
+	for _, r := range highlights {
+		res.Highlights = append(res.Highlights, &proto.TextRange{Start: int32(r.Start), End: int32(r.End)})
+	}
+	return res
+}
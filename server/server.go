@@ -2,24 +2,92 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
 	"time"
-	"log"
 
+	"grpc-todo/auth"
+	"grpc-todo/calendar"
 	"grpc-todo/domain"
+	"grpc-todo/filter"
+	"grpc-todo/logging"
 	"grpc-todo/proto"
 	"grpc-todo/repository"
+	"grpc-todo/retention"
+	"grpc-todo/webhook"
 
 	"github.com/robfig/cron/v3"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type ToDoServer struct {
 	proto.UnimplementedToDoServiceServer
 	repo repository.Repository
+
+	maxTasksPerTenant int64
+
+	calendarSigner  *calendar.Signer
+	calendarBaseURL string
+
+	webhooks   repository.WebhookRepository
+	dispatcher *webhook.Dispatcher
+
+	retention         *retention.Engine
+	retentionSchedule string
+
+	auditLog repository.AuditRepository
+
+	views repository.ViewRepository
+}
+
+type Option func(*ToDoServer)
+
+// WithMaxTasksPerTenant caps the number of tasks a single principal may
+// store. Zero means unlimited.
+func WithMaxTasksPerTenant(n int64) Option {
+	return func(s *ToDoServer) {
+		s.maxTasksPerTenant = n
+	}
+}
+
+// WithCalendarFeed enables GetCalendarFeed. baseURL is the public address of
+// the HTTP port that feed URLs are built on.
+func WithCalendarFeed(signer *calendar.Signer, baseURL string) Option {
+	return func(s *ToDoServer) {
+		s.calendarSigner = signer
+		s.calendarBaseURL = strings.TrimSuffix(baseURL, "/")
+	}
 }
 
-func NewToDoServer(repo repository.Repository) *ToDoServer {
-	return &ToDoServer{repo: repo}
+// WithWebhooks enables the webhook RPCs, checking new webhook URLs with
+// dispatcher. Task events reach dispatcher through WebhookPublisher.
+func WithWebhooks(webhooks repository.WebhookRepository, dispatcher *webhook.Dispatcher) Option {
+	return func(s *ToDoServer) {
+		s.webhooks = webhooks
+		s.dispatcher = dispatcher
+	}
+}
+
+// WithRetention makes StartCronJob apply policies on schedule, a cron spec
+// such as "@every 1h".
+func WithRetention(schedule string, policies []retention.Policy, opts ...retention.Option) Option {
+	return func(s *ToDoServer) {
+		opts = append(opts, retention.WithNotify(s.retentionRemoved))
+		s.retention = retention.NewEngine(s.repo, policies, opts...)
+		s.retentionSchedule = schedule
+	}
+}
+
+func NewToDoServer(repo repository.Repository, opts ...Option) *ToDoServer {
+	s := &ToDoServer{repo: repo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func protoStatusToString(status proto.Status) string {
@@ -28,6 +96,8 @@ func protoStatusToString(status proto.Status) string {
 		return "TODO"
 	case proto.Status_IN_PROGRESS:
 		return "IN_PROGRESS"
+	case proto.Status_PAUSED:
+		return "PAUSED"
 	case proto.Status_DONE:
 		return "DONE"
 	default:
@@ -41,6 +111,8 @@ func stringToProtoStatus(status string) proto.Status {
 		return proto.Status_TODO
 	case "IN_PROGRESS":
 		return proto.Status_IN_PROGRESS
+	case "PAUSED":
+		return proto.Status_PAUSED
 	case "DONE":
 		return proto.Status_DONE
 	default:
@@ -48,6 +120,42 @@ func stringToProtoStatus(status string) proto.Status {
 	}
 }
 
+func toProtoTask(t *domain.Task) *proto.Task {
+	return &proto.Task{
+		Id:          t.Id,
+		Title:       t.Title,
+		Description: t.Description,
+		Status:      stringToProtoStatus(t.Status),
+		CreatedAt:   t.CreatedAt,
+		DueAt:       t.DueAt,
+		CompletedAt: t.CompletedAt,
+		ArchivedAt:  t.ArchivedAt,
+		DeletedAt:   t.DeletedAt,
+		DeletedBy:   t.DeletedBy,
+	}
+}
+
+// toStatusError maps repository errors onto gRPC codes so that callers, and
+// the REST gateway in front of them, can tell client mistakes from failures.
+func toStatusError(method string, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return status.Errorf(codes.NotFound, "%s failed: %v", method, err)
+	case errors.Is(err, repository.ErrInvalidID), errors.Is(err, repository.ErrInvalidPageToken):
+		return status.Errorf(codes.InvalidArgument, "%s failed: %v", method, err)
+	case errors.Is(err, repository.ErrAlreadyExists):
+		return status.Errorf(codes.AlreadyExists, "%s failed: %v", method, err)
+	case errors.Is(err, repository.ErrBatchAborted):
+		return status.Errorf(codes.Aborted, "%s failed: %v", method, err)
+	case errors.Is(err, context.Canceled):
+		return status.Errorf(codes.Canceled, "%s failed: %v", method, err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Errorf(codes.DeadlineExceeded, "%s failed: %v", method, err)
+	default:
+		return status.Errorf(codes.Internal, "%s failed: %v", method, err)
+	}
+}
+
 func (s *ToDoServer) CreateTask(ctx context.Context, req *proto.CreateTaskRequest) (*proto.CreateTaskResponse, error) {
 	select {
 	case <-ctx.Done():
@@ -55,55 +163,158 @@ func (s *ToDoServer) CreateTask(ctx context.Context, req *proto.CreateTaskReques
 	default:
 	}
 
+	owner := auth.FromContext(ctx)
+	release, err := s.checkTaskQuota(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	task := &domain.Task{
-		Title: req.Title,
+		Title:       req.Title,
 		Description: req.Description,
-		Status: "TODO",
-		CreatedAt: time.Now().Unix(),
+		Status:      "TODO",
+		CreatedAt:   time.Now().Unix(),
+		Owner:       owner,
+		DueAt:       req.DueAt,
 	}
 
 	createdTask, err := s.repo.CreateTask(ctx, task)
 	if err != nil {
-		return nil, fmt.Errorf("CreateTask failed: %v", err)
+		return nil, toStatusError("CreateTask", err)
 	}
+	s.audit(ctx, "CreateTask", nil, createdTask)
 
-	return &proto.CreateTaskResponse{
-		Task: &proto.Task{
-			Id: createdTask.Id,
-			Title: createdTask.Title,
-			Description: createdTask.Description,
-			Status: stringToProtoStatus(createdTask.Status),
-			CreatedAt: createdTask.CreatedAt,
-		},
-	}, nil
+	return &proto.CreateTaskResponse{Task: toProtoTask(createdTask)}, nil
+}
+
+// checkTaskQuota reserves room for one new task of the owner's. The caller
+// must call release once the task is stored, or failed to be.
+func (s *ToDoServer) checkTaskQuota(ctx context.Context, owner string) (release func(), err error) {
+	_, release, err = s.reserveTasks(ctx, owner, 1)
+	return release, err
+}
+
+// reserveTasks reserves room for n new tasks in the owner's quota and returns
+// how many of them fit. It fails with ResourceExhausted when none do. Until
+// release is called, other calls count the reserved tasks as stored, so
+// concurrent calls cannot together exceed the quota. release is never nil.
+func (s *ToDoServer) reserveTasks(ctx context.Context, owner string, n int) (int, func(), error) {
+	if s.maxTasksPerTenant <= 0 {
+		return n, func() {}, nil
+	}
+
+	used, release, err := s.repo.ReserveTasks(ctx, owner, n)
+	if err != nil {
+		return 0, func() {}, toStatusError("CreateTask", err)
+	}
+	if free := s.maxTasksPerTenant - used; free > 0 {
+		return int(min(free, int64(n))), release, nil
+	}
+	release()
+	return 0, func() {}, s.quotaError(owner, used)
 }
 
-func (s *ToDoServer) GetAllTasks(ctx context.Context, _ *proto.GetAllTasksRequest) (*proto.GetAllTasksResponse, error) {
+func (s *ToDoServer) quotaError(owner string, count int64) error {
+	st := status.Newf(codes.ResourceExhausted, "task quota exceeded: %d of %d tasks stored", count, s.maxTasksPerTenant)
+	withDetails, err := st.WithDetails(&errdetails.QuotaFailure{
+		Violations: []*errdetails.QuotaFailure_Violation{{
+			Subject:     "tenant:" + owner,
+			Description: fmt.Sprintf("at most %d tasks may be stored", s.maxTasksPerTenant),
+		}},
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
+
+func (s *ToDoServer) GetAllTasks(ctx context.Context, req *proto.GetAllTasksRequest) (*proto.GetAllTasksResponse, error) {
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	default:
 	}
 
-	tasks, err := s.repo.GetAllTasks(ctx)
+	expr, err := parseFilter("GetAllTasks", "filter", req.Filter)
+	if err != nil {
+		return nil, err
+	}
+	var tasks []*domain.Task
+	switch repo, ok := s.repo.(repository.EventSourcedRepository); {
+	case req.AsOf != 0 && !ok:
+		return nil, status.Error(codes.FailedPrecondition, "GetAllTasks failed: as_of needs the event-sourced task store")
+	case req.AsOf != 0:
+		tasks, err = tasksAsOf(ctx, repo, req.AsOf, expr)
+	case expr == nil:
+		tasks, err = s.repo.GetAllTasks(ctx)
+	default:
+		tasks, err = s.repo.FilterTasks(ctx, expr, nil)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("GetAllTasks failed: %v", err)
+		return nil, toStatusError("GetAllTasks", err)
 	}
 
 	var protoTasks []*proto.Task
 	for _, t := range tasks {
-		protoTasks = append(protoTasks, &proto.Task{
-			Id: t.Id,
-			Title: t.Title,
-			Description: t.Description,
-			Status: stringToProtoStatus(t.Status),
-			CreatedAt: t.CreatedAt,
-		})
+		protoTasks = append(protoTasks, toProtoTask(t))
 	}
 
 	return &proto.GetAllTasksResponse{Tasks: protoTasks}, nil
 }
 
+// tasksAsOf lists the tasks matching expr, or all of them when it is nil,
+// as they were at the end of the second asOf.
+func tasksAsOf(ctx context.Context, repo repository.EventSourcedRepository, asOf int64, expr filter.Expr) ([]*domain.Task, error) {
+	all, err := repo.TasksAsOf(ctx, time.Unix(asOf, int64(time.Second-time.Nanosecond)))
+	if err != nil || expr == nil {
+		return all, err
+	}
+	var tasks []*domain.Task
+	for _, t := range all {
+		if filter.Match(expr, filter.TaskValue(t)) {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks, nil
+}
+
+func (s *ToDoServer) GetTask(ctx context.Context, req *proto.GetTaskRequest) (*proto.GetTaskResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	task, err := s.repo.GetTask(ctx, req.Id)
+	if err != nil {
+		return nil, toStatusError("GetTask", err)
+	}
+
+	return &proto.GetTaskResponse{Task: toProtoTask(task)}, nil
+}
+
+func (s *ToDoServer) UpdateTask(ctx context.Context, req *proto.UpdateTaskRequest) (*proto.UpdateTaskResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	var before *domain.Task
+	if s.auditLog != nil {
+		before = s.lookupTask(ctx, req.Id)
+	}
+
+	task, err := s.repo.UpdateTask(ctx, req.Id, req.Title, req.Description, req.DueAt)
+	if err != nil {
+		return nil, toStatusError("UpdateTask", err)
+	}
+	s.audit(ctx, "UpdateTask", before, task)
+
+	return &proto.UpdateTaskResponse{Task: toProtoTask(task)}, nil
+}
+
 func (s *ToDoServer) UpdateTaskStatus(ctx context.Context, req *proto.UpdateTaskStatusRequest) (*proto.UpdateTaskStatusResponse, error) {
 	select {
 	case <-ctx.Done():
@@ -111,9 +322,17 @@ func (s *ToDoServer) UpdateTaskStatus(ctx context.Context, req *proto.UpdateTask
 	default:
 	}
 
-	err := s.repo.UpdateTaskStatus(ctx, req.Id, protoStatusToString(req.Status))
+	var before *domain.Task
+	if s.auditLog != nil {
+		before = s.lookupTask(ctx, req.Id)
+	}
+
+	err := s.repo.UpdateTaskStatus(ctx, req.Id, protoStatusToString(req.Status), auth.FromContext(ctx))
 	if err != nil {
-		return nil, fmt.Errorf("UpdateTaskStatus failed: %v", err)
+		return nil, toStatusError("UpdateTaskStatus", err)
+	}
+	if s.auditLog != nil {
+		s.audit(ctx, "UpdateTaskStatus", before, s.lookupTask(ctx, req.Id))
 	}
 
 	return &proto.UpdateTaskStatusResponse{}, nil
@@ -126,33 +345,116 @@ func (s *ToDoServer) DeleteTask(ctx context.Context, req *proto.DeleteTaskReques
 	default:
 	}
 
-	err := s.repo.DeleteTask(ctx, req.Id)
+	// The entry carries the task as it was, so read it before it is gone.
+	var task *domain.Task
+	if s.auditLog != nil {
+		task = s.lookupTask(ctx, req.Id)
+	}
+
+	deletedBy := auth.FromContext(ctx)
+	err := s.repo.DeleteTask(ctx, req.Id, deletedBy)
 	if err != nil {
-		return nil, fmt.Errorf("DeleteTask failed: %v", err)
+		return nil, toStatusError("DeleteTask", err)
+	}
+	if task != nil {
+		s.audit(ctx, "DeleteTask", task, trashedTask(task, deletedBy))
 	}
 
 	return &proto.DeleteTaskResponse{}, nil
 }
 
-func (s *ToDoServer) StartCronJob() *cron.Cron {
+// lookupTask reads a task for an event or audit entry, returning nil if it
+// cannot.
+func (s *ToDoServer) lookupTask(ctx context.Context, id string) *domain.Task {
+	task, err := s.repo.GetTask(ctx, id)
+	if err != nil {
+		logging.FromContext(ctx).Warn("failed to read task for event",
+			slog.String("task_id", id), slog.String("error", err.Error()))
+		return nil
+	}
+	return task
+}
+
+// lookupTasks reads the tasks a batch names before it changes them, for
+// events and audit entries. Tasks that cannot be read are nil; the batch
+// reports them itself.
+func (s *ToDoServer) lookupTasks(ctx context.Context, ids []string) []*domain.Task {
+	tasks := make([]*domain.Task, len(ids))
+	for i, id := range ids {
+		if task, err := s.repo.GetTask(ctx, id); err == nil {
+			tasks[i] = task
+		}
+	}
+	return tasks
+}
+
+// trashedTask returns a task as it is once deletedBy moved it to the trash.
+func trashedTask(task *domain.Task, deletedBy string) *domain.Task {
+	t := *task
+	t.DeletedAt, t.DeletedBy = time.Now().Unix(), deletedBy
+	return &t
+}
+
+// StartCronJob runs the retention policies on their schedule. It returns
+// nil when the server has none.
+func (s *ToDoServer) StartCronJob() (*cron.Cron, error) {
+	if s.retention == nil {
+		return nil, nil
+	}
+
 	c := cron.New()
-	c.AddFunc("@every 1m", func() {
-		log.Println("Cron job started: Deleting DONE tasks")
-		s.deleteDoneTasks()
-	})
+	if _, err := c.AddFunc(s.retentionSchedule, s.applyRetention); err != nil {
+		return nil, fmt.Errorf("invalid retention schedule %q: %v", s.retentionSchedule, err)
+	}
 	c.Start()
-	return c
+	return c, nil
 }
 
-func (s *ToDoServer) deleteDoneTasks() {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func (s *ToDoServer) applyRetention() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 	defer cancel()
 
-	deletedCount, err := s.repo.DeleteDoneTasks(ctx)
+	requestID := logging.NewRequestID()
+	logger := slog.Default().With(slog.String("job", "retention"), slog.String("request_id", requestID))
+	ctx = logging.NewContext(logging.WithRequestID(ctx, requestID), logger)
+	ctx = auth.NewContext(ctx, retentionActor)
+
+	dryRun := s.retention.DryRun()
+	logger.Info("cron job started", slog.Bool("dry_run", dryRun))
+
+	results, err := s.retention.Run(ctx)
+	for _, res := range results {
+		attrs := []any{slog.String("policy", res.Policy.String()), slog.Int("tasks", len(res.Tasks))}
+		if dryRun {
+			ids := make([]string, len(res.Tasks))
+			for i, t := range res.Tasks {
+				ids[i] = t.Id
+			}
+			attrs = append(attrs, slog.Any("task_ids", ids))
+			logger.Info("retention policy would remove tasks", attrs...)
+			continue
+		}
+		logger.Info("retention policy applied", attrs...)
+	}
 	if err != nil {
-		log.Printf("Error deleting DONE tasks: %v", err)
+		logger.Error("cron job failed", slog.String("error", err.Error()))
 		return
 	}
 
-	log.Printf("Cron job: Deleted %d DONE tasks", deletedCount)
-}
\ No newline at end of file
+	logger.Info("cron job finished")
+}
+
+// retentionRemoved reports a task removed by a retention policy to the audit
+// log. Webhooks hear of it from the event the repository writes to the
+// outbox.
+func (s *ToDoServer) retentionRemoved(ctx context.Context, p retention.Policy, task *domain.Task) {
+	method := "retention " + p.String()
+	switch p.Action {
+	case retention.Delete:
+		s.audit(ctx, method, task, nil)
+	case retention.Archive:
+		s.audit(ctx, method, unarchived(task), task)
+	case retention.Purge:
+		s.audit(ctx, method, task, nil)
+	}
+}
@@ -1,21 +1,52 @@
 package server
 
 import (
+	"cmp"
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"grpc-todo/auth"
+	"grpc-todo/calendar"
 	"grpc-todo/domain"
+	"grpc-todo/filter"
+	"grpc-todo/outbox"
 	"grpc-todo/proto"
 	"grpc-todo/repository"
+	"grpc-todo/retention"
+	"grpc-todo/search"
+	"grpc-todo/webhook"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
 type mockRepository struct {
-	tasks map[string]*domain.Task
+	tasks    map[string]*domain.Task
+	archived map[string]*domain.Task
+	trash    map[string]*domain.Task
+	history  map[string][]*domain.StatusTransition
 }
 
 func newMockRepository() repository.Repository {
 	return &mockRepository{
-		tasks: make(map[string]*domain.Task),
+		tasks:    make(map[string]*domain.Task),
+		archived: make(map[string]*domain.Task),
+		trash:    make(map[string]*domain.Task),
+		history:  make(map[string][]*domain.StatusTransition),
 	}
 }
 
@@ -33,35 +64,365 @@ func (m *mockRepository) GetAllTasks(ctx context.Context) ([]*domain.Task, error
 	return res, nil
 }
 
-func (m *mockRepository) UpdateTaskStatus(ctx context.Context, id string, status string) error {
+func (m *mockRepository) FilterTasks(ctx context.Context, expr filter.Expr, order []filter.OrderKey) ([]*domain.Task, error) {
+	var res []*domain.Task
+	for _, t := range m.tasks {
+		if filter.Match(expr, filter.TaskValue(t)) {
+			res = append(res, t)
+		}
+	}
+	slices.SortFunc(res, func(a, b *domain.Task) int {
+		return cmp.Or(filter.CompareOrder(order, filter.TaskValue(a), filter.TaskValue(b)), strings.Compare(a.Id, b.Id))
+	})
+	return res, nil
+}
+
+func (m *mockRepository) GetTask(ctx context.Context, id string) (*domain.Task, error) {
+	t, ok := m.tasks[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	// A copy, like a task read from the database.
+	task := *t
+	return &task, nil
+}
+
+func (m *mockRepository) UpdateTask(ctx context.Context, id string, title string, description string, dueAt *int64) (*domain.Task, error) {
+	t, ok := m.tasks[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	t.Title = title
+	t.Description = description
+	if dueAt != nil {
+		t.DueAt = *dueAt
+	}
+	return t, nil
+}
+
+func (m *mockRepository) FindTaskByICalUID(ctx context.Context, owner string, uid string) (*domain.Task, error) {
+	for _, t := range m.tasks {
+		if t.Owner == owner && t.ICalUID == uid {
+			return t, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *mockRepository) UpdateTaskStatus(ctx context.Context, id string, status string, changedBy string) error {
 	t, ok := m.tasks[id]
 	if !ok {
 		return repository.ErrNotFound
 	}
+	if status == "DONE" && t.Status != "DONE" {
+		t.CompletedAt = time.Now().Unix()
+	}
+	if status != t.Status {
+		m.history[id] = append(m.history[id], &domain.StatusTransition{
+			TaskId: id, From: t.Status, To: status, ChangedBy: changedBy, ChangedAt: time.Now().Unix(),
+		})
+	}
 	t.Status = status
 	return nil
 }
 
-func (m *mockRepository) DeleteTask(ctx context.Context, id string) error {
-	_, ok := m.tasks[id]
+func (m *mockRepository) GetTaskHistory(ctx context.Context, id string) ([]*domain.StatusTransition, error) {
+	return m.history[id], nil
+}
+
+func (m *mockRepository) DeleteTask(ctx context.Context, id string, deletedBy string) error {
+	t, ok := m.tasks[id]
 	if !ok {
 		return repository.ErrNotFound
 	}
 	delete(m.tasks, id)
+	t.DeletedAt = time.Now().Unix()
+	t.DeletedBy = deletedBy
+	m.trash[id] = t
 	return nil
 }
 
-func (m *mockRepository) DeleteDoneTasks(ctx context.Context) (int64, error) {
-	var count int64
-	for id, t := range m.tasks {
+func (m *mockRepository) ListTrash(ctx context.Context, pageSize int, pageToken string) ([]*domain.Task, string, error) {
+	return mockPage(m.trash, pageSize, pageToken)
+}
+
+func (m *mockRepository) GetDeletedTask(ctx context.Context, id string) (*domain.Task, error) {
+	t, ok := m.trash[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	task := *t
+	return &task, nil
+}
+
+func (m *mockRepository) UndeleteTask(ctx context.Context, id string) (*domain.Task, error) {
+	t, ok := m.trash[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	delete(m.trash, id)
+	t.DeletedAt, t.DeletedBy = 0, ""
+	m.tasks[id] = t
+	return t, nil
+}
+
+func (m *mockRepository) PurgeTask(ctx context.Context, id string) (*domain.Task, error) {
+	t, ok := m.trash[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	delete(m.trash, id)
+	return t, nil
+}
+
+// FindTasksByAge measures DONE tasks from CompletedAt and others from
+// CreatedAt, since the mock does not track status changes.
+func (m *mockRepository) FindTasksByAge(ctx context.Context, f repository.AgeFilter) ([]*domain.Task, error) {
+	var res []*domain.Task
+	if f.Trashed {
+		for _, t := range m.trash {
+			if t.DeletedAt <= f.Before && (f.Limit == 0 || len(res) < f.Limit) {
+				res = append(res, t)
+			}
+		}
+		return res, nil
+	}
+	for _, t := range m.tasks {
+		since := t.CreatedAt
 		if t.Status == "DONE" {
-			delete(m.tasks, id)
+			since = t.CompletedAt
+		}
+		if t.Status == f.Status && since <= f.Before && (f.Limit == 0 || len(res) < f.Limit) {
+			res = append(res, t)
+		}
+	}
+	return res, nil
+}
+
+func (m *mockRepository) DeleteTasksByAge(ctx context.Context, f repository.AgeFilter) ([]*domain.Task, error) {
+	tasks, _ := m.FindTasksByAge(ctx, f)
+	for _, t := range tasks {
+		delete(m.tasks, t.Id)
+		delete(m.trash, t.Id)
+	}
+	return tasks, nil
+}
+
+func (m *mockRepository) ArchiveTasksByAge(ctx context.Context, f repository.AgeFilter) ([]*domain.Task, error) {
+	tasks, _ := m.FindTasksByAge(ctx, f)
+	for _, t := range tasks {
+		m.ArchiveTask(ctx, t.Id)
+	}
+	return tasks, nil
+}
+
+func (m *mockRepository) ArchiveTask(ctx context.Context, id string) (*domain.Task, error) {
+	t, ok := m.tasks[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	delete(m.tasks, id)
+	t.ArchivedAt = time.Now().Unix()
+	m.archived[id] = t
+	return t, nil
+}
+
+func (m *mockRepository) ListArchivedTasks(ctx context.Context, pageSize int, pageToken string) ([]*domain.Task, string, error) {
+	return mockPage(m.archived, pageSize, pageToken)
+}
+
+// mockPage pages through tasks in ID order, using the last ID of a page as
+// the next page token.
+func mockPage(tasks map[string]*domain.Task, pageSize int, pageToken string) ([]*domain.Task, string, error) {
+	if pageToken != "" && !strings.HasPrefix(pageToken, "mock_id_") {
+		return nil, "", repository.ErrInvalidPageToken
+	}
+	if pageSize == 0 {
+		pageSize = 100
+	}
+
+	var ids []string
+	for id := range tasks {
+		if id > pageToken {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+
+	var next string
+	if len(ids) > pageSize {
+		ids = ids[:pageSize]
+		next = ids[pageSize-1]
+	}
+	var res []*domain.Task
+	for _, id := range ids {
+		res = append(res, tasks[id])
+	}
+	return res, next, nil
+}
+
+func (m *mockRepository) GetArchivedTask(ctx context.Context, id string) (*domain.Task, error) {
+	t, ok := m.archived[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	task := *t
+	return &task, nil
+}
+
+func (m *mockRepository) RestoreTask(ctx context.Context, id string) (*domain.Task, error) {
+	t, ok := m.archived[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	if _, ok := m.tasks[id]; ok {
+		return nil, repository.ErrAlreadyExists
+	}
+	delete(m.archived, id)
+	t.ArchivedAt = 0
+	m.tasks[id] = t
+	return t, nil
+}
+
+func (m *mockRepository) GetTasksByOwner(ctx context.Context, owner string) ([]*domain.Task, error) {
+	var tasks []*domain.Task
+	for _, t := range m.tasks {
+		if t.Owner == owner {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks, nil
+}
+
+func (m *mockRepository) CountTasks(ctx context.Context, owner string) (int64, error) {
+	var count int64
+	for _, t := range m.tasks {
+		if t.Owner == owner {
 			count++
 		}
 	}
 	return count, nil
 }
 
+func (m *mockRepository) ReserveTasks(ctx context.Context, owner string, n int) (int64, func(), error) {
+	count, err := m.CountTasks(ctx, owner)
+	return count, func() {}, err
+}
+
+func (m *mockRepository) SearchTasks(ctx context.Context, q repository.SearchQuery) ([]*repository.SearchHit, error) {
+	index := search.NewIndex()
+	for _, t := range m.tasks {
+		index.Add(t.Id, t.Title, t.Description)
+	}
+	var hits []*repository.SearchHit
+	for _, h := range index.Search(q.Text) {
+		t := m.tasks[h.ID]
+		if len(q.Statuses) > 0 && !slices.Contains(q.Statuses, t.Status) {
+			continue
+		}
+		if slices.ContainsFunc(q.Tags, func(tag string) bool {
+			return !search.HasTag(t.Title, tag) && !search.HasTag(t.Description, tag)
+		}) {
+			continue
+		}
+		hits = append(hits, &repository.SearchHit{Task: t, Score: h.Score})
+	}
+	if q.Limit > 0 && len(hits) > q.Limit {
+		hits = hits[:q.Limit]
+	}
+	return hits, nil
+}
+
+func (m *mockRepository) GetTaskStats(ctx context.Context, q domain.StatsQuery) (*domain.TaskStats, error) {
+	var tasks []*domain.Task
+	for _, t := range m.tasks {
+		tasks = append(tasks, t)
+	}
+	return domain.ComputeStats(tasks, q), nil
+}
+
+func (m *mockRepository) ForEachTask(ctx context.Context, fn func(*domain.Task) error) error {
+	for _, t := range m.tasks {
+		if err := fn(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockRepository) BatchCreateTasks(ctx context.Context, tasks []*domain.Task, allOrNothing bool) ([]error, error) {
+	errs := make([]error, len(tasks))
+	for i, task := range tasks {
+		if task.Id == "" {
+			m.CreateTask(ctx, task)
+			continue
+		}
+		if _, ok := m.tasks[task.Id]; ok {
+			errs[i] = repository.ErrAlreadyExists
+			continue
+		}
+		m.tasks[task.Id] = task
+	}
+	return errs, nil
+}
+
+func (m *mockRepository) BatchUpdateTaskStatus(ctx context.Context, updates []repository.StatusUpdate, changedBy string, allOrNothing bool) ([]error, error) {
+	errs := make([]error, len(updates))
+	for i, u := range updates {
+		if _, ok := m.tasks[u.ID]; !ok {
+			errs[i] = repository.ErrNotFound
+		}
+	}
+	if abortBatch(errs, allOrNothing) {
+		return errs, nil
+	}
+	for i, u := range updates {
+		if errs[i] == nil {
+			m.tasks[u.ID].Status = u.Status
+		}
+	}
+	return errs, nil
+}
+
+func (m *mockRepository) BatchDeleteTasks(ctx context.Context, ids []string, deletedBy string, allOrNothing bool) ([]error, error) {
+	errs := make([]error, len(ids))
+	for i, id := range ids {
+		if _, ok := m.tasks[id]; !ok {
+			errs[i] = repository.ErrNotFound
+		}
+	}
+	if abortBatch(errs, allOrNothing) {
+		return errs, nil
+	}
+	for i, id := range ids {
+		if errs[i] == nil {
+			m.DeleteTask(ctx, id, deletedBy)
+		}
+	}
+	return errs, nil
+}
+
+// abortBatch marks the good items of a failed all-or-nothing batch as aborted.
+func abortBatch(errs []error, allOrNothing bool) bool {
+	if !allOrNothing {
+		return false
+	}
+	failed := false
+	for _, err := range errs {
+		if err != nil {
+			failed = true
+		}
+	}
+	if failed {
+		for i := range errs {
+			if errs[i] == nil {
+				errs[i] = repository.ErrBatchAborted
+			}
+		}
+	}
+	return failed
+}
+
 func TestCreateTask(t *testing.T) {
 	repo := newMockRepository()
 	s := NewToDoServer(repo)
@@ -172,3 +533,1379 @@ func TestDeleteTask(t *testing.T) {
 		t.Errorf("Expected 0 tasks, got %d", len(tasks))
 	}
 }
+
+func TestCreateTaskQuota(t *testing.T) {
+	repo := newMockRepository()
+	s := NewToDoServer(repo, WithMaxTasksPerTenant(1))
+	ctx := auth.NewContext(context.Background(), "alice")
+
+	_, err := s.CreateTask(ctx, &proto.CreateTaskRequest{Title: "Task 1"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+
+	_, err = s.CreateTask(ctx, &proto.CreateTaskRequest{Title: "Task 2"})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected ResourceExhausted, got %v", err)
+	}
+
+	_, err = s.CreateTask(auth.NewContext(context.Background(), "bob"), &proto.CreateTaskRequest{Title: "Task 3"})
+	if err != nil {
+		t.Errorf("Expected another tenant to be unaffected, got %v", err)
+	}
+}
+
+func TestCreateTaskQuotaConcurrent(t *testing.T) {
+	log, err := repository.NewFileEventLog(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileEventLog failed: %v", err)
+	}
+	repo, err := repository.NewEventSourcedRepository(context.Background(), log)
+	if err != nil {
+		t.Fatalf("NewEventSourcedRepository failed: %v", err)
+	}
+	s := NewToDoServer(repo, WithMaxTasksPerTenant(5))
+	ctx := auth.NewContext(context.Background(), "alice")
+
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			<-start
+			s.CreateTask(ctx, &proto.CreateTaskRequest{Title: fmt.Sprintf("Task %d", i)})
+		}()
+		go func() {
+			defer wg.Done()
+			<-start
+			s.BatchCreateTasks(ctx, &proto.BatchCreateTasksRequest{Requests: []*proto.CreateTaskRequest{
+				{Title: fmt.Sprintf("Batch %d a", i)},
+				{Title: fmt.Sprintf("Batch %d b", i)},
+			}})
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if n, _ := repo.CountTasks(ctx, "alice"); n > 5 {
+		t.Fatalf("Expected at most 5 tasks, got %d", n)
+	}
+
+	// Concurrent calls may leave room unused, but none of it stays reserved.
+	for i := 0; ; i++ {
+		if _, err := s.CreateTask(ctx, &proto.CreateTaskRequest{Title: fmt.Sprintf("Later %d", i)}); err != nil {
+			break
+		}
+	}
+	if n, _ := repo.CountTasks(ctx, "alice"); n != 5 {
+		t.Errorf("Expected the quota of 5 tasks to be filled, got %d", n)
+	}
+}
+
+func TestDeleteTaskNotFound(t *testing.T) {
+	s := NewToDoServer(newMockRepository())
+
+	_, err := s.DeleteTask(context.Background(), &proto.DeleteTaskRequest{Id: "missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound, got %v", err)
+	}
+}
+
+func TestGetTask(t *testing.T) {
+	repo := newMockRepository()
+	s := NewToDoServer(repo)
+
+	createRes, err := s.CreateTask(context.Background(), &proto.CreateTaskRequest{
+		Title:       "Task 1",
+		Description: "First task",
+	})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+
+	res, err := s.GetTask(context.Background(), &proto.GetTaskRequest{Id: createRes.Task.Id})
+	if err != nil {
+		t.Fatalf("GetTask failed: %v", err)
+	}
+
+	if res.Task.Title != "Task 1" {
+		t.Errorf("Expected title Task 1, got %s", res.Task.Title)
+	}
+}
+
+func TestUpdateTask(t *testing.T) {
+	repo := newMockRepository()
+	s := NewToDoServer(repo)
+
+	createRes, err := s.CreateTask(context.Background(), &proto.CreateTaskRequest{
+		Title:       "Task 1",
+		Description: "First task",
+	})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+
+	res, err := s.UpdateTask(context.Background(), &proto.UpdateTaskRequest{
+		Id:          createRes.Task.Id,
+		Title:       "Task 1 renamed",
+		Description: "Edited",
+	})
+	if err != nil {
+		t.Fatalf("UpdateTask failed: %v", err)
+	}
+
+	if res.Task.Title != "Task 1 renamed" || res.Task.Description != "Edited" {
+		t.Errorf("Expected updated title and description, got %s and %s", res.Task.Title, res.Task.Description)
+	}
+}
+
+func TestBatchCreateTasksQuota(t *testing.T) {
+	s := NewToDoServer(newMockRepository(), WithMaxTasksPerTenant(2))
+	ctx := auth.NewContext(context.Background(), "alice")
+
+	req := &proto.BatchCreateTasksRequest{
+		Requests: []*proto.CreateTaskRequest{{Title: "A"}, {Title: "B"}, {Title: "C"}},
+	}
+
+	req.AllOrNothing = true
+	_, err := s.BatchCreateTasks(ctx, req)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("Expected ResourceExhausted, got %v", err)
+	}
+
+	req.AllOrNothing = false
+	res, err := s.BatchCreateTasks(ctx, req)
+	if err != nil {
+		t.Fatalf("BatchCreateTasks failed: %v", err)
+	}
+
+	wantCodes := []codes.Code{codes.OK, codes.OK, codes.ResourceExhausted}
+	for i, want := range wantCodes {
+		if got := codes.Code(res.Results[i].Status.Code); got != want {
+			t.Errorf("Expected result %d to be %v, got %v", i, want, got)
+		}
+	}
+	if res.Results[0].Task.GetTitle() != "A" || res.Results[0].Id == "" {
+		t.Errorf("Expected created task A with an ID, got %v", res.Results[0])
+	}
+}
+
+func TestBatchDeleteTasks(t *testing.T) {
+	repo := newMockRepository()
+	s := NewToDoServer(repo)
+
+	createRes, err := s.CreateTask(context.Background(), &proto.CreateTaskRequest{Title: "Task 1"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	ids := []string{createRes.Task.Id, "missing"}
+
+	res, err := s.BatchDeleteTasks(context.Background(), &proto.BatchDeleteTasksRequest{Ids: ids, AllOrNothing: true})
+	if err != nil {
+		t.Fatalf("BatchDeleteTasks failed: %v", err)
+	}
+	if codes.Code(res.Results[0].Status.Code) != codes.Aborted || codes.Code(res.Results[1].Status.Code) != codes.NotFound {
+		t.Errorf("Expected Aborted and NotFound, got %v", res.Results)
+	}
+	if _, err := repo.GetTask(context.Background(), createRes.Task.Id); err != nil {
+		t.Errorf("Expected task to survive the aborted batch, got %v", err)
+	}
+
+	res, err = s.BatchDeleteTasks(context.Background(), &proto.BatchDeleteTasksRequest{Ids: ids})
+	if err != nil {
+		t.Fatalf("BatchDeleteTasks failed: %v", err)
+	}
+	if codes.Code(res.Results[0].Status.Code) != codes.OK || codes.Code(res.Results[1].Status.Code) != codes.NotFound {
+		t.Errorf("Expected OK and NotFound, got %v", res.Results)
+	}
+}
+
+type importStream struct {
+	grpc.ServerStream
+	reqs []*proto.ImportTasksRequest
+	res  *proto.ImportTasksResponse
+}
+
+func (s *importStream) Context() context.Context { return context.Background() }
+
+func (s *importStream) Recv() (*proto.ImportTasksRequest, error) {
+	if len(s.reqs) == 0 {
+		return nil, io.EOF
+	}
+	req := s.reqs[0]
+	s.reqs = s.reqs[1:]
+	return req, nil
+}
+
+func (s *importStream) SendAndClose(res *proto.ImportTasksResponse) error {
+	s.res = res
+	return nil
+}
+
+type exportStream struct {
+	grpc.ServerStream
+	tasks []*proto.Task
+}
+
+func (s *exportStream) Context() context.Context { return context.Background() }
+
+func (s *exportStream) Send(res *proto.ExportTasksResponse) error {
+	s.tasks = append(s.tasks, res.Task)
+	return nil
+}
+
+func TestImportExportTasks(t *testing.T) {
+	repo := newMockRepository()
+	s := NewToDoServer(repo)
+
+	stream := &importStream{reqs: []*proto.ImportTasksRequest{
+		{Task: &proto.Task{Id: "5f1d7e4b9c2a3b0012345678", Title: "Kept", Status: proto.Status_DONE, CreatedAt: 42}, PreserveIds: true},
+		{Task: &proto.Task{Title: ""}},
+		{Task: &proto.Task{Id: "5f1d7e4b9c2a3b0012345678", Title: "Duplicate"}, PreserveIds: true},
+	}}
+	if err := s.ImportTasks(stream); err != nil {
+		t.Fatalf("ImportTasks failed: %v", err)
+	}
+
+	if stream.res.Received != 3 || stream.res.Imported != 1 || stream.res.Failed != 2 {
+		t.Fatalf("Expected 3 received, 1 imported and 2 failed, got %v", stream.res)
+	}
+	wantCodes := []codes.Code{codes.InvalidArgument, codes.AlreadyExists}
+	for i, want := range wantCodes {
+		if got := codes.Code(stream.res.Errors[i].Status.Code); got != want {
+			t.Errorf("Expected error %d to be %v, got %v", i, want, got)
+		}
+	}
+
+	export := &exportStream{}
+	if err := s.ExportTasks(&proto.ExportTasksRequest{PreserveIds: true}, export); err != nil {
+		t.Fatalf("ExportTasks failed: %v", err)
+	}
+	if len(export.tasks) != 1 {
+		t.Fatalf("Expected 1 exported task, got %d", len(export.tasks))
+	}
+	task := export.tasks[0]
+	if task.Id != "5f1d7e4b9c2a3b0012345678" || task.CreatedAt != 42 || task.Status != proto.Status_DONE {
+		t.Errorf("Expected ID, created_at and status to be preserved, got %v", task)
+	}
+
+	export = &exportStream{}
+	if err := s.ExportTasks(&proto.ExportTasksRequest{}, export); err != nil {
+		t.Fatalf("ExportTasks failed: %v", err)
+	}
+	if export.tasks[0].Id != "" || export.tasks[0].CreatedAt != 0 {
+		t.Errorf("Expected ID and created_at to be cleared, got %v", export.tasks[0])
+	}
+}
+
+func TestExportsDropAssignedFields(t *testing.T) {
+	repo := newMockRepository()
+	completed := time.Date(2024, 5, 3, 12, 0, 0, 0, time.UTC).Unix()
+	repo.CreateTask(context.Background(), &domain.Task{Title: "Ship", Status: "DONE", CreatedAt: completed - 3600, CompletedAt: completed})
+	s := NewToDoServer(repo)
+
+	for _, preserve := range []bool{true, false} {
+		stream := &exportStream{}
+		if err := s.ExportTasks(&proto.ExportTasksRequest{PreserveIds: preserve}, stream); err != nil {
+			t.Fatalf("ExportTasks failed: %v", err)
+		}
+		doc, err := s.ExportDocument(context.Background(), &proto.ExportDocumentRequest{
+			Format:      proto.DocumentFormat_DOCUMENT_FORMAT_TODO_TXT,
+			PreserveIds: preserve,
+		})
+		if err != nil {
+			t.Fatalf("ExportDocument failed: %v", err)
+		}
+
+		streamed := stream.tasks[0].CompletedAt != 0
+		documented := strings.Contains(string(doc.Content), "2024-05-03")
+		if streamed != preserve || documented != preserve {
+			t.Errorf("With preserve_ids %v, expected completed_at kept %v, got %v in ExportTasks and %v in %q",
+				preserve, preserve, streamed, documented, doc.Content)
+		}
+	}
+}
+
+func TestImportDocument(t *testing.T) {
+	repo := newMockRepository()
+	s := NewToDoServer(repo)
+
+	req := &proto.ImportDocumentRequest{
+		Format:  proto.DocumentFormat_DOCUMENT_FORMAT_CSV,
+		Content: []byte("title,status\nBuy milk,done\n,todo\n"),
+		DryRun:  true,
+	}
+
+	res, err := s.ImportDocument(context.Background(), req)
+	if err != nil {
+		t.Fatalf("ImportDocument failed: %v", err)
+	}
+	if len(res.Tasks) != 1 || res.Imported != 0 || len(res.Problems) != 1 || res.Problems[0].Line != 3 {
+		t.Fatalf("Expected one valid task and a problem on line 3, got %v", res)
+	}
+	if tasks, _ := repo.GetAllTasks(context.Background()); len(tasks) != 0 {
+		t.Errorf("Expected a dry run to store nothing, got %d tasks", len(tasks))
+	}
+
+	req.DryRun = false
+	res, err = s.ImportDocument(context.Background(), req)
+	if err != nil {
+		t.Fatalf("ImportDocument failed: %v", err)
+	}
+	if res.Imported != 1 || res.Tasks[0].Status != proto.Status_DONE || res.Tasks[0].Id == "" {
+		t.Errorf("Expected the DONE task to be imported, got %v", res)
+	}
+
+	exported, err := s.ExportDocument(context.Background(), &proto.ExportDocumentRequest{Format: proto.DocumentFormat_DOCUMENT_FORMAT_MARKDOWN})
+	if err != nil {
+		t.Fatalf("ExportDocument failed: %v", err)
+	}
+	if !strings.Contains(string(exported.Content), "- [x] Buy milk") {
+		t.Errorf("Expected a ticked checklist item, got %q", exported.Content)
+	}
+}
+
+func TestImportCalendar(t *testing.T) {
+	repo := newMockRepository()
+	s := NewToDoServer(repo)
+	ctx := auth.NewContext(context.Background(), "alice")
+
+	ics := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VTODO\r\nUID:1@phone\r\nSUMMARY:Call Bob\r\nDUE;VALUE=DATE:20240503\r\nEND:VTODO\r\n" +
+		"BEGIN:VTODO\r\nUID:2@phone\r\nEND:VTODO\r\n" +
+		"END:VCALENDAR\r\n"
+
+	res, err := s.ImportCalendar(ctx, &proto.ImportCalendarRequest{Content: []byte(ics)})
+	if err != nil {
+		t.Fatalf("ImportCalendar failed: %v", err)
+	}
+	if res.Created != 1 || len(res.Problems) != 1 || res.Problems[0].Line != 7 {
+		t.Fatalf("Expected one task and a problem on line 7, got %v", res)
+	}
+
+	ics = strings.Replace(ics, "SUMMARY:Call Bob", "SUMMARY:Call Bob again\r\nSTATUS:COMPLETED", 1)
+	res, err = s.ImportCalendar(ctx, &proto.ImportCalendarRequest{Content: []byte(ics)})
+	if err != nil {
+		t.Fatalf("ImportCalendar failed: %v", err)
+	}
+	if res.Created != 0 || res.Updated != 1 {
+		t.Fatalf("Expected the re-import to update the task, got %v", res)
+	}
+
+	tasks, _ := repo.GetAllTasks(ctx)
+	if len(tasks) != 1 || tasks[0].Title != "Call Bob again" || tasks[0].Status != "DONE" || tasks[0].DueAt == 0 {
+		t.Errorf("Expected one updated DONE task with a due date, got %+v", tasks)
+	}
+}
+
+func TestGetCalendarFeed(t *testing.T) {
+	_, err := NewToDoServer(newMockRepository()).GetCalendarFeed(context.Background(), &proto.GetCalendarFeedRequest{})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition without a signer, got %v", err)
+	}
+
+	signer := calendar.NewSigner("secret")
+	s := NewToDoServer(newMockRepository(), WithCalendarFeed(signer, "https://todo.example.com/"))
+	res, err := s.GetCalendarFeed(auth.NewContext(context.Background(), "alice"), &proto.GetCalendarFeedRequest{})
+	if err != nil {
+		t.Fatalf("GetCalendarFeed failed: %v", err)
+	}
+	if res.Url != "https://todo.example.com"+signer.FeedPath("alice") {
+		t.Errorf("Unexpected feed URL %s", res.Url)
+	}
+}
+
+type mockWebhookRepository struct {
+	mu         sync.Mutex
+	webhooks   []*domain.Webhook
+	deliveries []*domain.WebhookDelivery
+	// enqueueErr, when set, fails EnqueueDeliveries.
+	enqueueErr error
+}
+
+func (m *mockWebhookRepository) CreateWebhook(ctx context.Context, w *domain.Webhook) (*domain.Webhook, error) {
+	w.Id = fmt.Sprintf("%024x", len(m.webhooks)+1)
+	m.webhooks = append(m.webhooks, w)
+	return w, nil
+}
+
+func (m *mockWebhookRepository) ListWebhooks(ctx context.Context, owner string) ([]*domain.Webhook, error) {
+	var res []*domain.Webhook
+	for _, w := range m.webhooks {
+		if w.Owner == owner {
+			res = append(res, w)
+		}
+	}
+	return res, nil
+}
+
+func (m *mockWebhookRepository) GetWebhook(ctx context.Context, id string) (*domain.Webhook, error) {
+	for _, w := range m.webhooks {
+		if w.Id == id {
+			return w, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *mockWebhookRepository) DeleteWebhook(ctx context.Context, owner string, id string) error {
+	for i, w := range m.webhooks {
+		if w.Id == id && w.Owner == owner {
+			m.webhooks = append(m.webhooks[:i], m.webhooks[i+1:]...)
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func (m *mockWebhookRepository) MatchWebhooks(ctx context.Context, owner string, event string) ([]*domain.Webhook, error) {
+	var res []*domain.Webhook
+	for _, w := range m.webhooks {
+		if w.Owner == owner && (len(w.Events) == 0 || slices.Contains(w.Events, event)) {
+			res = append(res, w)
+		}
+	}
+	return res, nil
+}
+
+func (m *mockWebhookRepository) EnqueueDeliveries(ctx context.Context, deliveries []*domain.WebhookDelivery) error {
+	if m.enqueueErr != nil {
+		return m.enqueueErr
+	}
+	for _, d := range deliveries {
+		if d.Id == "" {
+			d.Id = fmt.Sprintf("%024x", len(m.deliveries)+1)
+		}
+		if !slices.ContainsFunc(m.deliveries, func(queued *domain.WebhookDelivery) bool { return queued.Id == d.Id }) {
+			m.deliveries = append(m.deliveries, d)
+		}
+	}
+	return nil
+}
+
+func (m *mockWebhookRepository) ClaimDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.WebhookDelivery, error) {
+	claimed := m.deliveries[:min(limit, len(m.deliveries))]
+	m.deliveries = m.deliveries[len(claimed):]
+	return claimed, nil
+}
+
+func (m *mockWebhookRepository) CompleteDelivery(ctx context.Context, id string) error {
+	return nil
+}
+
+func (m *mockWebhookRepository) FailDelivery(ctx context.Context, d *domain.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deliveries = append(m.deliveries, d)
+	return nil
+}
+
+func TestWebhooks(t *testing.T) {
+	var mu sync.Mutex
+	var received []string
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := webhook.Verify("secret", r.Header.Get(webhook.HeaderSignature), body, time.Now(), time.Minute); err != nil {
+			t.Errorf("Expected a valid signature, got %v", err)
+		}
+		mu.Lock()
+		received = append(received, r.Header.Get(webhook.HeaderEvent))
+		mu.Unlock()
+	}))
+	defer receiver.Close()
+
+	hooks := &mockWebhookRepository{}
+	dispatcher := webhook.NewDispatcher(hooks, webhook.WithAllowedNetworks(netip.MustParsePrefix("127.0.0.0/8")))
+	s := NewToDoServer(newMockRepository(), WithWebhooks(hooks, dispatcher))
+	ctx := auth.NewContext(context.Background(), "alice")
+
+	_, err := s.CreateWebhook(ctx, &proto.CreateWebhookRequest{
+		Url:    "http://93.184.215.14/ignored",
+		Events: []proto.WebhookEvent{proto.WebhookEvent_WEBHOOK_EVENT_TASK_DELETED},
+	})
+	if err != nil {
+		t.Fatalf("CreateWebhook failed: %v", err)
+	}
+	res, err := s.CreateWebhook(ctx, &proto.CreateWebhookRequest{Url: receiver.URL, Secret: "secret"})
+	if err != nil {
+		t.Fatalf("CreateWebhook failed: %v", err)
+	}
+	if res.Secret != "secret" || res.Webhook.Url != receiver.URL {
+		t.Errorf("Unexpected webhook %v", res)
+	}
+
+	list, err := s.ListWebhooks(ctx, &proto.ListWebhooksRequest{})
+	if err != nil || len(list.Webhooks) != 2 {
+		t.Fatalf("Expected 2 webhooks, got %v (%v)", list, err)
+	}
+	if events := list.Webhooks[0].Events; len(events) != 1 || events[0] != proto.WebhookEvent_WEBHOOK_EVENT_TASK_DELETED {
+		t.Errorf("Expected the event filter to be kept, got %v", events)
+	}
+	if _, err := s.DeleteWebhook(ctx, &proto.DeleteWebhookRequest{Id: list.Webhooks[0].Id}); err != nil {
+		t.Fatalf("DeleteWebhook failed: %v", err)
+	}
+	if _, err := s.DeleteWebhook(auth.NewContext(context.Background(), "bob"), &proto.DeleteWebhookRequest{Id: res.Webhook.Id}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound deleting another owner's webhook, got %v", err)
+	}
+
+	publisher := WebhookPublisher(dispatcher)
+	task := &domain.Task{Id: "1", Title: "Ship", Status: "TODO", Owner: "alice"}
+	for i, event := range []string{domain.EventTaskCreated, domain.EventTaskStatusUpdated, domain.EventTaskDeleted} {
+		if err := publisher.Publish(ctx, outboxMessage(i+1, event, task)); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+	}
+	// Another owner's task is not reported.
+	publisher.Publish(ctx, outboxMessage(4, domain.EventTaskCreated, &domain.Task{Id: "2", Title: "Other", Owner: "bob"}))
+
+	if _, err := dispatcher.DeliverDue(context.Background()); err != nil {
+		t.Fatalf("DeliverDue failed: %v", err)
+	}
+	slices.Sort(received)
+	want := []string{domain.EventTaskCreated, domain.EventTaskDeleted, domain.EventTaskStatusUpdated}
+	if !slices.Equal(received, want) {
+		t.Errorf("Expected events %v, got %v", want, received)
+	}
+}
+
+// outboxMessage returns the outbox message the repository writes for event
+// on task.
+func outboxMessage(n int, event string, task *domain.Task) *domain.OutboxMessage {
+	payload := fmt.Sprintf(`{"id":%q,"title":%q,"status":%q,"owner":%q}`, task.Id, task.Title, task.Status, task.Owner)
+	return &domain.OutboxMessage{
+		Id:        fmt.Sprintf("%024x", n),
+		Event:     event,
+		TaskId:    task.Id,
+		Owner:     task.Owner,
+		Payload:   []byte(payload),
+		CreatedAt: time.Unix(1735689600, 0),
+	}
+}
+
+func TestWebhookPublisher(t *testing.T) {
+	hooks := &mockWebhookRepository{}
+	s := NewToDoServer(newMockRepository(), WithWebhooks(hooks, webhook.NewDispatcher(hooks)))
+	ctx := auth.NewContext(context.Background(), "alice")
+	if _, err := s.CreateWebhook(ctx, &proto.CreateWebhookRequest{Url: "https://93.184.215.14/hook"}); err != nil {
+		t.Fatalf("CreateWebhook failed: %v", err)
+	}
+
+	publisher := WebhookPublisher(webhook.NewDispatcher(hooks))
+	task := &domain.Task{Id: "1", Title: "Ship", Status: "DONE", Owner: "alice"}
+	created := outboxMessage(1, domain.EventTaskCreated, task)
+	for _, msg := range []*domain.OutboxMessage{
+		created,
+		// Redelivered by the relay.
+		created,
+		// Webhooks cannot subscribe to these.
+		outboxMessage(2, domain.EventTaskUpdated, task),
+		outboxMessage(3, domain.EventTaskArchived, task),
+	} {
+		if err := publisher.Publish(ctx, msg); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+	}
+
+	if len(hooks.deliveries) != 1 {
+		t.Fatalf("Expected 1 delivery, got %d", len(hooks.deliveries))
+	}
+	d := hooks.deliveries[0]
+	if d.Event != domain.EventTaskCreated || d.OccurredAt != created.CreatedAt.Unix() {
+		t.Errorf("Expected the task.created event as it occurred, got %+v", d)
+	}
+	var data proto.Task
+	if err := protojson.Unmarshal(d.Data, &data); err != nil || data.Id != "1" || data.Status != proto.Status_DONE {
+		t.Errorf("Expected the task as the API returns it, got %s (%v)", d.Data, err)
+	}
+
+	if err := publisher.Publish(ctx, &domain.OutboxMessage{Id: "x", Event: domain.EventTaskCreated, Payload: []byte("{")}); err == nil {
+		t.Error("Expected an undecodable message to be rejected")
+	}
+}
+
+// mockOutboxRepository hands out every unacknowledged message on each claim,
+// as if leases expired at once.
+type mockOutboxRepository struct {
+	messages []*domain.OutboxMessage
+}
+
+func (m *mockOutboxRepository) ClaimOutbox(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.OutboxMessage, error) {
+	return m.messages[:min(limit, len(m.messages))], nil
+}
+
+func (m *mockOutboxRepository) AckOutbox(ctx context.Context, ids []string) error {
+	m.messages = slices.DeleteFunc(m.messages, func(msg *domain.OutboxMessage) bool { return slices.Contains(ids, msg.Id) })
+	return nil
+}
+
+func TestWebhookPublisherRetriesFailedEnqueue(t *testing.T) {
+	var received atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+	}))
+	defer receiver.Close()
+
+	hooks := &mockWebhookRepository{}
+	dispatcher := webhook.NewDispatcher(hooks, webhook.WithAllowedNetworks(netip.MustParsePrefix("127.0.0.0/8")))
+	s := NewToDoServer(newMockRepository(), WithWebhooks(hooks, dispatcher))
+	ctx := auth.NewContext(context.Background(), "alice")
+	if _, err := s.CreateWebhook(ctx, &proto.CreateWebhookRequest{Url: receiver.URL}); err != nil {
+		t.Fatalf("CreateWebhook failed: %v", err)
+	}
+
+	outboxRepo := &mockOutboxRepository{messages: []*domain.OutboxMessage{
+		outboxMessage(1, domain.EventTaskCreated, &domain.Task{Id: "1", Title: "Ship", Owner: "alice"}),
+	}}
+	relay := outbox.NewRelay(outboxRepo, WebhookPublisher(dispatcher))
+
+	hooks.enqueueErr = errors.New("connection reset")
+	if n, err := relay.RelayPending(ctx); err == nil || n != 0 {
+		t.Fatalf("Expected the failed enqueue to be reported, got %d (%v)", n, err)
+	}
+	if len(outboxRepo.messages) != 1 {
+		t.Fatalf("Expected the event to stay in the outbox, got %d messages", len(outboxRepo.messages))
+	}
+
+	hooks.enqueueErr = nil
+	if n, err := relay.RelayPending(ctx); err != nil || n != 1 {
+		t.Fatalf("Expected the event to be published on retry, got %d (%v)", n, err)
+	}
+	if _, err := dispatcher.DeliverDue(context.Background()); err != nil {
+		t.Fatalf("DeliverDue failed: %v", err)
+	}
+	if received.Load() != 1 {
+		t.Errorf("Expected the event to be delivered once, got %d requests", received.Load())
+	}
+}
+
+func TestWebhooksFromEventLog(t *testing.T) {
+	log, err := repository.NewFileEventLog(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileEventLog failed: %v", err)
+	}
+	repo, err := repository.NewEventSourcedRepository(context.Background(), log)
+	if err != nil {
+		t.Fatalf("NewEventSourcedRepository failed: %v", err)
+	}
+	outboxRepo, err := repository.NewEventOutboxRepository(log)
+	if err != nil {
+		t.Fatalf("NewEventOutboxRepository failed: %v", err)
+	}
+
+	hooks := &mockWebhookRepository{}
+	dispatcher := webhook.NewDispatcher(hooks)
+	s := NewToDoServer(repo, WithWebhooks(hooks, dispatcher))
+	ctx := auth.NewContext(context.Background(), "alice")
+	if _, err := s.CreateWebhook(ctx, &proto.CreateWebhookRequest{Url: "https://93.184.215.14/hook"}); err != nil {
+		t.Fatalf("CreateWebhook failed: %v", err)
+	}
+	created, err := s.CreateTask(ctx, &proto.CreateTaskRequest{Title: "Ship"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+
+	relay := outbox.NewRelay(outboxRepo, WebhookPublisher(dispatcher))
+	if n, err := relay.RelayPending(ctx); err != nil || n != 1 {
+		t.Fatalf("Expected the task.created event to be published, got %d (%v)", n, err)
+	}
+	if len(hooks.deliveries) != 1 {
+		t.Fatalf("Expected 1 delivery, got %d", len(hooks.deliveries))
+	}
+	d := hooks.deliveries[0]
+	var data proto.Task
+	if err := protojson.Unmarshal(d.Data, &data); err != nil || d.Event != domain.EventTaskCreated || data.Id != created.Task.Id {
+		t.Errorf("Expected task.created for %s, got %+v (%v)", created.Task.Id, d, err)
+	}
+}
+
+func TestCreateWebhookRefusesInternalAddresses(t *testing.T) {
+	hooks := &mockWebhookRepository{}
+	s := NewToDoServer(newMockRepository(), WithWebhooks(hooks, webhook.NewDispatcher(hooks)))
+	ctx := auth.NewContext(context.Background(), "alice")
+
+	for _, url := range []string{"http://127.0.0.1:27017", "http://169.254.169.254/latest/meta-data/", "http://10.1.2.3/hook"} {
+		if _, err := s.CreateWebhook(ctx, &proto.CreateWebhookRequest{Url: url}); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument for %s, got %v", url, err)
+		}
+	}
+	if len(hooks.webhooks) != 0 {
+		t.Errorf("Expected no webhook to be stored, got %d", len(hooks.webhooks))
+	}
+}
+
+func TestWebhooksDisabled(t *testing.T) {
+	_, err := NewToDoServer(newMockRepository()).ListWebhooks(context.Background(), &proto.ListWebhooksRequest{})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition without webhooks, got %v", err)
+	}
+}
+
+func TestRetention(t *testing.T) {
+	repo := newMockRepository()
+	old := time.Now().Add(-31 * 24 * time.Hour).Unix()
+	repo.CreateTask(context.Background(), &domain.Task{Title: "Old", Status: "DONE", CreatedAt: old, CompletedAt: old})
+	repo.CreateTask(context.Background(), &domain.Task{Title: "Recent", Status: "DONE", CreatedAt: old, CompletedAt: time.Now().Unix()})
+
+	if c, err := NewToDoServer(repo).StartCronJob(); c != nil || err != nil {
+		t.Errorf("Expected no cron job without retention policies, got %v (%v)", c, err)
+	}
+	policies, _ := retention.ParsePolicies("DONE:delete:30d")
+	if _, err := NewToDoServer(repo, WithRetention("every day", policies)).StartCronJob(); err == nil {
+		t.Error("Expected an invalid schedule to be rejected")
+	}
+
+	s := NewToDoServer(repo, WithRetention("@every 1h", policies, retention.WithDryRun(true)))
+	s.applyRetention()
+	if tasks, _ := repo.GetAllTasks(context.Background()); len(tasks) != 2 {
+		t.Fatalf("Expected a dry run to keep both tasks, got %d", len(tasks))
+	}
+
+	s = NewToDoServer(repo, WithRetention("@every 1h", policies))
+	s.applyRetention()
+	if _, err := repo.GetTask(context.Background(), "mock_id_Old"); err == nil {
+		t.Error("Expected the task completed 31 days ago to be deleted")
+	}
+	if _, err := repo.GetTask(context.Background(), "mock_id_Recent"); err != nil {
+		t.Errorf("Expected the recently completed task to be kept, got %v", err)
+	}
+}
+
+func TestArchiveTasks(t *testing.T) {
+	repo := newMockRepository()
+	s := NewToDoServer(repo)
+	ctx := context.Background()
+	for _, title := range []string{"A", "B", "C"} {
+		repo.CreateTask(ctx, &domain.Task{Title: title, Status: "DONE"})
+	}
+
+	for _, id := range []string{"mock_id_A", "mock_id_B"} {
+		res, err := s.ArchiveTask(ctx, &proto.ArchiveTaskRequest{Id: id})
+		if err != nil {
+			t.Fatalf("ArchiveTask failed: %v", err)
+		}
+		if res.Task.ArchivedAt == 0 {
+			t.Errorf("Expected %s to report when it was archived", id)
+		}
+	}
+	if _, err := s.ArchiveTask(ctx, &proto.ArchiveTaskRequest{Id: "mock_id_A"}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound for a task that is already archived, got %v", err)
+	}
+	if tasks, _ := repo.GetAllTasks(ctx); len(tasks) != 1 {
+		t.Errorf("Expected 1 live task, got %d", len(tasks))
+	}
+
+	var listed []string
+	token := ""
+	for pages := 0; ; pages++ {
+		if pages == 3 {
+			t.Fatal("Expected two pages")
+		}
+		res, err := s.ListArchivedTasks(ctx, &proto.ListArchivedTasksRequest{PageSize: 1, PageToken: token})
+		if err != nil {
+			t.Fatalf("ListArchivedTasks failed: %v", err)
+		}
+		for _, task := range res.Tasks {
+			listed = append(listed, task.Id)
+		}
+		if token = res.NextPageToken; token == "" {
+			break
+		}
+	}
+	if strings.Join(listed, ",") != "mock_id_A,mock_id_B" {
+		t.Errorf("Expected mock_id_A,mock_id_B, got %v", listed)
+	}
+
+	if _, err := s.ListArchivedTasks(ctx, &proto.ListArchivedTasksRequest{PageSize: -1}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for a negative page size, got %v", err)
+	}
+	if _, err := s.ListArchivedTasks(ctx, &proto.ListArchivedTasksRequest{PageToken: "garbage"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for a bad page token, got %v", err)
+	}
+
+	res, err := s.RestoreTask(ctx, &proto.RestoreTaskRequest{Id: "mock_id_A"})
+	if err != nil {
+		t.Fatalf("RestoreTask failed: %v", err)
+	}
+	if res.Task.ArchivedAt != 0 || res.Task.Status != proto.Status_DONE {
+		t.Errorf("Expected the task back as it was, got %v", res.Task)
+	}
+	if _, err := repo.GetTask(ctx, "mock_id_A"); err != nil {
+		t.Errorf("Expected the restored task to be live, got %v", err)
+	}
+	if _, err := s.RestoreTask(ctx, &proto.RestoreTaskRequest{Id: "mock_id_A"}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound for a task that is not archived, got %v", err)
+	}
+}
+
+func TestTrash(t *testing.T) {
+	repo := newMockRepository()
+	s := NewToDoServer(repo)
+	ctx := auth.NewContext(context.Background(), "alice")
+	repo.CreateTask(ctx, &domain.Task{Title: "A", Status: "TODO"})
+	repo.CreateTask(ctx, &domain.Task{Title: "B", Status: "TODO"})
+
+	for _, id := range []string{"mock_id_A", "mock_id_B"} {
+		if _, err := s.DeleteTask(ctx, &proto.DeleteTaskRequest{Id: id}); err != nil {
+			t.Fatalf("DeleteTask failed: %v", err)
+		}
+	}
+	if _, err := s.GetTask(ctx, &proto.GetTaskRequest{Id: "mock_id_A"}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected a deleted task to be hidden, got %v", err)
+	}
+
+	res, err := s.ListTrash(ctx, &proto.ListTrashRequest{})
+	if err != nil {
+		t.Fatalf("ListTrash failed: %v", err)
+	}
+	if len(res.Tasks) != 2 || res.Tasks[0].DeletedBy != "alice" || res.Tasks[0].DeletedAt == 0 {
+		t.Fatalf("Expected both tasks in the trash, deleted by alice, got %v", res.Tasks)
+	}
+
+	undeleted, err := s.UndeleteTask(ctx, &proto.UndeleteTaskRequest{Id: "mock_id_A"})
+	if err != nil {
+		t.Fatalf("UndeleteTask failed: %v", err)
+	}
+	if undeleted.Task.DeletedAt != 0 {
+		t.Errorf("Expected the task to leave the trash, got %v", undeleted.Task)
+	}
+	if _, err := s.GetTask(ctx, &proto.GetTaskRequest{Id: "mock_id_A"}); err != nil {
+		t.Errorf("Expected the undeleted task to be visible, got %v", err)
+	}
+
+	if _, err := s.PurgeTask(ctx, &proto.PurgeTaskRequest{Id: "mock_id_A"}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected PurgeTask to refuse a task not in the trash, got %v", err)
+	}
+	if _, err := s.PurgeTask(ctx, &proto.PurgeTaskRequest{Id: "mock_id_B"}); err != nil {
+		t.Fatalf("PurgeTask failed: %v", err)
+	}
+	if _, err := s.UndeleteTask(ctx, &proto.UndeleteTaskRequest{Id: "mock_id_B"}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected a purged task to be gone, got %v", err)
+	}
+}
+
+func TestUndeleteAndRestoreCheckOwnerQuota(t *testing.T) {
+	repo := newMockRepository()
+	s := NewToDoServer(repo, WithMaxTasksPerTenant(1))
+	alice := auth.NewContext(context.Background(), "alice")
+	bob := auth.NewContext(context.Background(), "bob")
+	repo.CreateTask(alice, &domain.Task{Title: "Deleted", Status: "TODO", Owner: "alice"})
+	repo.CreateTask(alice, &domain.Task{Title: "Archived", Status: "DONE", Owner: "alice"})
+	repo.DeleteTask(alice, "mock_id_Deleted", "alice")
+	repo.ArchiveTask(alice, "mock_id_Archived")
+	repo.CreateTask(alice, &domain.Task{Title: "Live", Status: "TODO", Owner: "alice"})
+
+	if _, err := s.UndeleteTask(bob, &proto.UndeleteTaskRequest{Id: "mock_id_Deleted"}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected UndeleteTask to check the owner's quota, got %v", err)
+	}
+	if _, err := s.RestoreTask(bob, &proto.RestoreTaskRequest{Id: "mock_id_Archived"}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected RestoreTask to check the owner's quota, got %v", err)
+	}
+	if n, _ := repo.CountTasks(alice, "alice"); n != 1 {
+		t.Errorf("Expected alice to keep 1 live task, got %d", n)
+	}
+
+	if _, err := s.UndeleteTask(bob, &proto.UndeleteTaskRequest{Id: "mock_id_Missing"}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound for a task not in the trash, got %v", err)
+	}
+}
+
+type mockAuditRepository struct {
+	entries []*domain.AuditEntry
+}
+
+func (m *mockAuditRepository) AppendAudit(ctx context.Context, entries ...*domain.AuditEntry) error {
+	for _, e := range entries {
+		e.Id = fmt.Sprintf("%024x", len(m.entries)+1)
+		m.entries = append(m.entries, e)
+	}
+	return nil
+}
+
+func (m *mockAuditRepository) ListAuditEntries(ctx context.Context, f repository.AuditFilter, pageSize int, pageToken string) ([]*domain.AuditEntry, string, error) {
+	var res []*domain.AuditEntry
+	for _, e := range m.entries {
+		if (f.TaskID == "" || e.TaskId == f.TaskID) && (f.Actor == "" || e.Actor == f.Actor) {
+			res = append(res, e)
+		}
+	}
+	return res, "", nil
+}
+
+func TestAuditLog(t *testing.T) {
+	auditLog := &mockAuditRepository{}
+	s := NewToDoServer(newMockRepository(), WithAudit(auditLog))
+	ctx := auth.NewContext(context.Background(), "alice")
+
+	created, err := s.CreateTask(ctx, &proto.CreateTaskRequest{Title: "A"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	id := created.Task.Id
+	if _, err := s.UpdateTaskStatus(ctx, &proto.UpdateTaskStatusRequest{Id: id, Status: proto.Status_IN_PROGRESS}); err != nil {
+		t.Fatalf("UpdateTaskStatus failed: %v", err)
+	}
+	if _, err := s.DeleteTask(auth.NewContext(context.Background(), "bob"), &proto.DeleteTaskRequest{Id: id}); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+
+	res, err := s.ListAuditEntries(ctx, &proto.ListAuditEntriesRequest{TaskId: id})
+	if err != nil {
+		t.Fatalf("ListAuditEntries failed: %v", err)
+	}
+	if len(res.Entries) != 3 {
+		t.Fatalf("Expected 3 audit entries, got %d", len(res.Entries))
+	}
+
+	methods := []string{"CreateTask", "UpdateTaskStatus", "DeleteTask"}
+	actors := []string{"alice", "alice", "bob"}
+	for i, e := range res.Entries {
+		if e.Method != methods[i] || e.Actor != actors[i] {
+			t.Errorf("Expected entry %d to be %s by %s, got %s by %s", i, methods[i], actors[i], e.Method, e.Actor)
+		}
+	}
+
+	changes := res.Entries[1].Changes
+	if len(changes) != 1 || changes[0].Field != "status" || changes[0].Before != "TODO" || changes[0].After != "IN_PROGRESS" {
+		t.Errorf("Expected only the status to change from TODO to IN_PROGRESS, got %v", changes)
+	}
+	deleted := map[string]string{}
+	for _, c := range res.Entries[2].Changes {
+		deleted[c.Field] = c.After
+	}
+	if deleted["deleted_by"] != "bob" || deleted["deleted_at"] == "" {
+		t.Errorf("Expected the deletion to record deleted_by and deleted_at, got %v", res.Entries[2].Changes)
+	}
+
+	if _, err := s.ListAuditEntries(ctx, &proto.ListAuditEntriesRequest{StartTime: 2, EndTime: 1}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for an inverted time range, got %v", err)
+	}
+	if _, err := NewToDoServer(newMockRepository()).ListAuditEntries(ctx, &proto.ListAuditEntriesRequest{}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition without an audit log, got %v", err)
+	}
+}
+
+func TestAuditLogBatchesAndImports(t *testing.T) {
+	auditLog := &mockAuditRepository{}
+	repo := newMockRepository()
+	s := NewToDoServer(repo, WithAudit(auditLog))
+	ctx := auth.NewContext(context.Background(), "alice")
+
+	if _, err := s.BatchCreateTasks(ctx, &proto.BatchCreateTasksRequest{Requests: []*proto.CreateTaskRequest{{Title: "A"}, {Title: "B"}}}); err != nil {
+		t.Fatalf("BatchCreateTasks failed: %v", err)
+	}
+	if _, err := s.BatchUpdateTaskStatus(ctx, &proto.BatchUpdateTaskStatusRequest{Requests: []*proto.UpdateTaskStatusRequest{
+		{Id: "mock_id_A", Status: proto.Status_DONE},
+		{Id: "mock_id_Missing", Status: proto.Status_DONE},
+	}}); err != nil {
+		t.Fatalf("BatchUpdateTaskStatus failed: %v", err)
+	}
+	if _, err := s.BatchDeleteTasks(ctx, &proto.BatchDeleteTasksRequest{Ids: []string{"mock_id_A", "mock_id_B", "mock_id_Missing"}}); err != nil {
+		t.Fatalf("BatchDeleteTasks failed: %v", err)
+	}
+	if _, err := s.UndeleteTask(ctx, &proto.UndeleteTaskRequest{Id: "mock_id_B"}); err != nil {
+		t.Fatalf("UndeleteTask failed: %v", err)
+	}
+	content := []byte("- [ ] Imported\n")
+	if _, err := s.ImportDocument(ctx, &proto.ImportDocumentRequest{Format: proto.DocumentFormat_DOCUMENT_FORMAT_MARKDOWN, Content: content}); err != nil {
+		t.Fatalf("ImportDocument failed: %v", err)
+	}
+
+	res, err := s.ListAuditEntries(ctx, &proto.ListAuditEntriesRequest{})
+	if err != nil {
+		t.Fatalf("ListAuditEntries failed: %v", err)
+	}
+	var got []string
+	for _, e := range res.Entries {
+		got = append(got, e.Method+" "+e.TaskId)
+	}
+	want := []string{
+		"BatchCreateTasks mock_id_A",
+		"BatchCreateTasks mock_id_B",
+		"BatchUpdateTaskStatus mock_id_A",
+		"BatchDeleteTasks mock_id_A",
+		"BatchDeleteTasks mock_id_B",
+		"UndeleteTask mock_id_B",
+		"ImportDocument mock_id_Imported",
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("Expected entries %v, got %v", want, got)
+	}
+
+	changes := map[string]string{}
+	for _, c := range res.Entries[3].Changes {
+		changes[c.Field] = c.Before + " -> " + c.After
+	}
+	if changes["deleted_by"] != " -> alice" || changes["status"] != "" {
+		t.Errorf("Expected the batch deletion to record deleted_by only, got %v", res.Entries[3].Changes)
+	}
+	if c := res.Entries[2].Changes; len(c) == 0 || c[0].Field != "status" || c[0].Before != "TODO" || c[0].After != "DONE" {
+		t.Errorf("Expected the batch update to record the status change, got %v", c)
+	}
+}
+
+func TestGetTaskHistory(t *testing.T) {
+	repo := newMockRepository()
+	s := NewToDoServer(repo)
+	ctx := auth.NewContext(context.Background(), "alice")
+	now := time.Now().Unix()
+	repo.CreateTask(ctx, &domain.Task{Title: "A", Status: "TODO", CreatedAt: now - 1000})
+
+	if _, err := s.UpdateTaskStatus(ctx, &proto.UpdateTaskStatusRequest{Id: "mock_id_A", Status: proto.Status_IN_PROGRESS}); err != nil {
+		t.Fatalf("UpdateTaskStatus failed: %v", err)
+	}
+	res, err := s.GetTaskHistory(ctx, &proto.GetTaskHistoryRequest{Id: "mock_id_A"})
+	if err != nil {
+		t.Fatalf("GetTaskHistory failed: %v", err)
+	}
+	if len(res.Transitions) != 1 || res.Transitions[0].From != proto.Status_TODO ||
+		res.Transitions[0].To != proto.Status_IN_PROGRESS || res.Transitions[0].ChangedBy != "alice" {
+		t.Fatalf("Expected a TODO to IN_PROGRESS transition by alice, got %v", res.Transitions)
+	}
+
+	// Replace the history with one at known times.
+	repo.(*mockRepository).tasks["mock_id_A"].Status = "DONE"
+	repo.(*mockRepository).history["mock_id_A"] = []*domain.StatusTransition{
+		{From: "TODO", To: "IN_PROGRESS", ChangedAt: now - 900},
+		{From: "IN_PROGRESS", To: "PAUSED", ChangedAt: now - 700},
+		{From: "PAUSED", To: "IN_PROGRESS", ChangedAt: now - 600},
+		{From: "IN_PROGRESS", To: "DONE", ChangedAt: now - 500},
+	}
+	res, err = s.GetTaskHistory(ctx, &proto.GetTaskHistoryRequest{Id: "mock_id_A"})
+	if err != nil {
+		t.Fatalf("GetTaskHistory failed: %v", err)
+	}
+
+	want := map[proto.Status]int64{
+		proto.Status_TODO:        100,
+		proto.Status_IN_PROGRESS: 300,
+		proto.Status_PAUSED:      100,
+		proto.Status_DONE:        500,
+	}
+	if len(res.Durations) != len(want) {
+		t.Fatalf("Expected %d durations, got %v", len(want), res.Durations)
+	}
+	for _, d := range res.Durations {
+		// The time in the current status runs until the call.
+		if got := d.Seconds; got < want[d.Status] || got > want[d.Status]+5 {
+			t.Errorf("Expected %d seconds in %s, got %d", want[d.Status], d.Status, got)
+		}
+	}
+	if res.CycleTimeSeconds != 400 {
+		t.Errorf("Expected a cycle time of 400 seconds, got %d", res.CycleTimeSeconds)
+	}
+
+	if _, err := s.GetTaskHistory(ctx, &proto.GetTaskHistoryRequest{Id: "missing"}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound for a missing task, got %v", err)
+	}
+}
+
+func TestSearchTasks(t *testing.T) {
+	repo := newMockRepository()
+	s := NewToDoServer(repo)
+	ctx := context.Background()
+	repo.CreateTask(ctx, &domain.Task{Title: "Deploy website", Description: "Ship the new landing page +website", Status: "TODO"})
+	repo.CreateTask(ctx, &domain.Task{Title: "Write report", Description: "Summarize the website deploy", Status: "DONE"})
+	repo.CreateTask(ctx, &domain.Task{Title: "Buy milk", Status: "TODO"})
+
+	res, err := s.SearchTasks(ctx, &proto.SearchTasksRequest{Query: "deploy"})
+	if err != nil {
+		t.Fatalf("SearchTasks failed: %v", err)
+	}
+	if len(res.Results) != 2 || res.Results[0].Task.Id != "mock_id_Deploy website" {
+		t.Fatalf("Expected the title match first of 2 results, got %v", res.Results)
+	}
+	snippet := res.Results[0].Snippets[0]
+	if snippet.Field != "title" || len(snippet.Highlights) != 1 ||
+		snippet.Highlights[0].Start != 0 || snippet.Highlights[0].End != 6 {
+		t.Fatalf("Expected \"Deploy\" highlighted in the title, got %v", snippet)
+	}
+	if snippets := res.Results[1].Snippets; len(snippets) != 1 || snippets[0].Field != "description" {
+		t.Fatalf("Expected a description snippet, got %v", snippets)
+	}
+
+	res, err = s.SearchTasks(ctx, &proto.SearchTasksRequest{Query: "deploy", Statuses: []proto.Status{proto.Status_DONE}})
+	if err != nil {
+		t.Fatalf("SearchTasks failed: %v", err)
+	}
+	if len(res.Results) != 1 || res.Results[0].Task.Id != "mock_id_Write report" {
+		t.Fatalf("Expected only the DONE task, got %v", res.Results)
+	}
+
+	res, err = s.SearchTasks(ctx, &proto.SearchTasksRequest{Query: "website", Tags: []string{"+Website"}})
+	if err != nil {
+		t.Fatalf("SearchTasks failed: %v", err)
+	}
+	if len(res.Results) != 1 || res.Results[0].Task.Id != "mock_id_Deploy website" {
+		t.Fatalf("Expected only the tagged task, got %v", res.Results)
+	}
+
+	if _, err := s.SearchTasks(ctx, &proto.SearchTasksRequest{Query: "deploy", PageSize: -1}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument for a negative page size, got %v", err)
+	}
+}
+
+func TestGetAllTasksFilter(t *testing.T) {
+	repo := newMockRepository()
+	s := NewToDoServer(repo)
+	ctx := context.Background()
+	jan1 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	repo.CreateTask(ctx, &domain.Task{Title: "Deploy website", Status: "IN_PROGRESS", CreatedAt: jan1 + 60})
+	repo.CreateTask(ctx, &domain.Task{Title: "Deploy API", Status: "IN_PROGRESS", CreatedAt: jan1 - 60})
+	repo.CreateTask(ctx, &domain.Task{Title: "Write report", Status: "TODO", CreatedAt: jan1 + 60})
+
+	res, err := s.GetAllTasks(ctx, &proto.GetAllTasksRequest{Filter: `status:IN_PROGRESS AND created>2026-01-01 AND title~"deploy"`})
+	if err != nil {
+		t.Fatalf("GetAllTasks failed: %v", err)
+	}
+	if len(res.Tasks) != 1 || res.Tasks[0].Title != "Deploy website" {
+		t.Fatalf("Expected only \"Deploy website\", got %v", res.Tasks)
+	}
+
+	_, err = s.GetAllTasks(ctx, &proto.GetAllTasksRequest{Filter: "status:IN_PROGRESS AND colour=red"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `column 24`) || !strings.Contains(err.Error(), `"colour"`) {
+		t.Errorf("Expected the error to point at colour, got %v", err)
+	}
+	details := status.Convert(err).Details()
+	if len(details) != 1 {
+		t.Fatalf("Expected a BadRequest detail, got %v", details)
+	}
+	if br, ok := details[0].(*errdetails.BadRequest); !ok || br.FieldViolations[0].Field != "filter" {
+		t.Errorf("Expected a violation of the filter field, got %v", details[0])
+	}
+}
+
+func TestGetAllTasksAsOf(t *testing.T) {
+	log, err := repository.NewFileEventLog(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileEventLog failed: %v", err)
+	}
+	repo, err := repository.NewEventSourcedRepository(context.Background(), log)
+	if err != nil {
+		t.Fatalf("NewEventSourcedRepository failed: %v", err)
+	}
+	s := NewToDoServer(repo)
+	ctx := auth.NewContext(context.Background(), "alice")
+
+	created, err := s.CreateTask(ctx, &proto.CreateTaskRequest{Title: "Ship it"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	createdAt := time.Now().Unix()
+	// as_of has whole seconds, so the change must land in a later one.
+	time.Sleep(time.Until(time.Unix(createdAt+1, 0)))
+	if _, err := s.UpdateTaskStatus(ctx, &proto.UpdateTaskStatusRequest{Id: created.Task.Id, Status: proto.Status_DONE}); err != nil {
+		t.Fatalf("UpdateTaskStatus failed: %v", err)
+	}
+
+	res, err := s.GetAllTasks(ctx, &proto.GetAllTasksRequest{AsOf: createdAt})
+	if err != nil {
+		t.Fatalf("GetAllTasks failed: %v", err)
+	}
+	if len(res.Tasks) != 1 || res.Tasks[0].Status != proto.Status_TODO {
+		t.Errorf("Expected the task still to do, got %v", res.Tasks)
+	}
+	res, err = s.GetAllTasks(ctx, &proto.GetAllTasksRequest{AsOf: createdAt - 3600})
+	if err != nil {
+		t.Fatalf("GetAllTasks failed: %v", err)
+	}
+	if len(res.Tasks) != 0 {
+		t.Errorf("Expected no tasks before the first one was created, got %v", res.Tasks)
+	}
+	res, err = s.GetAllTasks(ctx, &proto.GetAllTasksRequest{AsOf: createdAt, Filter: "status:DONE"})
+	if err != nil {
+		t.Fatalf("GetAllTasks failed: %v", err)
+	}
+	if len(res.Tasks) != 0 {
+		t.Errorf("Expected the filter to apply to the past tasks, got %v", res.Tasks)
+	}
+
+	_, err = NewToDoServer(newMockRepository()).GetAllTasks(ctx, &proto.GetAllTasksRequest{AsOf: createdAt})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition without the event-sourced store, got %v", err)
+	}
+}
+
+type mockViewRepository struct {
+	views []*domain.SavedView
+}
+
+func (m *mockViewRepository) CreateView(ctx context.Context, view *domain.SavedView) (*domain.SavedView, error) {
+	view.Id = fmt.Sprintf("%024x", len(m.views)+1)
+	m.views = append(m.views, view)
+	return view, nil
+}
+
+func (m *mockViewRepository) GetView(ctx context.Context, id string) (*domain.SavedView, error) {
+	for _, v := range m.views {
+		if v.Id == id {
+			return v, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *mockViewRepository) ListViews(ctx context.Context, owner string) ([]*domain.SavedView, error) {
+	var res []*domain.SavedView
+	for _, v := range m.views {
+		if v.Owner == owner || v.Shared {
+			res = append(res, v)
+		}
+	}
+	return res, nil
+}
+
+func (m *mockViewRepository) UpdateView(ctx context.Context, view *domain.SavedView) (*domain.SavedView, error) {
+	for i, v := range m.views {
+		if v.Id == view.Id && v.Owner == view.Owner {
+			view.CreatedAt = v.CreatedAt
+			m.views[i] = view
+			return view, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *mockViewRepository) DeleteView(ctx context.Context, owner string, id string) error {
+	for i, v := range m.views {
+		if v.Id == id && v.Owner == owner {
+			m.views = slices.Delete(m.views, i, i+1)
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func TestSavedViews(t *testing.T) {
+	repo := newMockRepository()
+	views := &mockViewRepository{}
+	s := NewToDoServer(repo, WithViews(views))
+	alice := auth.NewContext(context.Background(), "alice")
+	bob := auth.NewContext(context.Background(), "bob")
+	repo.CreateTask(alice, &domain.Task{Title: "B", Status: "TODO", DueAt: 200})
+	repo.CreateTask(alice, &domain.Task{Title: "A", Status: "TODO", DueAt: 100})
+	repo.CreateTask(alice, &domain.Task{Title: "C", Status: "DONE"})
+
+	_, err := s.CreateView(alice, &proto.CreateViewRequest{Name: "Bad", Filter: "status=TODO AND colour=red"})
+	if status.Code(err) != codes.InvalidArgument || !strings.Contains(err.Error(), `"colour"`) {
+		t.Fatalf("Expected InvalidArgument pointing at colour, got %v", err)
+	}
+	_, err = s.CreateView(alice, &proto.CreateViewRequest{Name: "Bad", OrderBy: "due sideways"})
+	if status.Code(err) != codes.InvalidArgument || !strings.Contains(err.Error(), "order_by") {
+		t.Fatalf("Expected InvalidArgument for order_by, got %v", err)
+	}
+
+	created, err := s.CreateView(alice, &proto.CreateViewRequest{Name: "To do", Filter: "status:todo", OrderBy: "due"})
+	if err != nil {
+		t.Fatalf("CreateView failed: %v", err)
+	}
+	if v := created.View; v.Owner != "alice" || v.FilterVersion != filter.Version || v.Shared {
+		t.Fatalf("Unexpected view %v", v)
+	}
+	id := created.View.Id
+
+	res, err := s.ListTasksByView(alice, &proto.ListTasksByViewRequest{Id: id})
+	if err != nil {
+		t.Fatalf("ListTasksByView failed: %v", err)
+	}
+	if len(res.Tasks) != 2 || res.Tasks[0].Title != "A" || res.Tasks[1].Title != "B" {
+		t.Fatalf("Expected A then B, got %v", res.Tasks)
+	}
+
+	// Bob cannot see alice's view until she shares it, and cannot change
+	// it then.
+	if _, err := s.ListTasksByView(bob, &proto.ListTasksByViewRequest{Id: id}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound for another user's view, got %v", err)
+	}
+	if _, err := s.UpdateView(alice, &proto.UpdateViewRequest{Id: id, Name: "To do", Filter: "status:todo", OrderBy: "due desc", Shared: true}); err != nil {
+		t.Fatalf("UpdateView failed: %v", err)
+	}
+	res, err = s.ListTasksByView(bob, &proto.ListTasksByViewRequest{Id: id})
+	if err != nil {
+		t.Fatalf("ListTasksByView failed: %v", err)
+	}
+	if len(res.Tasks) != 2 || res.Tasks[0].Title != "B" {
+		t.Errorf("Expected B first in the shared view, got %v", res.Tasks)
+	}
+	if list, err := s.ListViews(bob, &proto.ListViewsRequest{}); err != nil || len(list.Views) != 1 {
+		t.Errorf("Expected bob to list the shared view, got %v (%v)", list, err)
+	}
+	if _, err := s.DeleteView(bob, &proto.DeleteViewRequest{Id: id}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied for deleting another user's view, got %v", err)
+	}
+
+	// A view saved in a format this server does not know is reported, not
+	// misread.
+	views.views[0].FilterVersion = filter.Version + 1
+	if _, err := s.ListTasksByView(alice, &proto.ListTasksByViewRequest{Id: id}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition for an unknown filter version, got %v", err)
+	}
+
+	if _, err := s.DeleteView(alice, &proto.DeleteViewRequest{Id: id}); err != nil {
+		t.Fatalf("DeleteView failed: %v", err)
+	}
+	if _, err := s.GetView(alice, &proto.GetViewRequest{Id: id}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound after deletion, got %v", err)
+	}
+
+	if _, err := NewToDoServer(repo).ListViews(alice, &proto.ListViewsRequest{}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition without saved views, got %v", err)
+	}
+}
+
+func TestGetTaskStats(t *testing.T) {
+	repo := newMockRepository()
+	s := NewToDoServer(repo)
+	ctx := context.Background()
+	day := int64(24 * 60 * 60)
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC).Unix()
+	repo.CreateTask(ctx, &domain.Task{Title: "Old", Status: "TODO", CreatedAt: start - day})
+	repo.CreateTask(ctx, &domain.Task{Title: "Paused", Status: "PAUSED", CreatedAt: start + 10})
+	repo.CreateTask(ctx, &domain.Task{Title: "Quick", Status: "DONE", CreatedAt: start + 20, CompletedAt: start + 20 + 100})
+	repo.CreateTask(ctx, &domain.Task{Title: "Slow", Status: "DONE", CreatedAt: start + 30, CompletedAt: start + day + 400})
+	repo.CreateTask(ctx, &domain.Task{Title: "Late", Status: "DONE", CreatedAt: start - day, CompletedAt: start + 3*day})
+
+	res, err := s.GetTaskStats(ctx, &proto.GetTaskStatsRequest{StartTime: start, EndTime: start + 2*day, OldestOpenLimit: 1})
+	if err != nil {
+		t.Fatalf("GetTaskStats failed: %v", err)
+	}
+
+	counts := make(map[proto.Status]int64)
+	for _, c := range res.StatusCounts {
+		counts[c.Status] = c.Count
+	}
+	if counts[proto.Status_TODO] != 1 || counts[proto.Status_PAUSED] != 1 || counts[proto.Status_DONE] != 3 || counts[proto.Status_IN_PROGRESS] != 0 {
+		t.Errorf("Unexpected status counts %v", res.StatusCounts)
+	}
+	if len(res.Buckets) != 2 {
+		t.Fatalf("Expected 2 daily buckets, got %v", res.Buckets)
+	}
+	if b := res.Buckets[0]; b.StartTime != start || b.Created != 3 || b.Completed != 1 {
+		t.Errorf("Expected 3 created and 1 completed on the first day, got %v", b)
+	}
+	if b := res.Buckets[1]; b.StartTime != start+day || b.Created != 0 || b.Completed != 1 {
+		t.Errorf("Expected 1 completed on the second day, got %v", b)
+	}
+	// Quick took 100s and Slow a day and 370s; Late was completed after
+	// the window.
+	if want := (100 + day + 370) / 2; res.MedianTimeToDoneSeconds != want {
+		t.Errorf("Expected a median time to done of %d, got %d", want, res.MedianTimeToDoneSeconds)
+	}
+	if len(res.OldestOpen) != 1 || res.OldestOpen[0].Title != "Old" {
+		t.Errorf("Expected only the oldest open task, got %v", res.OldestOpen)
+	}
+
+	for _, req := range []*proto.GetTaskStatsRequest{
+		{StartTime: start, EndTime: start},
+		{StartTime: start, EndTime: start + day, BucketSeconds: 1},
+		{StartTime: start, EndTime: start + 2000*day},
+		{OldestOpenLimit: -1},
+	} {
+		if _, err := s.GetTaskStats(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument for %v, got %v", req, err)
+		}
+	}
+}
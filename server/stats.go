@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"grpc-todo/domain"
+	"grpc-todo/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultStatsWindow = 30 * 24 * 60 * 60
+	defaultStatsBucket = 24 * 60 * 60
+	minStatsBucket     = 60
+	maxStatsBuckets    = 1000
+	maxOldestOpen      = 100
+)
+
+// statsQuery fills in the defaults of a GetTaskStats request and checks
+// the window it asks for.
+func statsQuery(req *proto.GetTaskStatsRequest, now int64) (domain.StatsQuery, error) {
+	q := domain.StatsQuery{
+		End:        req.EndTime,
+		Start:      req.StartTime,
+		Bucket:     req.BucketSeconds,
+		OldestOpen: int(min(req.OldestOpenLimit, maxOldestOpen)),
+	}
+	if q.End == 0 {
+		q.End = now
+	}
+	if q.Start == 0 {
+		q.Start = q.End - defaultStatsWindow
+	}
+	if q.Bucket == 0 {
+		q.Bucket = defaultStatsBucket
+	}
+
+	switch {
+	case q.Start < 0:
+		return q, status.Error(codes.InvalidArgument, "GetTaskStats failed: start_time must not be negative")
+	case q.Start >= q.End:
+		return q, status.Error(codes.InvalidArgument, "GetTaskStats failed: start_time must be before end_time")
+	case q.Bucket < minStatsBucket:
+		return q, status.Errorf(codes.InvalidArgument, "GetTaskStats failed: bucket_seconds must be at least %d", minStatsBucket)
+	case (q.End-q.Start-1)/q.Bucket+1 > maxStatsBuckets:
+		return q, status.Errorf(codes.InvalidArgument, "GetTaskStats failed: the window holds more than %d buckets of %d seconds", maxStatsBuckets, q.Bucket)
+	case req.OldestOpenLimit < 0:
+		return q, status.Error(codes.InvalidArgument, "GetTaskStats failed: oldest_open_limit must not be negative")
+	}
+	return q, nil
+}
+
+func (s *ToDoServer) GetTaskStats(ctx context.Context, req *proto.GetTaskStatsRequest) (*proto.GetTaskStatsResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	q, err := statsQuery(req, time.Now().Unix())
+	if err != nil {
+		return nil, err
+	}
+	stats, err := s.repo.GetTaskStats(ctx, q)
+	if err != nil {
+		return nil, toStatusError("GetTaskStats", err)
+	}
+
+	res := &proto.GetTaskStatsResponse{MedianTimeToDoneSeconds: stats.MedianTimeToDone}
+	for _, st := range []proto.Status{proto.Status_TODO, proto.Status_IN_PROGRESS, proto.Status_PAUSED, proto.Status_DONE} {
+		res.StatusCounts = append(res.StatusCounts, &proto.StatusCount{
+			Status: st,
+			Count:  stats.StatusCounts[protoStatusToString(st)],
+		})
+	}
+	for _, b := range stats.Buckets {
+		res.Buckets = append(res.Buckets, &proto.StatsBucket{StartTime: b.Start, Created: b.Created, Completed: b.Completed})
+	}
+	for _, t := range stats.OldestOpen {
+		res.OldestOpen = append(res.OldestOpen, toProtoTask(t))
+	}
+	return res, nil
+}
@@ -0,0 +1,178 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"grpc-todo/auth"
+	"grpc-todo/domain"
+	"grpc-todo/proto"
+	"grpc-todo/validation"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// importChunkSize is how many tasks ImportTasks buffers before writing
+	// them. The stream is not read while a chunk is written, so gRPC flow
+	// control holds the client back instead of the server buffering.
+	importChunkSize = 500
+	maxImportErrors = 100
+)
+
+func (s *ToDoServer) ImportTasks(stream grpc.ClientStreamingServer[proto.ImportTasksRequest, proto.ImportTasksResponse]) error {
+	ctx := stream.Context()
+	owner := auth.FromContext(ctx)
+	now := time.Now().Unix()
+
+	summary := &proto.ImportTasksResponse{}
+	fail := func(index int64, id string, err error) {
+		summary.Failed++
+		if len(summary.Errors) < maxImportErrors {
+			summary.Errors = append(summary.Errors, &proto.ImportError{
+				Index:  index,
+				Id:     id,
+				Status: itemStatus("ImportTasks", err),
+			})
+		}
+	}
+
+	var chunk []*domain.Task
+	var indexes []int64
+	flush := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+		defer func() { chunk, indexes = chunk[:0], indexes[:0] }()
+
+		errs, err := s.createImported(ctx, "ImportTasks", owner, chunk)
+		if err != nil {
+			return err
+		}
+		for i, err := range errs {
+			if err != nil {
+				fail(indexes[i], chunk[i].Id, err)
+				continue
+			}
+			summary.Imported++
+		}
+		return nil
+	}
+
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		index := summary.Received
+		summary.Received++
+
+		task, err := importedTask(req, owner, now)
+		if err != nil {
+			fail(index, req.GetTask().GetId(), err)
+			continue
+		}
+
+		chunk = append(chunk, task)
+		indexes = append(indexes, index)
+		if len(chunk) == importChunkSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return err
+	}
+	return stream.SendAndClose(summary)
+}
+
+// createImported stores a chunk of tasks imported by method and returns one
+// error per task. Tasks beyond the owner's quota fail with ResourceExhausted.
+func (s *ToDoServer) createImported(ctx context.Context, method, owner string, tasks []*domain.Task) ([]error, error) {
+	allowed, release, err := s.reserveTasks(ctx, owner, len(tasks))
+	if err != nil && status.Code(err) != codes.ResourceExhausted {
+		return nil, err
+	}
+	defer release()
+
+	errs, err := s.repo.BatchCreateTasks(ctx, tasks[:allowed], false)
+	if err != nil {
+		return nil, toStatusError(method, err)
+	}
+	var changes []taskChange
+	for i, err := range errs {
+		if err == nil {
+			changes = append(changes, taskChange{after: tasks[i]})
+		}
+	}
+	s.auditBatch(ctx, method, changes)
+	for range tasks[allowed:] {
+		errs = append(errs, status.Error(codes.ResourceExhausted, "task quota exceeded"))
+	}
+	return errs, nil
+}
+
+// importedTask applies the same rules to an imported task as CreateTask does
+// to a new one.
+func importedTask(req *proto.ImportTasksRequest, owner string, now int64) (*domain.Task, error) {
+	t := req.GetTask()
+	err := validation.Validate(&proto.CreateTaskRequest{Title: t.GetTitle(), Description: t.GetDescription()})
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	task := &domain.Task{
+		Title:       t.GetTitle(),
+		Description: t.GetDescription(),
+		Status:      protoStatusToString(t.GetStatus()),
+		CreatedAt:   now,
+		Owner:       owner,
+		DueAt:       t.GetDueAt(),
+	}
+	if req.PreserveIds {
+		task.Id = t.GetId()
+		if t.GetCreatedAt() != 0 {
+			task.CreatedAt = t.GetCreatedAt()
+		}
+		task.CompletedAt = t.GetCompletedAt()
+	}
+	return task, nil
+}
+
+// exportedTask returns a copy of t as exports write it. Unless preserveIDs is
+// set, it drops the fields the importing server assigns itself, so that the
+// copy imports as a new task.
+func exportedTask(t *domain.Task, preserveIDs bool) *domain.Task {
+	task := *t
+	if !preserveIDs {
+		task.Id = ""
+		task.CreatedAt = 0
+		task.CompletedAt = 0
+	}
+	return &task
+}
+
+func (s *ToDoServer) ExportTasks(req *proto.ExportTasksRequest, stream grpc.ServerStreamingServer[proto.ExportTasksResponse]) error {
+	ctx := stream.Context()
+
+	err := s.repo.ForEachTask(ctx, func(t *domain.Task) error {
+		return stream.Send(&proto.ExportTasksResponse{Task: toProtoTask(exportedTask(t, req.PreserveIds))})
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return toStatusError("ExportTasks", err)
+	}
+	return nil
+}
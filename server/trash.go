@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+
+	"grpc-todo/proto"
+)
+
+func (s *ToDoServer) ListTrash(ctx context.Context, req *proto.ListTrashRequest) (*proto.ListTrashResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	size, err := pageSize("ListTrash", req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, next, err := s.repo.ListTrash(ctx, size, req.PageToken)
+	if err != nil {
+		return nil, toStatusError("ListTrash", err)
+	}
+
+	res := &proto.ListTrashResponse{NextPageToken: next}
+	for _, t := range tasks {
+		res.Tasks = append(res.Tasks, toProtoTask(t))
+	}
+	return res, nil
+}
+
+// UndeleteTask counts the task against its owner's quota again, since tasks
+// in the trash do not use any.
+func (s *ToDoServer) UndeleteTask(ctx context.Context, req *proto.UndeleteTaskRequest) (*proto.UndeleteTaskResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	deleted, err := s.repo.GetDeletedTask(ctx, req.Id)
+	if err != nil {
+		return nil, toStatusError("UndeleteTask", err)
+	}
+	release, err := s.checkTaskQuota(ctx, deleted.Owner)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	task, err := s.repo.UndeleteTask(ctx, req.Id)
+	if err != nil {
+		return nil, toStatusError("UndeleteTask", err)
+	}
+	s.audit(ctx, "UndeleteTask", deleted, task)
+
+	return &proto.UndeleteTaskResponse{Task: toProtoTask(task)}, nil
+}
+
+func (s *ToDoServer) PurgeTask(ctx context.Context, req *proto.PurgeTaskRequest) (*proto.PurgeTaskResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	task, err := s.repo.PurgeTask(ctx, req.Id)
+	if err != nil {
+		return nil, toStatusError("PurgeTask", err)
+	}
+	s.audit(ctx, "PurgeTask", task, nil)
+
+	return &proto.PurgeTaskResponse{}, nil
+}
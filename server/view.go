@@ -0,0 +1,240 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"grpc-todo/auth"
+	"grpc-todo/domain"
+	"grpc-todo/filter"
+	"grpc-todo/proto"
+	"grpc-todo/repository"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WithViews enables the saved view RPCs.
+func WithViews(views repository.ViewRepository) Option {
+	return func(s *ToDoServer) {
+		s.views = views
+	}
+}
+
+func (s *ToDoServer) checkViews() error {
+	if s.views == nil {
+		return status.Error(codes.FailedPrecondition, "saved views are not enabled on this server")
+	}
+	return nil
+}
+
+func toProtoView(v *domain.SavedView) *proto.SavedView {
+	return &proto.SavedView{
+		Id:            v.Id,
+		Name:          v.Name,
+		Filter:        v.Filter,
+		OrderBy:       v.OrderBy,
+		Owner:         v.Owner,
+		Shared:        v.Shared,
+		FilterVersion: int32(v.FilterVersion),
+		CreatedAt:     v.CreatedAt,
+		UpdatedAt:     v.UpdatedAt,
+	}
+}
+
+// checkView fails with InvalidArgument unless the filter and order of a
+// view about to be saved parse in the current filter format.
+func checkView(method, rawFilter, orderBy string) error {
+	if _, err := parseFilter(method, "filter", rawFilter); err != nil {
+		return err
+	}
+	_, err := parseOrderBy(method, "order_by", orderBy)
+	return err
+}
+
+// visibleView returns a view the caller owns or that is shared. Other
+// views are reported as not found.
+func (s *ToDoServer) visibleView(ctx context.Context, method, id string) (*domain.SavedView, error) {
+	view, err := s.views.GetView(ctx, id)
+	if err != nil {
+		return nil, toStatusError(method, err)
+	}
+	if view.Owner != auth.FromContext(ctx) && !view.Shared {
+		return nil, toStatusError(method, fmt.Errorf("saved view with ID %s: %w", id, repository.ErrNotFound))
+	}
+	return view, nil
+}
+
+// changeableView returns a view the caller may change: one they own.
+func (s *ToDoServer) changeableView(ctx context.Context, method, id string) error {
+	view, err := s.visibleView(ctx, method, id)
+	if err != nil {
+		return err
+	}
+	if view.Owner != auth.FromContext(ctx) {
+		return status.Errorf(codes.PermissionDenied, "%s failed: saved view %s is shared by %s, only they can change it", method, id, view.Owner)
+	}
+	return nil
+}
+
+func (s *ToDoServer) CreateView(ctx context.Context, req *proto.CreateViewRequest) (*proto.CreateViewResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	if err := s.checkViews(); err != nil {
+		return nil, err
+	}
+	if err := checkView("CreateView", req.Filter, req.OrderBy); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	view, err := s.views.CreateView(ctx, &domain.SavedView{
+		Owner:         auth.FromContext(ctx),
+		Name:          req.Name,
+		Filter:        req.Filter,
+		OrderBy:       req.OrderBy,
+		FilterVersion: filter.Version,
+		Shared:        req.Shared,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	})
+	if err != nil {
+		return nil, toStatusError("CreateView", err)
+	}
+	return &proto.CreateViewResponse{View: toProtoView(view)}, nil
+}
+
+func (s *ToDoServer) GetView(ctx context.Context, req *proto.GetViewRequest) (*proto.GetViewResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	if err := s.checkViews(); err != nil {
+		return nil, err
+	}
+	view, err := s.visibleView(ctx, "GetView", req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.GetViewResponse{View: toProtoView(view)}, nil
+}
+
+func (s *ToDoServer) ListViews(ctx context.Context, _ *proto.ListViewsRequest) (*proto.ListViewsResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	if err := s.checkViews(); err != nil {
+		return nil, err
+	}
+	views, err := s.views.ListViews(ctx, auth.FromContext(ctx))
+	if err != nil {
+		return nil, toStatusError("ListViews", err)
+	}
+
+	res := &proto.ListViewsResponse{}
+	for _, v := range views {
+		res.Views = append(res.Views, toProtoView(v))
+	}
+	return res, nil
+}
+
+func (s *ToDoServer) UpdateView(ctx context.Context, req *proto.UpdateViewRequest) (*proto.UpdateViewResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	if err := s.checkViews(); err != nil {
+		return nil, err
+	}
+	if err := checkView("UpdateView", req.Filter, req.OrderBy); err != nil {
+		return nil, err
+	}
+	if err := s.changeableView(ctx, "UpdateView", req.Id); err != nil {
+		return nil, err
+	}
+
+	// The view is saved again in the current filter format.
+	view, err := s.views.UpdateView(ctx, &domain.SavedView{
+		Id:            req.Id,
+		Owner:         auth.FromContext(ctx),
+		Name:          req.Name,
+		Filter:        req.Filter,
+		OrderBy:       req.OrderBy,
+		FilterVersion: filter.Version,
+		Shared:        req.Shared,
+		UpdatedAt:     time.Now().Unix(),
+	})
+	if err != nil {
+		return nil, toStatusError("UpdateView", err)
+	}
+	return &proto.UpdateViewResponse{View: toProtoView(view)}, nil
+}
+
+func (s *ToDoServer) DeleteView(ctx context.Context, req *proto.DeleteViewRequest) (*proto.DeleteViewResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	if err := s.checkViews(); err != nil {
+		return nil, err
+	}
+	if err := s.changeableView(ctx, "DeleteView", req.Id); err != nil {
+		return nil, err
+	}
+	if err := s.views.DeleteView(ctx, auth.FromContext(ctx), req.Id); err != nil {
+		return nil, toStatusError("DeleteView", err)
+	}
+	return &proto.DeleteViewResponse{}, nil
+}
+
+func (s *ToDoServer) ListTasksByView(ctx context.Context, req *proto.ListTasksByViewRequest) (*proto.ListTasksByViewResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	if err := s.checkViews(); err != nil {
+		return nil, err
+	}
+	view, err := s.visibleView(ctx, "ListTasksByView", req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	// A view was valid when it was saved, so one that no longer parses
+	// needs its owner to save it again rather than a different request.
+	expr, err := filter.ParseVersion(view.Filter, view.FilterVersion, filter.Tasks)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "ListTasksByView failed: saved view %s has an invalid filter: %v", view.Id, err)
+	}
+	order, err := filter.ParseOrderByVersion(view.OrderBy, view.FilterVersion, filter.Tasks)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "ListTasksByView failed: saved view %s has an invalid order: %v", view.Id, err)
+	}
+
+	tasks, err := s.repo.FilterTasks(ctx, expr, order)
+	if err != nil {
+		return nil, toStatusError("ListTasksByView", err)
+	}
+
+	res := &proto.ListTasksByViewResponse{}
+	for _, t := range tasks {
+		res.Tasks = append(res.Tasks, toProtoTask(t))
+	}
+	return res, nil
+}
@@ -0,0 +1,173 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"grpc-todo/auth"
+	"grpc-todo/domain"
+	"grpc-todo/outbox"
+	"grpc-todo/proto"
+	"grpc-todo/repository"
+	"grpc-todo/webhook"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var webhookEvents = map[proto.WebhookEvent]string{
+	proto.WebhookEvent_WEBHOOK_EVENT_TASK_CREATED:        domain.EventTaskCreated,
+	proto.WebhookEvent_WEBHOOK_EVENT_TASK_STATUS_UPDATED: domain.EventTaskStatusUpdated,
+	proto.WebhookEvent_WEBHOOK_EVENT_TASK_DELETED:        domain.EventTaskDeleted,
+}
+
+func toProtoWebhook(w *domain.Webhook) *proto.Webhook {
+	pw := &proto.Webhook{Id: w.Id, Url: w.URL, CreatedAt: w.CreatedAt}
+	for _, name := range w.Events {
+		for event, n := range webhookEvents {
+			if n == name {
+				pw.Events = append(pw.Events, event)
+			}
+		}
+	}
+	return pw
+}
+
+func (s *ToDoServer) checkWebhooks() error {
+	if s.webhooks == nil {
+		return status.Error(codes.FailedPrecondition, "webhooks are not enabled on this server")
+	}
+	return nil
+}
+
+func (s *ToDoServer) CreateWebhook(ctx context.Context, req *proto.CreateWebhookRequest) (*proto.CreateWebhookResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	if err := s.checkWebhooks(); err != nil {
+		return nil, err
+	}
+	if s.dispatcher != nil {
+		if err := s.dispatcher.CheckURL(ctx, req.Url); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "CreateWebhook failed: %v", err)
+		}
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		var err error
+		secret, err = webhook.NewSecret()
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "CreateWebhook failed: %v", err)
+		}
+	}
+
+	w := &domain.Webhook{
+		Owner:     auth.FromContext(ctx),
+		URL:       req.Url,
+		Secret:    secret,
+		CreatedAt: time.Now().Unix(),
+	}
+	seen := make(map[proto.WebhookEvent]bool)
+	for _, event := range req.Events {
+		if !seen[event] {
+			seen[event] = true
+			w.Events = append(w.Events, webhookEvents[event])
+		}
+	}
+
+	created, err := s.webhooks.CreateWebhook(ctx, w)
+	if err != nil {
+		return nil, toStatusError("CreateWebhook", err)
+	}
+
+	return &proto.CreateWebhookResponse{Webhook: toProtoWebhook(created), Secret: secret}, nil
+}
+
+func (s *ToDoServer) ListWebhooks(ctx context.Context, _ *proto.ListWebhooksRequest) (*proto.ListWebhooksResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	if err := s.checkWebhooks(); err != nil {
+		return nil, err
+	}
+
+	webhooks, err := s.webhooks.ListWebhooks(ctx, auth.FromContext(ctx))
+	if err != nil {
+		return nil, toStatusError("ListWebhooks", err)
+	}
+
+	res := &proto.ListWebhooksResponse{}
+	for _, w := range webhooks {
+		res.Webhooks = append(res.Webhooks, toProtoWebhook(w))
+	}
+	return res, nil
+}
+
+func (s *ToDoServer) DeleteWebhook(ctx context.Context, req *proto.DeleteWebhookRequest) (*proto.DeleteWebhookResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	if err := s.checkWebhooks(); err != nil {
+		return nil, err
+	}
+
+	if err := s.webhooks.DeleteWebhook(ctx, auth.FromContext(ctx), req.Id); err != nil {
+		return nil, toStatusError("DeleteWebhook", err)
+	}
+	return &proto.DeleteWebhookResponse{}, nil
+}
+
+// WebhookPublisher returns an outbox publisher that queues the webhook
+// deliveries of each task event, so that they are made from the outbox
+// message written with the change. The data of a delivery is the task as
+// the API returns it. Events webhooks cannot subscribe to are skipped, and
+// an event that fails to queue is left for the relay to retry.
+func WebhookPublisher(dispatcher *webhook.Dispatcher) outbox.Publisher {
+	return webhookPublisher{dispatcher}
+}
+
+type webhookPublisher struct {
+	dispatcher *webhook.Dispatcher
+}
+
+func (p webhookPublisher) Publish(ctx context.Context, msg *domain.OutboxMessage) error {
+	if !isWebhookEvent(msg.Event) {
+		return nil
+	}
+
+	task, err := repository.DecodeOutboxTask(msg)
+	if err != nil {
+		return err
+	}
+	data, err := protojson.Marshal(toProtoTask(task))
+	if err != nil {
+		return err
+	}
+	return p.dispatcher.Emit(ctx, webhook.Event{
+		ID:         msg.Id,
+		Type:       msg.Event,
+		Owner:      msg.Owner,
+		Data:       data,
+		OccurredAt: msg.CreatedAt,
+	})
+}
+
+func isWebhookEvent(event string) bool {
+	for _, name := range webhookEvents {
+		if name == event {
+			return true
+		}
+	}
+	return false
+}
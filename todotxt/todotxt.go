@@ -0,0 +1,305 @@
+// Package todotxt converts between tasks and the todo.txt line format
+// (https://github.com/todotxt/todo.txt):
+//
+//	x (A) 2024-05-02 2024-05-01 Call Mom +Family @phone due:2024-05-03
+//
+// Due dates use the common due:YYYY-MM-DD tag, or an RFC 3339 timestamp when
+// the due time is not midnight UTC. Fields that todo.txt has no place for
+// are carried in key:value tags too, so that every field of a task survives
+// a round trip:
+//
+//	id:<task ID>  status:in_progress|paused  desc:<escaped description>
+//	created:<Unix seconds>, when the creation date alone would lose the time
+//	completed:<Unix seconds>, likewise for the completion date
+//	title:<escaped title>, when the title would otherwise be misread, e.g.
+//	  because it starts with "x", a priority or a date, or has words that
+//	  look like these tags
+//
+// Priorities are parsed but tasks do not store them.
+package todotxt
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"grpc-todo/domain"
+	"grpc-todo/proto"
+)
+
+const dateLayout = time.DateOnly
+
+var (
+	priorityPrefix = regexp.MustCompile(`^\(([A-Z])\)$`)
+	taskID         = regexp.MustCompile(`^[0-9a-f]{24}$`)
+)
+
+// Item is one parsed todo.txt line.
+type Item struct {
+	Completed bool
+	// Priority is 'A' to 'Z', or zero when the item has none.
+	Priority       byte
+	CompletionDate time.Time
+	CreationDate   time.Time
+	// Text is everything after the dates, including projects, contexts and
+	// tags.
+	Text string
+}
+
+// Tag is a key:value pair in the text of an item.
+type Tag struct {
+	Key   string
+	Value string
+}
+
+// Parse reads a single todo.txt line.
+func Parse(line string) (Item, error) {
+	var it Item
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return it, fmt.Errorf("empty line")
+	}
+
+	if fields[0] == "x" {
+		it.Completed = true
+		fields = fields[1:]
+	}
+	if len(fields) > 0 {
+		if m := priorityPrefix.FindStringSubmatch(fields[0]); m != nil {
+			it.Priority = m[1][0]
+			fields = fields[1:]
+		}
+	}
+
+	// Only completed items have two dates: completion, then creation. A
+	// single date on a completed item is its completion date.
+	maxDates := 1
+	if it.Completed {
+		maxDates = 2
+	}
+	var dates []time.Time
+	for len(fields) > 0 && len(dates) < maxDates {
+		d, err := time.Parse(dateLayout, fields[0])
+		if err != nil {
+			break
+		}
+		dates = append(dates, d)
+		fields = fields[1:]
+	}
+	switch {
+	case len(dates) == 2:
+		it.CompletionDate, it.CreationDate = dates[0], dates[1]
+	case len(dates) == 1 && it.Completed:
+		it.CompletionDate = dates[0]
+	case len(dates) == 1:
+		it.CreationDate = dates[0]
+	}
+
+	it.Text = strings.Join(fields, " ")
+	if it.Text == "" {
+		return it, fmt.Errorf("missing task text")
+	}
+	return it, nil
+}
+
+// String formats the item as a todo.txt line.
+func (it Item) String() string {
+	var parts []string
+	if it.Completed {
+		parts = append(parts, "x")
+	}
+	if it.Priority != 0 {
+		parts = append(parts, "("+string(it.Priority)+")")
+	}
+	if !it.CompletionDate.IsZero() && it.Completed {
+		parts = append(parts, it.CompletionDate.Format(dateLayout))
+	}
+	// The creation date of a completed item needs a completion date in
+	// front of it, or readers would take it for one.
+	if !it.CreationDate.IsZero() && (!it.Completed || !it.CompletionDate.IsZero()) {
+		parts = append(parts, it.CreationDate.Format(dateLayout))
+	}
+	parts = append(parts, it.Text)
+	return strings.Join(parts, " ")
+}
+
+// Projects returns the +project tokens of the text.
+func (it Item) Projects() []string {
+	return prefixed(it.Text, '+')
+}
+
+// Contexts returns the @context tokens of the text.
+func (it Item) Contexts() []string {
+	return prefixed(it.Text, '@')
+}
+
+// Tags returns the key:value tokens of the text. URLs are not tags.
+func (it Item) Tags() []Tag {
+	var tags []Tag
+	for _, field := range strings.Fields(it.Text) {
+		if tag, ok := parseTag(field); ok {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func prefixed(text string, prefix byte) []string {
+	var names []string
+	for _, field := range strings.Fields(text) {
+		if len(field) > 1 && field[0] == prefix {
+			names = append(names, field[1:])
+		}
+	}
+	return names
+}
+
+func parseTag(field string) (Tag, bool) {
+	key, value, ok := strings.Cut(field, ":")
+	if !ok || key == "" || value == "" || strings.ContainsAny(key, "/+@") || strings.HasPrefix(value, "//") {
+		return Tag{}, false
+	}
+	return Tag{Key: key, Value: value}, true
+}
+
+// FromTask converts a task to a todo.txt item.
+func FromTask(t *domain.Task) Item {
+	it := Item{Completed: t.Status == proto.Status_DONE.String()}
+	title := strings.Join(strings.Fields(t.Title), " ")
+	text := []string{title}
+
+	switch t.Status {
+	case proto.Status_IN_PROGRESS.String(), proto.Status_PAUSED.String():
+		text = append(text, "status:"+strings.ToLower(t.Status))
+	}
+	if t.Id != "" {
+		text = append(text, "id:"+t.Id)
+	}
+	if t.Description != "" {
+		text = append(text, "desc:"+url.PathEscape(t.Description))
+	}
+	if t.DueAt != 0 {
+		due := time.Unix(t.DueAt, 0).UTC()
+		if due.Equal(due.Truncate(24 * time.Hour)) {
+			text = append(text, "due:"+due.Format(dateLayout))
+		} else {
+			text = append(text, "due:"+due.Format(time.RFC3339))
+		}
+	}
+
+	if it.Completed && t.CompletedAt != 0 {
+		completed := time.Unix(t.CompletedAt, 0).UTC()
+		it.CompletionDate = completed.Truncate(24 * time.Hour)
+		if !completed.Equal(it.CompletionDate) {
+			text = append(text, "completed:"+strconv.FormatInt(t.CompletedAt, 10))
+		}
+	}
+	if t.CreatedAt != 0 {
+		created := time.Unix(t.CreatedAt, 0).UTC()
+		day := created.Truncate(24 * time.Hour)
+		if !it.Completed {
+			it.CreationDate = day
+		}
+		if it.Completed || !created.Equal(day) {
+			text = append(text, "created:"+strconv.FormatInt(t.CreatedAt, 10))
+		}
+	}
+
+	it.Text = strings.Join(text, " ")
+	if title != "" && !readsBack(it, t.Title) {
+		text[0] = "title:" + url.PathEscape(t.Title)
+		it.Text = strings.Join(text, " ")
+	}
+	return it
+}
+
+// readsBack reports whether it parses back to a task with the given title,
+// which fails when leading words of the title are taken for the completion
+// mark, a priority or a date, other words for tags, or when the title has
+// whitespace that parsing would collapse.
+func readsBack(it Item, title string) bool {
+	parsed, err := Parse(it.String())
+	return err == nil && parsed.Task().Title == title
+}
+
+// Task converts the item to a task. The tags written by FromTask are taken
+// out of the text; anything else, including other tags, stays in the title.
+func (it Item) Task() *domain.Task {
+	t := &domain.Task{Status: proto.Status_TODO.String()}
+	if !it.CreationDate.IsZero() {
+		t.CreatedAt = it.CreationDate.Unix()
+	}
+	if it.Completed && !it.CompletionDate.IsZero() {
+		t.CompletedAt = it.CompletionDate.Unix()
+	}
+
+	var title []string
+	for _, field := range strings.Fields(it.Text) {
+		tag, ok := parseTag(field)
+		if ok && applyTag(t, tag) {
+			continue
+		}
+		title = append(title, field)
+	}
+	// A title tag holds the title; words next to it were added later.
+	if t.Title != "" {
+		title = append([]string{t.Title}, title...)
+	}
+	t.Title = strings.Join(title, " ")
+
+	if it.Completed {
+		t.Status = proto.Status_DONE.String()
+	}
+	return t
+}
+
+// applyTag stores a tag written by FromTask in t. Tags with values FromTask
+// would not write are left in the title.
+func applyTag(t *domain.Task, tag Tag) bool {
+	switch tag.Key {
+	case "id":
+		if taskID.MatchString(tag.Value) {
+			t.Id = tag.Value
+			return true
+		}
+	case "status":
+		status := proto.Status(proto.Status_value[strings.ToUpper(tag.Value)])
+		if status != proto.Status_UNKNOWN {
+			t.Status = status.String()
+			return true
+		}
+	case "title":
+		if title, err := url.PathUnescape(tag.Value); err == nil && t.Title == "" {
+			t.Title = title
+			return true
+		}
+	case "desc":
+		if desc, err := url.PathUnescape(tag.Value); err == nil {
+			t.Description = desc
+			return true
+		}
+	case "created":
+		if n, err := strconv.ParseInt(tag.Value, 10, 64); err == nil && n > 0 {
+			t.CreatedAt = n
+			return true
+		}
+	case "completed":
+		if n, err := strconv.ParseInt(tag.Value, 10, 64); err == nil && n > 0 {
+			t.CompletedAt = n
+			return true
+		}
+	case "due":
+		if due, err := time.Parse(dateLayout, tag.Value); err == nil {
+			t.DueAt = due.Unix()
+			return true
+		}
+		if due, err := time.Parse(time.RFC3339, tag.Value); err == nil {
+			t.DueAt = due.Unix()
+			return true
+		}
+	}
+	return false
+}
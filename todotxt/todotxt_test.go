@@ -0,0 +1,121 @@
+package todotxt
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"grpc-todo/domain"
+)
+
+func TestParse(t *testing.T) {
+	it, err := Parse("x (A) 2024-05-02 2024-05-01 Call Mom +Family @phone due:2024-05-03 http://example.com")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if !it.Completed || it.Priority != 'A' {
+		t.Errorf("Expected a completed item with priority A, got %+v", it)
+	}
+	if it.CompletionDate.Format(time.DateOnly) != "2024-05-02" || it.CreationDate.Format(time.DateOnly) != "2024-05-01" {
+		t.Errorf("Expected completion and creation dates, got %v and %v", it.CompletionDate, it.CreationDate)
+	}
+	if got := it.Projects(); len(got) != 1 || got[0] != "Family" {
+		t.Errorf("Expected project Family, got %v", got)
+	}
+	if got := it.Contexts(); len(got) != 1 || got[0] != "phone" {
+		t.Errorf("Expected context phone, got %v", got)
+	}
+	if got := it.Tags(); len(got) != 1 || got[0] != (Tag{Key: "due", Value: "2024-05-03"}) {
+		t.Errorf("Expected only the due tag, got %v", got)
+	}
+	if got := it.String(); got != "x (A) 2024-05-02 2024-05-01 Call Mom +Family @phone due:2024-05-03 http://example.com" {
+		t.Errorf("Expected the line to be written back unchanged, got %q", got)
+	}
+}
+
+func TestParseDates(t *testing.T) {
+	it, err := Parse("2024-05-01 2024-05-02 is the deadline")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if it.CreationDate.Format(time.DateOnly) != "2024-05-01" || it.Text != "2024-05-02 is the deadline" {
+		t.Errorf("Expected one creation date on an open item, got %+v", it)
+	}
+
+	it, err = Parse("x 2024-05-02 Done")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if it.CompletionDate.IsZero() || !it.CreationDate.IsZero() {
+		t.Errorf("Expected a single date on a completed item to be the completion date, got %+v", it)
+	}
+
+	for _, line := range []string{"", "   ", "x (B) 2024-05-01"} {
+		if _, err := Parse(line); err == nil {
+			t.Errorf("Parse(%q): expected an error", line)
+		}
+	}
+}
+
+func TestTaskRoundTrip(t *testing.T) {
+	tasks := []*domain.Task{
+		{Id: "5f1d7e4b9c2a3b0012345678", Title: "Write docs +docs @desk", Description: "README: usage\n100% done + more", Status: "IN_PROGRESS", CreatedAt: 1714557645},
+		{Id: "5f1d7e4b9c2a3b0012345679", Title: "Release", Status: "DONE", CreatedAt: 1714521600, DueAt: 1714608000},
+		{Title: "Demo", Status: "TODO", DueAt: 1714640400},
+		{Title: "Pause me", Status: "PAUSED", CreatedAt: 1714521600},
+		{Title: "Plain", Status: "TODO"},
+		{Title: "x marks the spot", Status: "TODO"},
+		{Title: "x marks the spot", Status: "DONE", CompletedAt: 1714608000},
+		{Title: "(A) Call Mom", Status: "TODO"},
+		{Title: "2024-05-01 retro notes", Status: "TODO"},
+		{Title: "2024-05-01 retro notes", Status: "TODO", CreatedAt: 1714521600},
+		{Title: "Bump created:5 for id:5f1d7e4b9c2a3b0012345678", Status: "PAUSED"},
+		{Title: "Fix title:parsing", Status: "TODO"},
+		{Title: "Two  spaces\tand a tab", Status: "TODO"},
+		{Title: "Late night", Status: "DONE", CreatedAt: 1714521600, CompletedAt: 1714640400},
+	}
+
+	for _, task := range tasks {
+		line := FromTask(task).String()
+		it, err := Parse(line)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", line, err)
+		}
+		if got := it.Task(); !reflect.DeepEqual(got, task) {
+			t.Errorf("Round trip through %q: expected %+v, got %+v", line, task, got)
+		}
+	}
+
+	line := FromTask(tasks[3]).String()
+	if !strings.HasPrefix(line, "2024-05-01 Pause me status:paused") || strings.Contains(line, "created:") {
+		t.Errorf("Expected a creation date and no created tag for a midnight timestamp, got %q", line)
+	}
+	if line := FromTask(tasks[0]).String(); strings.Contains(line, "title:") {
+		t.Errorf("Expected a title that reads back as is to stay plain, got %q", line)
+	}
+	if line := FromTask(tasks[12]).String(); !strings.Contains(line, "title:Two%20%20spaces%09and%20a%20tab") {
+		t.Errorf("Expected whitespace that parsing collapses to be escaped, got %q", line)
+	}
+	if line := FromTask(tasks[13]).String(); !strings.HasPrefix(line, "x 2024-05-02 Late night") || !strings.Contains(line, "completed:1714640400") {
+		t.Errorf("Expected a completion date and the completion time, got %q", line)
+	}
+	if line := FromTask(tasks[6]).String(); strings.Contains(line, "completed:") {
+		t.Errorf("Expected no completed tag for a midnight timestamp, got %q", line)
+	}
+}
+
+func TestTaskKeepsForeignTags(t *testing.T) {
+	it, err := Parse("(C) Pay rent id:42 t:2024-05-25 due:2024-06-01")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	task := it.Task()
+	if task.Title != "Pay rent id:42 t:2024-05-25" || task.Id != "" || task.Status != "TODO" {
+		t.Errorf("Expected tags we do not own to stay in the title, got %+v", task)
+	}
+	if task.DueAt != time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC).Unix() {
+		t.Errorf("Expected the due tag to set the due date, got %d", task.DueAt)
+	}
+}
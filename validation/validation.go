@@ -0,0 +1,169 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	todopb "grpc-todo/proto"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Violation describes a single field that failed its declared rules.
+type Violation struct {
+	Field       string
+	Description string
+}
+
+type Error struct {
+	Violations []Violation
+}
+
+func (e *Error) Error() string {
+	parts := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		parts = append(parts, v.Field+": "+v.Description)
+	}
+	return "invalid request: " + strings.Join(parts, "; ")
+}
+
+var patterns sync.Map
+
+// Validate checks msg against the (todo.rules) options declared on its
+// fields in the proto definitions, descending into nested messages.
+func Validate(msg proto.Message) error {
+	var violations []Violation
+	validateMessage(msg.ProtoReflect(), "", &violations)
+	if len(violations) > 0 {
+		return &Error{Violations: violations}
+	}
+	return nil
+}
+
+func validateMessage(m protoreflect.Message, prefix string, violations *[]Violation) {
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		path := prefix + string(fd.Name())
+		rules := fieldRules(fd)
+
+		switch {
+		case fd.IsList():
+			list := m.Get(fd).List()
+			itemRules := rules
+			if r := rules.GetRepeated(); r != nil {
+				validateCount(r, list.Len(), path, violations)
+				itemRules = r.GetItems()
+			}
+			for j := 0; j < list.Len(); j++ {
+				validateValue(fd, itemRules, list.Get(j), fmt.Sprintf("%s[%d]", path, j), violations)
+			}
+		case fd.IsMap():
+			continue
+		case fd.Message() != nil:
+			if m.Has(fd) {
+				validateMessage(m.Get(fd).Message(), path+".", violations)
+			}
+		default:
+			validateValue(fd, rules, m.Get(fd), path, violations)
+		}
+	}
+}
+
+func fieldRules(fd protoreflect.FieldDescriptor) *todopb.FieldRules {
+	rules, _ := proto.GetExtension(fd.Options(), todopb.E_Rules).(*todopb.FieldRules)
+	return rules
+}
+
+func validateCount(r *todopb.RepeatedRules, n int, path string, violations *[]Violation) {
+	count := uint64(n)
+	if r.MinItems != nil && count < r.GetMinItems() {
+		if r.GetMinItems() == 1 {
+			*violations = append(*violations, Violation{Field: path, Description: "must not be empty"})
+		} else {
+			*violations = append(*violations, Violation{Field: path, Description: fmt.Sprintf("must contain at least %d items", r.GetMinItems())})
+		}
+	}
+	if r.MaxItems != nil && count > r.GetMaxItems() {
+		*violations = append(*violations, Violation{Field: path, Description: fmt.Sprintf("must contain at most %d items", r.GetMaxItems())})
+	}
+}
+
+func validateValue(fd protoreflect.FieldDescriptor, rules *todopb.FieldRules, v protoreflect.Value, path string, violations *[]Violation) {
+	if fd.Message() != nil {
+		validateMessage(v.Message(), path+".", violations)
+		return
+	}
+
+	if rules == nil {
+		return
+	}
+
+	add := func(format string, args ...any) {
+		*violations = append(*violations, Violation{Field: path, Description: fmt.Sprintf(format, args...)})
+	}
+
+	if r := rules.GetString_(); r != nil && fd.Kind() == protoreflect.StringKind {
+		s := v.String()
+		n := uint64(utf8.RuneCountInString(s))
+		if r.MinLen != nil && uint64(utf8.RuneCountInString(strings.TrimSpace(s))) < r.GetMinLen() {
+			if r.GetMinLen() == 1 {
+				add("must not be empty")
+			} else {
+				add("must be at least %d characters", r.GetMinLen())
+			}
+		}
+		if r.MaxLen != nil && n > r.GetMaxLen() {
+			add("must be at most %d characters", r.GetMaxLen())
+		}
+		if r.Pattern != nil && !compile(r.GetPattern()).MatchString(s) {
+			add("must match %q", r.GetPattern())
+		}
+	}
+
+	if r := rules.GetBytes(); r != nil && fd.Kind() == protoreflect.BytesKind {
+		n := uint64(len(v.Bytes()))
+		if r.MinLen != nil && n < r.GetMinLen() {
+			if r.GetMinLen() == 1 {
+				add("must not be empty")
+			} else {
+				add("must be at least %d bytes", r.GetMinLen())
+			}
+		}
+		if r.MaxLen != nil && n > r.GetMaxLen() {
+			add("must be at most %d bytes", r.GetMaxLen())
+		}
+	}
+
+	if r := rules.GetEnum(); r != nil && fd.Kind() == protoreflect.EnumKind {
+		n := v.Enum()
+		if r.DefinedOnly && fd.Enum().Values().ByNumber(n) == nil {
+			add("unknown value %d", n)
+		}
+		for _, excluded := range r.NotIn {
+			if int32(n) == excluded {
+				add("value %s is not allowed", enumName(fd, n))
+			}
+		}
+	}
+}
+
+func compile(pattern string) *regexp.Regexp {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp)
+	}
+	re := regexp.MustCompile(pattern)
+	patterns.Store(pattern, re)
+	return re
+}
+
+func enumName(fd protoreflect.FieldDescriptor, n protoreflect.EnumNumber) string {
+	if ev := fd.Enum().Values().ByNumber(n); ev != nil {
+		return string(ev.Name())
+	}
+	return fmt.Sprint(n)
+}
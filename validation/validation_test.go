@@ -0,0 +1,102 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"grpc-todo/proto"
+)
+
+func violationFields(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var verr *Error
+	if !errors.As(err, &verr) {
+		t.Fatalf("Expected *Error, got %T", err)
+	}
+	var fields []string
+	for _, v := range verr.Violations {
+		fields = append(fields, v.Field)
+	}
+	return fields
+}
+
+func TestValidate_CreateTaskRequest(t *testing.T) {
+	if err := Validate(&proto.CreateTaskRequest{Title: "Task", Description: "Desc"}); err != nil {
+		t.Errorf("Expected valid request, got %v", err)
+	}
+
+	fields := violationFields(t, Validate(&proto.CreateTaskRequest{}))
+	if len(fields) != 1 || fields[0] != "title" {
+		t.Errorf("Expected violation on title, got %v", fields)
+	}
+
+	fields = violationFields(t, Validate(&proto.CreateTaskRequest{Title: " \t\n "}))
+	if len(fields) != 1 || fields[0] != "title" {
+		t.Errorf("Expected violation on a blank title, got %v", fields)
+	}
+	if err := Validate(&proto.CreateTaskRequest{Title: " a "}); err != nil {
+		t.Errorf("Expected a padded title to be valid, got %v", err)
+	}
+
+	fields = violationFields(t, Validate(&proto.CreateTaskRequest{
+		Title:       strings.Repeat("a", 201),
+		Description: strings.Repeat("b", 4001),
+	}))
+	if len(fields) != 2 {
+		t.Errorf("Expected violations on title and description, got %v", fields)
+	}
+}
+
+func TestValidate_UpdateTaskStatusRequest(t *testing.T) {
+	valid := &proto.UpdateTaskStatusRequest{Id: "5f1d7e4b9c2a3b0012345678", Status: proto.Status_DONE}
+	if err := Validate(valid); err != nil {
+		t.Errorf("Expected valid request, got %v", err)
+	}
+
+	fields := violationFields(t, Validate(&proto.UpdateTaskStatusRequest{Id: "not-an-id", Status: proto.Status_UNKNOWN}))
+	if len(fields) != 2 || fields[0] != "id" || fields[1] != "status" {
+		t.Errorf("Expected violations on id and status, got %v", fields)
+	}
+
+	fields = violationFields(t, Validate(&proto.UpdateTaskStatusRequest{Id: valid.Id, Status: proto.Status(42)}))
+	if len(fields) != 1 || fields[0] != "status" {
+		t.Errorf("Expected violation on status, got %v", fields)
+	}
+}
+
+func TestValidate_BatchRequests(t *testing.T) {
+	fields := violationFields(t, Validate(&proto.BatchDeleteTasksRequest{}))
+	if len(fields) != 1 || fields[0] != "ids" {
+		t.Errorf("Expected violation on ids, got %v", fields)
+	}
+
+	fields = violationFields(t, Validate(&proto.BatchDeleteTasksRequest{Ids: []string{"5f1d7e4b9c2a3b0012345678", "bad"}}))
+	if len(fields) != 1 || fields[0] != "ids[1]" {
+		t.Errorf("Expected violation on ids[1], got %v", fields)
+	}
+
+	fields = violationFields(t, Validate(&proto.BatchCreateTasksRequest{Requests: make([]*proto.CreateTaskRequest, 1001)}))
+	if len(fields) == 0 || fields[0] != "requests" {
+		t.Errorf("Expected violation on requests, got %v", fields)
+	}
+
+	fields = violationFields(t, Validate(&proto.BatchCreateTasksRequest{Requests: []*proto.CreateTaskRequest{{Title: "ok"}, {}}}))
+	if len(fields) != 1 || fields[0] != "requests[1].title" {
+		t.Errorf("Expected violation on requests[1].title, got %v", fields)
+	}
+}
+
+func TestValidate_Bytes(t *testing.T) {
+	fields := violationFields(t, Validate(&proto.ImportCalendarRequest{}))
+	if len(fields) != 1 || fields[0] != "content" {
+		t.Errorf("Expected violation on content, got %v", fields)
+	}
+
+	if err := Validate(&proto.ImportCalendarRequest{Content: []byte("BEGIN:VCALENDAR")}); err != nil {
+		t.Errorf("Expected valid request, got %v", err)
+	}
+}
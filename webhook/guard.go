@@ -0,0 +1,102 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"syscall"
+)
+
+// ErrForbiddenAddress is returned for webhook URLs whose host is, or
+// resolves to, an address the server must not post to.
+var ErrForbiddenAddress = errors.New("webhook address is not allowed")
+
+// internalNetworks are ranges netip has no predicate for that still reach
+// the server's own network rather than the internet.
+var internalNetworks = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+}
+
+// WithAllowedNetworks lets webhooks reach addresses in networks that are
+// otherwise refused, such as a private network the receivers run in.
+func WithAllowedNetworks(networks ...netip.Prefix) Option {
+	return func(d *Dispatcher) {
+		d.allowed = append(d.allowed, networks...)
+	}
+}
+
+// allowedAddr reports whether webhooks may be posted to ip: any public
+// address, and others only in an allowed network. Loopback, link-local,
+// private, multicast and unspecified addresses are refused so that callers
+// cannot make the server post to itself or to hosts behind it, such as a
+// cloud metadata endpoint.
+func (d *Dispatcher) allowedAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	for _, p := range d.allowed {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, p := range internalNetworks {
+		if p.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckURL checks a webhook URL when it is registered: it must be http or
+// https, and every address its host resolves to must be allowed. A host
+// that does not resolve yet is accepted; deliveries check the address they
+// connect to anyway, which also catches hosts that resolve differently
+// later.
+func (d *Dispatcher) CheckURL(ctx context.Context, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+	host := u.Hostname()
+	if ip, err := netip.ParseAddr(host); err == nil {
+		if !d.allowedAddr(ip) {
+			return fmt.Errorf("%w: %s", ErrForbiddenAddress, ip)
+		}
+		return nil
+	}
+
+	ips, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil
+	}
+	for _, ip := range ips {
+		if !d.allowedAddr(ip) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrForbiddenAddress, host, ip)
+		}
+	}
+	return nil
+}
+
+// control refuses connections to addresses webhooks may not reach. It runs
+// for every address a delivery, or a redirect it follows, dials, after the
+// host name has been resolved.
+func (d *Dispatcher) control(_, address string, _ syscall.RawConn) error {
+	ap, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !d.allowedAddr(ap.Addr()) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, ap.Addr())
+	}
+	return nil
+}
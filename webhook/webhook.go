@@ -0,0 +1,380 @@
+// Package webhook posts task events to subscribed URLs. Events are written
+// to an outbox first and delivered from there, so a receiver that is down
+// gets them once it is back, up to a limit of attempts.
+//
+// Task events reach the Dispatcher from the task outbox relay, which hands
+// it every message written with a task change; see the outbox package.
+//
+// Every delivery is a JSON POST:
+//
+//	{"id": "<delivery id>", "type": "task.created", "occurredAt": "...", "data": {...}}
+//
+// with these headers:
+//
+//	X-Webhook-Id         delivery ID, the same on every retry; use it to drop duplicates
+//	X-Webhook-Event      event type
+//	X-Webhook-Signature  t=<unix time>,v1=<hex HMAC-SHA256 of "<t>.<body>">
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"grpc-todo/domain"
+	"grpc-todo/logging"
+	"grpc-todo/repository"
+)
+
+const (
+	HeaderID        = "X-Webhook-Id"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+var (
+	ErrNoSignature      = errors.New("missing signature")
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrSignatureExpired = errors.New("signature timestamp out of tolerance")
+)
+
+// Event is a change to one of Owner's tasks. Data is the JSON the receiver
+// gets in the "data" field.
+type Event struct {
+	// ID, when set, identifies the event, such as by its outbox message ID.
+	// Emitting an event again then queues nothing new for webhooks that
+	// still have it queued, and delivers it under the same delivery ID to
+	// the others.
+	ID    string
+	Type  string
+	Owner string
+	Data  json.RawMessage
+	// OccurredAt defaults to the time the event is emitted.
+	OccurredAt time.Time
+}
+
+type Dispatcher struct {
+	repo repository.WebhookRepository
+
+	client       *http.Client
+	allowed      []netip.Prefix
+	maxAttempts  int
+	baseBackoff  time.Duration
+	maxBackoff   time.Duration
+	pollInterval time.Duration
+	batchSize    int
+	now          func() time.Time
+
+	wake chan struct{}
+}
+
+type Option func(*Dispatcher)
+
+// WithHTTPClient sets the client deliveries are posted with. Its timeout
+// bounds a single attempt. The client replaces the default one, which
+// refuses addresses that are not allowed, so it must do its own checks.
+func WithHTTPClient(client *http.Client) Option {
+	return func(d *Dispatcher) {
+		d.client = client
+	}
+}
+
+// WithMaxAttempts sets how many failed attempts dead-letter a delivery.
+func WithMaxAttempts(n int) Option {
+	return func(d *Dispatcher) {
+		if n > 0 {
+			d.maxAttempts = n
+		}
+	}
+}
+
+// WithBackoff sets the delay after the first failure, which doubles after
+// every further failure up to max.
+func WithBackoff(base, max time.Duration) Option {
+	return func(d *Dispatcher) {
+		d.baseBackoff = base
+		d.maxBackoff = max
+	}
+}
+
+// WithPollInterval sets how often Run looks for deliveries that are due.
+func WithPollInterval(interval time.Duration) Option {
+	return func(d *Dispatcher) {
+		d.pollInterval = interval
+	}
+}
+
+func NewDispatcher(repo repository.WebhookRepository, opts ...Option) *Dispatcher {
+	d := &Dispatcher{
+		repo:         repo,
+		maxAttempts:  8,
+		baseBackoff:  10 * time.Second,
+		maxBackoff:   time.Hour,
+		pollInterval: time.Second,
+		batchSize:    50,
+		now:          time.Now,
+		wake:         make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if d.client == nil {
+		// Proxies are not used, since they would connect on the
+		// dispatcher's behalf without the address check.
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = nil
+		transport.DialContext = (&net.Dialer{Timeout: 10 * time.Second, Control: d.control}).DialContext
+		d.client = &http.Client{Timeout: 10 * time.Second, Transport: transport}
+	}
+	return d
+}
+
+// Emit queues e for every webhook of its owner subscribed to its type.
+func (d *Dispatcher) Emit(ctx context.Context, e Event) error {
+	webhooks, err := d.repo.MatchWebhooks(ctx, e.Owner, e.Type)
+	if err != nil || len(webhooks) == 0 {
+		return err
+	}
+
+	now := d.now()
+	occurredAt := e.OccurredAt
+	if occurredAt.IsZero() {
+		occurredAt = now
+	}
+	deliveries := make([]*domain.WebhookDelivery, len(webhooks))
+	for i, w := range webhooks {
+		deliveries[i] = &domain.WebhookDelivery{
+			WebhookId:     w.Id,
+			Event:         e.Type,
+			Data:          e.Data,
+			OccurredAt:    occurredAt.Unix(),
+			NextAttemptAt: now,
+		}
+		if e.ID != "" {
+			deliveries[i].Id = deliveryID(e.ID, w.Id)
+		}
+	}
+	if err := d.repo.EnqueueDeliveries(ctx, deliveries); err != nil {
+		return err
+	}
+
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// deliveryID derives the ID of the delivery of an event to a webhook, in the
+// form of a Mongo object ID.
+func deliveryID(eventID, webhookID string) string {
+	sum := sha256.Sum256([]byte(eventID + "/" + webhookID))
+	return hex.EncodeToString(sum[:12])
+}
+
+// Run delivers queued events until ctx is canceled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			n, err := d.DeliverDue(ctx)
+			if err != nil {
+				logging.FromContext(ctx).Error("webhook delivery failed", slog.String("error", err.Error()))
+			}
+			if err != nil || n < d.batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// DeliverDue makes one attempt at up to one batch of due deliveries and
+// returns how many it attempted.
+func (d *Dispatcher) DeliverDue(ctx context.Context) (int, error) {
+	// The lease outlives the attempt, so nobody else picks the delivery up
+	// while it is in flight.
+	lease := d.client.Timeout + time.Minute
+	deliveries, err := d.repo.ClaimDeliveries(ctx, d.now(), lease, d.batchSize)
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.deliver(ctx, delivery)
+		}()
+	}
+	wg.Wait()
+
+	return len(deliveries), err
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery *domain.WebhookDelivery) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("delivery_id", delivery.Id),
+		slog.String("webhook_id", delivery.WebhookId),
+		slog.String("event", delivery.Event),
+	)
+
+	webhook, err := d.repo.GetWebhook(ctx, delivery.WebhookId)
+	if errors.Is(err, repository.ErrNotFound) {
+		// The webhook was deleted after the event was queued.
+		err = d.repo.CompleteDelivery(ctx, delivery.Id)
+	}
+	if err != nil || webhook == nil {
+		if err != nil {
+			logger.Error("failed to load webhook", slog.String("error", err.Error()))
+		}
+		return
+	}
+
+	err = d.post(ctx, webhook, delivery)
+	if err == nil {
+		if err := d.repo.CompleteDelivery(ctx, delivery.Id); err != nil {
+			logger.Error("failed to complete webhook delivery", slog.String("error", err.Error()))
+		}
+		return
+	}
+
+	delivery.Attempts++
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= d.maxAttempts {
+		delivery.Dead = true
+		logger.Warn("webhook delivery dead-lettered", slog.Int("attempts", delivery.Attempts), slog.String("error", err.Error()))
+	} else {
+		delivery.NextAttemptAt = d.now().Add(d.backoff(delivery.Attempts))
+		logger.Info("webhook delivery will be retried", slog.Int("attempts", delivery.Attempts), slog.String("error", err.Error()))
+	}
+	if err := d.repo.FailDelivery(ctx, delivery); err != nil {
+		logger.Error("failed to record webhook delivery failure", slog.String("error", err.Error()))
+	}
+}
+
+// backoff returns the delay after the given number of failed attempts.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.baseBackoff
+	for i := 1; i < attempts && delay < d.maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, d.maxBackoff)
+}
+
+func (d *Dispatcher) post(ctx context.Context, webhook *domain.Webhook, delivery *domain.WebhookDelivery) error {
+	body, err := json.Marshal(struct {
+		ID         string          `json:"id"`
+		Type       string          `json:"type"`
+		OccurredAt time.Time       `json:"occurredAt"`
+		Data       json.RawMessage `json:"data,omitempty"`
+	}{delivery.Id, delivery.Event, time.Unix(delivery.OccurredAt, 0).UTC(), delivery.Data})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderID, delivery.Id)
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, d.now(), body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("receiver answered %s", resp.Status)
+	}
+	return nil
+}
+
+// NewSecret returns a random signing secret.
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Sign returns the X-Webhook-Signature value for body sent at t.
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac(secret, ts, body))
+}
+
+// Verify checks an X-Webhook-Signature value against body. Signatures made
+// more than tolerance away from now are rejected to stop replays; a zero
+// tolerance skips that check.
+func Verify(secret, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var ts string
+	var sigs [][]byte
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			if sig, err := hex.DecodeString(value); err == nil {
+				sigs = append(sigs, sig)
+			}
+		}
+	}
+	if ts == "" || len(sigs) == 0 {
+		return ErrNoSignature
+	}
+
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrNoSignature
+	}
+	if tolerance > 0 {
+		if age := now.Sub(time.Unix(sec, 0)); age > tolerance || age < -tolerance {
+			return ErrSignatureExpired
+		}
+	}
+
+	want := mac(secret, ts, body)
+	for _, sig := range sigs {
+		if hmac.Equal(sig, want) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+func mac(secret, ts string, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(ts))
+	h.Write([]byte("."))
+	h.Write(body)
+	return h.Sum(nil)
+}
@@ -0,0 +1,427 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"grpc-todo/domain"
+	"grpc-todo/repository"
+)
+
+type memoryRepository struct {
+	mu         sync.Mutex
+	nextID     int
+	webhooks   map[string]*domain.Webhook
+	deliveries map[string]*domain.WebhookDelivery
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{
+		webhooks:   make(map[string]*domain.Webhook),
+		deliveries: make(map[string]*domain.WebhookDelivery),
+	}
+}
+
+func (m *memoryRepository) id() string {
+	m.nextID++
+	return fmt.Sprintf("%024x", m.nextID)
+}
+
+func (m *memoryRepository) CreateWebhook(ctx context.Context, w *domain.Webhook) (*domain.Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w.Id = m.id()
+	m.webhooks[w.Id] = w
+	return w, nil
+}
+
+func (m *memoryRepository) ListWebhooks(ctx context.Context, owner string) ([]*domain.Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var res []*domain.Webhook
+	for _, w := range m.webhooks {
+		if w.Owner == owner {
+			res = append(res, w)
+		}
+	}
+	return res, nil
+}
+
+func (m *memoryRepository) GetWebhook(ctx context.Context, id string) (*domain.Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w, ok := m.webhooks[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return w, nil
+}
+
+func (m *memoryRepository) DeleteWebhook(ctx context.Context, owner string, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if w, ok := m.webhooks[id]; !ok || w.Owner != owner {
+		return repository.ErrNotFound
+	}
+	delete(m.webhooks, id)
+	return nil
+}
+
+func (m *memoryRepository) MatchWebhooks(ctx context.Context, owner string, event string) ([]*domain.Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var res []*domain.Webhook
+	for _, w := range m.webhooks {
+		if w.Owner == owner && (len(w.Events) == 0 || slices.Contains(w.Events, event)) {
+			res = append(res, w)
+		}
+	}
+	return res, nil
+}
+
+func (m *memoryRepository) EnqueueDeliveries(ctx context.Context, deliveries []*domain.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, d := range deliveries {
+		if d.Id == "" {
+			d.Id = m.id()
+		} else if _, ok := m.deliveries[d.Id]; ok {
+			continue
+		}
+		stored := *d
+		m.deliveries[d.Id] = &stored
+	}
+	return nil
+}
+
+func (m *memoryRepository) ClaimDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var res []*domain.WebhookDelivery
+	for _, d := range m.deliveries {
+		if len(res) < limit && !d.Dead && !d.NextAttemptAt.After(now) {
+			d.NextAttemptAt = now.Add(lease)
+			claimed := *d
+			res = append(res, &claimed)
+		}
+	}
+	return res, nil
+}
+
+func (m *memoryRepository) CompleteDelivery(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.deliveries, id)
+	return nil
+}
+
+func (m *memoryRepository) FailDelivery(ctx context.Context, d *domain.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := *d
+	m.deliveries[d.Id] = &stored
+	return nil
+}
+
+func (m *memoryRepository) live() []*domain.WebhookDelivery {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var res []*domain.WebhookDelivery
+	for _, d := range m.deliveries {
+		if !d.Dead {
+			res = append(res, d)
+		}
+	}
+	return res
+}
+
+// receiver records verified deliveries and answers with the queued status
+// codes, then 200.
+type receiver struct {
+	t      *testing.T
+	secret string
+
+	mu       sync.Mutex
+	statuses []int
+	bodies   []map[string]any
+	ids      []string
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	// The dispatcher runs on a fake clock, so only the MAC is checked here.
+	if err := Verify(rc.secret, r.Header.Get(HeaderSignature), body, time.Now(), 0); err != nil {
+		rc.t.Errorf("Expected a valid signature, got %v", err)
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	var payload map[string]any
+	json.Unmarshal(body, &payload)
+	rc.bodies = append(rc.bodies, payload)
+	rc.ids = append(rc.ids, r.Header.Get(HeaderID))
+
+	code := http.StatusOK
+	if len(rc.statuses) > 0 {
+		code, rc.statuses = rc.statuses[0], rc.statuses[1:]
+	}
+	w.WriteHeader(code)
+}
+
+// allowLoopback lets deliveries reach the test receivers.
+var allowLoopback = WithAllowedNetworks(netip.MustParsePrefix("127.0.0.0/8"))
+
+func setup(t *testing.T, events []string, statuses ...int) (*memoryRepository, *receiver, *httptest.Server) {
+	rc := &receiver{t: t, secret: "s3cret", statuses: statuses}
+	srv := httptest.NewServer(rc)
+	t.Cleanup(srv.Close)
+
+	repo := newMemoryRepository()
+	repo.CreateWebhook(context.Background(), &domain.Webhook{Owner: "alice", URL: srv.URL, Secret: rc.secret, Events: events})
+	return repo, rc, srv
+}
+
+// deliverAll runs DeliverDue, moving the clock past any backoff, until the
+// outbox has no live deliveries left.
+func deliverAll(t *testing.T, repo *memoryRepository, d *Dispatcher, clock *time.Time) {
+	for i := 0; i < 20; i++ {
+		if _, err := d.DeliverDue(context.Background()); err != nil {
+			t.Fatalf("DeliverDue failed: %v", err)
+		}
+		if len(repo.live()) == 0 {
+			return
+		}
+		*clock = clock.Add(time.Hour)
+	}
+	t.Fatal("Expected the outbox to drain")
+}
+
+func TestDispatcherDelivers(t *testing.T) {
+	repo, rc, _ := setup(t, []string{domain.EventTaskCreated})
+	d := NewDispatcher(repo, allowLoopback)
+
+	ctx := context.Background()
+	if err := d.Emit(ctx, Event{Type: domain.EventTaskCreated, Owner: "alice", Data: json.RawMessage(`{"id":"1"}`)}); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	// Filtered out by event type and by owner.
+	d.Emit(ctx, Event{Type: domain.EventTaskDeleted, Owner: "alice"})
+	d.Emit(ctx, Event{Type: domain.EventTaskCreated, Owner: "bob"})
+
+	if len(repo.deliveries) != 1 {
+		t.Fatalf("Expected 1 queued delivery, got %d", len(repo.deliveries))
+	}
+
+	n, err := d.DeliverDue(ctx)
+	if err != nil || n != 1 {
+		t.Fatalf("Expected 1 delivery attempt, got %d (%v)", n, err)
+	}
+	if len(rc.bodies) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(rc.bodies))
+	}
+	body := rc.bodies[0]
+	if body["type"] != domain.EventTaskCreated || body["id"] != rc.ids[0] {
+		t.Errorf("Expected a task.created payload with the delivery ID, got %v", body)
+	}
+	if data, _ := body["data"].(map[string]any); data["id"] != "1" {
+		t.Errorf("Expected the event data, got %v", body["data"])
+	}
+	if len(repo.deliveries) != 0 {
+		t.Errorf("Expected the outbox to be empty, got %d entries", len(repo.deliveries))
+	}
+}
+
+func TestDispatcherEmitsEventOnce(t *testing.T) {
+	repo, rc, _ := setup(t, nil)
+	d := NewDispatcher(repo, allowLoopback)
+	ctx := context.Background()
+	occurred := time.Unix(1735689600, 0)
+	e := Event{ID: "65f000000000000000000001", Type: domain.EventTaskCreated, Owner: "alice", OccurredAt: occurred}
+
+	for range 2 {
+		if err := d.Emit(ctx, e); err != nil {
+			t.Fatalf("Emit failed: %v", err)
+		}
+	}
+	if len(repo.deliveries) != 1 {
+		t.Fatalf("Expected the event to be queued once, got %d deliveries", len(repo.deliveries))
+	}
+	for _, delivery := range repo.deliveries {
+		if delivery.OccurredAt != occurred.Unix() {
+			t.Errorf("Expected the event time to be kept, got %d", delivery.OccurredAt)
+		}
+	}
+
+	// Emitted again after it was delivered, it keeps its delivery ID.
+	d.DeliverDue(ctx)
+	d.Emit(ctx, e)
+	d.DeliverDue(ctx)
+	if len(rc.ids) != 2 || rc.ids[0] != rc.ids[1] {
+		t.Errorf("Expected 2 requests with the same delivery ID, got %v", rc.ids)
+	}
+}
+
+func TestDispatcherRetries(t *testing.T) {
+	repo, rc, _ := setup(t, nil, http.StatusInternalServerError, http.StatusServiceUnavailable)
+	clock := time.Now()
+	d := NewDispatcher(repo, WithMaxAttempts(5), allowLoopback)
+	d.now = func() time.Time { return clock }
+
+	d.Emit(context.Background(), Event{Type: domain.EventTaskDeleted, Owner: "alice"})
+
+	d.DeliverDue(context.Background())
+	live := repo.live()
+	if len(live) != 1 || live[0].Attempts != 1 || live[0].LastError == "" {
+		t.Fatalf("Expected a failed attempt to be recorded, got %+v", live)
+	}
+	delivery := live[0]
+	if want := clock.Add(10 * time.Second); !delivery.NextAttemptAt.Equal(want) {
+		t.Errorf("Expected the retry at %v, got %v", want, delivery.NextAttemptAt)
+	}
+
+	if n, _ := d.DeliverDue(context.Background()); n != 0 {
+		t.Errorf("Expected no attempt before the backoff elapsed, got %d", n)
+	}
+
+	deliverAll(t, repo, d, &clock)
+	if len(rc.ids) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(rc.ids))
+	}
+	if rc.ids[0] != rc.ids[2] {
+		t.Errorf("Expected retries to keep the delivery ID, got %q and %q", rc.ids[0], rc.ids[2])
+	}
+	if len(repo.deliveries) != 0 {
+		t.Errorf("Expected the outbox to be empty, got %d entries", len(repo.deliveries))
+	}
+}
+
+func TestDispatcherDeadLetters(t *testing.T) {
+	failures := []int{500, 500, 500, 500}
+	repo, rc, _ := setup(t, nil, failures...)
+	clock := time.Now()
+	d := NewDispatcher(repo, WithMaxAttempts(3), allowLoopback)
+	d.now = func() time.Time { return clock }
+
+	d.Emit(context.Background(), Event{Type: domain.EventTaskCreated, Owner: "alice"})
+	deliverAll(t, repo, d, &clock)
+
+	if len(rc.ids) != 3 {
+		t.Errorf("Expected 3 attempts, got %d", len(rc.ids))
+	}
+	if len(repo.deliveries) != 1 {
+		t.Fatalf("Expected the delivery to stay in the outbox, got %d entries", len(repo.deliveries))
+	}
+	for _, delivery := range repo.deliveries {
+		if !delivery.Dead || delivery.Attempts != 3 {
+			t.Errorf("Expected a dead delivery after 3 attempts, got %+v", delivery)
+		}
+	}
+}
+
+func TestDispatcherDropsDeletedWebhook(t *testing.T) {
+	repo, rc, _ := setup(t, nil)
+	d := NewDispatcher(repo)
+
+	d.Emit(context.Background(), Event{Type: domain.EventTaskCreated, Owner: "alice"})
+	for id := range repo.webhooks {
+		repo.DeleteWebhook(context.Background(), "alice", id)
+	}
+	d.DeliverDue(context.Background())
+
+	if len(rc.ids) != 0 || len(repo.deliveries) != 0 {
+		t.Errorf("Expected the delivery to be dropped, got %d requests and %d entries", len(rc.ids), len(repo.deliveries))
+	}
+}
+
+func TestDispatcherRefusesInternalAddresses(t *testing.T) {
+	repo, rc, _ := setup(t, nil)
+	d := NewDispatcher(repo)
+
+	d.Emit(context.Background(), Event{Type: domain.EventTaskCreated, Owner: "alice"})
+	d.DeliverDue(context.Background())
+
+	live := repo.live()
+	if len(rc.ids) != 0 || len(live) != 1 || !strings.Contains(live[0].LastError, ErrForbiddenAddress.Error()) {
+		t.Errorf("Expected the loopback receiver to be refused, got %d requests and %+v", len(rc.ids), live)
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	d := NewDispatcher(nil)
+	for _, raw := range []string{
+		"http://127.0.0.1:27017",
+		"http://169.254.169.254/latest/meta-data/",
+		"https://10.0.0.5/hook",
+		"https://192.168.1.1/hook",
+		"http://[::1]:8080/hook",
+		"http://[::ffff:127.0.0.1]/hook",
+		"http://[fd00::1]/hook",
+		"http://0.0.0.0/hook",
+		"http://100.64.0.1/hook",
+		"http://localhost:8080/hook",
+	} {
+		if err := d.CheckURL(context.Background(), raw); !errors.Is(err, ErrForbiddenAddress) {
+			t.Errorf("Expected %s to be refused, got %v", raw, err)
+		}
+	}
+	if err := d.CheckURL(context.Background(), "ftp://93.184.215.14/hook"); err == nil {
+		t.Error("Expected an unsupported scheme to be refused")
+	}
+	if err := d.CheckURL(context.Background(), "https://93.184.215.14/hook"); err != nil {
+		t.Errorf("Expected a public address to be allowed, got %v", err)
+	}
+
+	d = NewDispatcher(nil, WithAllowedNetworks(netip.MustParsePrefix("10.0.0.0/8")))
+	if err := d.CheckURL(context.Background(), "https://10.0.0.5/hook"); err != nil {
+		t.Errorf("Expected an allowed network to be accepted, got %v", err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	d := NewDispatcher(nil, WithBackoff(time.Second, 10*time.Second))
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}
+	for i, w := range want {
+		if got := d.backoff(i + 1); got != w {
+			t.Errorf("Expected backoff %v after %d failures, got %v", w, i+1, got)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Now()
+	body := []byte(`{"type":"task.created"}`)
+	header := Sign("key", now, body)
+
+	if err := Verify("key", header, body, now, time.Minute); err != nil {
+		t.Errorf("Expected a valid signature, got %v", err)
+	}
+	tests := []struct {
+		name   string
+		secret string
+		header string
+		body   string
+		now    time.Time
+		want   error
+	}{
+		{"wrong secret", "other", header, string(body), now, ErrInvalidSignature},
+		{"tampered body", "key", header, `{"type":"task.deleted"}`, now, ErrInvalidSignature},
+		{"replayed", "key", header, string(body), now.Add(time.Hour), ErrSignatureExpired},
+		{"missing", "key", "", string(body), now, ErrNoSignature},
+	}
+	for _, tt := range tests {
+		if err := Verify(tt.secret, tt.header, []byte(tt.body), tt.now, time.Minute); !errors.Is(err, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+	}
+}